@@ -0,0 +1,55 @@
+// cmd/iconpkg/main.go
+//
+// iconpkg genera el paquete Go de los iconos a partir de una configuración
+// JSON. Pensado para go:generate, que lo ejecuta en el directorio del paquete
+// que contiene la directiva, por lo que las rutas relativas parten de ahí:
+//
+//    //go:generate go run iconexporter/cmd/iconpkg -config icons.json -out ./icons
+package main
+
+import (
+    "encoding/json"
+    "flag"
+    "fmt"
+    "os"
+
+    "iconexporter/iconexporter"
+)
+
+func main() {
+    configPath := flag.String("config", "", "archivo JSON con la configuración del exportador")
+    outputDir := flag.String("out", "", "directorio del paquete generado (sustituye a goPackage.outputDir)")
+    packageName := flag.String("package", "", "nombre del paquete generado (sustituye a goPackage.packageName)")
+    flag.Parse()
+    
+    if *configPath == "" || flag.NArg() > 0 {
+        flag.Usage()
+        os.Exit(2)
+    }
+    
+    data, err := os.ReadFile(*configPath)
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "iconpkg: %v\n", err)
+        os.Exit(1)
+    }
+    var config iconexporter.Config
+    if err := json.Unmarshal(data, &config); err != nil {
+        fmt.Fprintf(os.Stderr, "iconpkg: %s: %v\n", *configPath, err)
+        os.Exit(1)
+    }
+    if *outputDir != "" {
+        config.GoPackage.OutputDir = *outputDir
+    }
+    if *packageName != "" {
+        config.GoPackage.PackageName = *packageName
+    }
+    
+    summary, err := iconexporter.ExportGoPackage(config)
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "iconpkg: %v\n", err)
+        os.Exit(1)
+    }
+    if summary.Errors > 0 {
+        os.Exit(1)
+    }
+}
@@ -4,7 +4,7 @@ package main
 import (
     "fmt"
     "os"
-    "iconexporter"
+    "iconexporter/iconexporter"
 )
 
 func main() {
@@ -4,6 +4,7 @@ module iconexporter
 go 1.21
 
 require (
+    github.com/andybalholm/brotli v1.1.1
     github.com/disintegration/imaging v1.6.2
     github.com/srwiley/oksvg v0.0.0-20221011165216-be6e8873101c
     github.com/srwiley/rasterx v0.0.0-20220730225603-2ab79fcdd4ef
@@ -14,5 +15,3 @@ require (
     golang.org/x/net v0.20.0 // indirect
     golang.org/x/text v0.14.0 // indirect
 )
-
-go 1.25.5
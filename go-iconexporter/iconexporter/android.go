@@ -0,0 +1,250 @@
+// iconexporter/android.go
+package iconexporter
+
+import (
+    "fmt"
+    "image/color"
+    "io"
+    "path/filepath"
+    "regexp"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// Densidades de Android y su factor respecto a mdpi
+var AndroidDensities = []struct {
+    Name  string
+    Scale float64
+}{
+    {"mdpi", 1},
+    {"hdpi", 1.5},
+    {"xhdpi", 2},
+    {"xxhdpi", 3},
+    {"xxxhdpi", 4},
+}
+
+// Medidas de los iconos adaptativos: capa de 108dp con zona visible de 72dp
+const (
+    androidAdaptiveLayerDp = 108
+    androidAdaptiveSafeDp  = 72
+)
+
+var androidResourceName = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// AndroidConfig configura la salida para Android
+type AndroidConfig struct {
+    ResourceName    string `json:"resourceName"`
+    BackgroundColor string `json:"backgroundColor"`
+}
+
+// parseViewBox interpreta un viewBox "minX minY ancho alto"
+func parseViewBox(viewBox string) ([4]float64, error) {
+    var box [4]float64
+    fields := strings.FieldsFunc(viewBox, func(r rune) bool { return r == ' ' || r == ',' })
+    if len(fields) != 4 {
+        return box, fmt.Errorf("viewBox no válido: %q", viewBox)
+    }
+    for i, field := range fields {
+        value, err := strconv.ParseFloat(field, 64)
+        if err != nil {
+            return box, fmt.Errorf("viewBox no válido: %q", viewBox)
+        }
+        box[i] = value
+    }
+    return box, nil
+}
+
+// expandViewBox agranda el viewBox para que el contenido ocupe solo la
+// fracción indicada del lienzo, centrado
+func expandViewBox(viewBox string, contentRatio float64) (string, error) {
+    box, err := parseViewBox(viewBox)
+    if err != nil {
+        return "", err
+    }
+    width, height := box[2]/contentRatio, box[3]/contentRatio
+    minX := box[0] - (width-box[2])/2
+    minY := box[1] - (height-box[3])/2
+    return fmt.Sprintf("%g %g %g %g", minX, minY, width, height), nil
+}
+
+// ExportAndroid genera los PNG de mipmap-mdpi a mipmap-xxxhdpi para un icono de
+// baseDp, más el icono adaptativo (capa frontal en PNG y fondo como recurso de color)
+func (e *IconExporter) ExportAndroid(collection, iconName string, baseDp int, col string) (ExportSummary, error) {
+    startTime := time.Now()
+    var results []ExportResult
+    
+    resourceName := e.config.Android.ResourceName
+    if resourceName == "" {
+        resourceName = "ic_launcher"
+    }
+    if !androidResourceName.MatchString(resourceName) {
+        return ExportSummary{}, fmt.Errorf("nombre de recurso Android no válido: %s", resourceName)
+    }
+    background := e.config.Android.BackgroundColor
+    if background == "" {
+        background = "#FFFFFF"
+    }
+    if col == "" {
+        col = e.config.DefaultColor
+    }
+    if baseDp <= 0 {
+        baseDp = e.config.DefaultSize[0]
+    }
+    
+    iconData, err := e.loadCollectionData(collection)
+    if err != nil {
+        return ExportSummary{}, err
+    }
+    icon, exists := iconData.Icons[iconName]
+    if !exists {
+        return ExportSummary{}, fmt.Errorf("icono '%s' no encontrado en %s", iconName, collection)
+    }
+    
+    // La capa frontal reserva el margen de 18dp alrededor de la zona segura
+    foreground := icon
+    if foreground.ViewBox, err = expandViewBox(icon.ViewBox, float64(androidAdaptiveSafeDp)/androidAdaptiveLayerDp); err != nil {
+        return ExportSummary{}, err
+    }
+    
+    resDir := filepath.Join(e.config.OutputDir, "android", "res")
+    // La capa frontal solo cambia el viewBox: un único análisis sirve para ambas
+    preflight := e.rasterPreflight(e.prepareSvgBuffer(icon, icon.Width, icon.Height, col, ""))
+    save := func(folder, fileName string, input EncodeInput) {
+        folderPath := filepath.Join(resDir, folder)
+        filePath := filepath.Join(folderPath, fileName)
+        result := ExportResult{
+            Collection: collection,
+            Icon:       iconName,
+            Format:     "png",
+            Path:       filePath,
+            Width:      input.Width,
+            Height:     input.Height,
+            Color:      col,
+        }
+        if !preflight(&result) {
+            results = append(results, result)
+            return
+        }
+        input.Warn = func(message string) {
+            result.Warnings = append(result.Warnings, message)
+        }
+        err := e.ensureOutputDir(folderPath)
+        if err == nil {
+            err = e.saveImage(input, filePath, "png")
+        }
+        if err != nil {
+            fmt.Printf("❌ Error al guardar %s para '%s': %v\n", filePath, iconName, err)
+            result.Error = err.Error()
+        } else {
+            fmt.Printf("✅ Exportado: %s\n", filePath)
+        }
+        printWarnings(result)
+        results = append(results, result)
+    }
+    writeXML := func(folder, fileName, content string) {
+        folderPath := filepath.Join(resDir, folder)
+        filePath := filepath.Join(folderPath, fileName)
+        result := ExportResult{Collection: collection, Icon: iconName, Format: "xml", Path: filePath, Color: col}
+        err := e.ensureOutputDir(folderPath)
+        if err == nil {
+            err = writeFile(filePath, func(w io.Writer) error {
+                _, err := io.WriteString(w, content)
+                return err
+            })
+        }
+        if err != nil {
+            fmt.Printf("❌ Error al guardar %s para '%s': %v\n", filePath, iconName, err)
+            result.Error = err.Error()
+        } else {
+            fmt.Printf("✅ Exportado: %s\n", filePath)
+        }
+        results = append(results, result)
+    }
+    
+    fmt.Printf("\n🤖 Generando recursos Android: %s:%s (%ddp)\n", collection, iconName, baseDp)
+    
+    for _, density := range AndroidDensities {
+        folder := "mipmap-" + density.Name
+        
+        size := int(float64(baseDp)*density.Scale + 0.5)
+        save(folder, resourceName+".png", EncodeInput{
+            Collection: collection,
+            Icon:       iconName,
+            Color:      col,
+            Width:      size,
+            Height:     size,
+            SVG:        e.prepareSvgBuffer(icon, size, size, col, e.svgIDPrefix(collection, iconName)),
+        })
+        
+        layerSize := int(androidAdaptiveLayerDp*density.Scale + 0.5)
+        save(folder, resourceName+"_foreground.png", EncodeInput{
+            Collection: collection,
+            Icon:       iconName,
+            Color:      col,
+            Width:      layerSize,
+            Height:     layerSize,
+            SVG:        e.prepareSvgBuffer(foreground, layerSize, layerSize, col, e.svgIDPrefix(collection, iconName)),
+        })
+    }
+    
+    // Icono adaptativo (API 26+) y su variante redonda
+    adaptive := fmt.Sprintf(`<?xml version="1.0" encoding="utf-8"?>
+<adaptive-icon xmlns:android="http://schemas.android.com/apk/res/android">
+    <background android:drawable="@color/%[1]s_background"/>
+    <foreground android:drawable="@mipmap/%[1]s_foreground"/>
+</adaptive-icon>
+`, resourceName)
+    writeXML("mipmap-anydpi-v26", resourceName+".xml", adaptive)
+    writeXML("mipmap-anydpi-v26", resourceName+"_round.xml", adaptive)
+    writeXML("values", resourceName+"_background.xml", fmt.Sprintf(`<?xml version="1.0" encoding="utf-8"?>
+<resources>
+    <color name="%s_background">%s</color>
+</resources>
+`, resourceName, androidColor(background)))
+    
+    processed, errors, skipped := 0, 0, 0
+    for _, result := range results {
+        switch {
+        case result.Skipped:
+            skipped++
+        case result.Error != "":
+            errors++
+        default:
+            processed++
+        }
+    }
+    
+    duration := time.Since(startTime).Seconds()
+    if skipped > 0 {
+        fmt.Printf("\n⏭️ Omitidos por el análisis previo: %d\n", skipped)
+    }
+    e.printExportSummary(processed, errors, duration)
+    
+    return ExportSummary{
+        Processed: processed,
+        Errors:    errors,
+        Skipped:   skipped,
+        Duration:  duration,
+        Results:   results,
+        Sanitized: e.sanitizeReports(),
+    }, nil
+}
+
+// androidColor convierte un color SVG al formato #RRGGBB / #AARRGGBB de Android
+func androidColor(value string) string {
+    c, err := parseColor(value)
+    if err != nil {
+        return value
+    }
+    return androidColorValue(color.NRGBAModel.Convert(c).(color.NRGBA))
+}
+
+// ExportAndroidIcon genera los recursos Android de un icono con la configuración dada
+func ExportAndroidIcon(config Config, collection, iconName string, baseDp int, color string) (ExportSummary, error) {
+    exporter, err := NewIconExporter(config)
+    if err != nil {
+        return ExportSummary{}, err
+    }
+    return exporter.ExportAndroid(collection, iconName, baseDp, color)
+}
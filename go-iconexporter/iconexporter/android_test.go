@@ -0,0 +1,128 @@
+// iconexporter/android_test.go
+package iconexporter
+
+import (
+    "image/png"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+func TestExpandViewBox(t *testing.T) {
+    tests := []struct {
+        viewBox string
+        ratio   float64
+        want    string
+        wantErr bool
+    }{
+        {"0 0 24 24", 1, "0 0 24 24", false},
+        {"0 0 24 24", 0.5, "-12 -12 48 48", false},
+        {"0,0,72,36", 72.0 / 108, "-18 -9 108 54", false},
+        {"10 10 20", 0.5, "", true},
+        {"0 0 a 24", 0.5, "", true},
+    }
+    for _, tt := range tests {
+        got, err := expandViewBox(tt.viewBox, tt.ratio)
+        if (err != nil) != tt.wantErr || got != tt.want {
+            t.Errorf("expandViewBox(%q, %g) = %q, %v; se esperaba %q", tt.viewBox, tt.ratio, got, err, tt.want)
+        }
+    }
+}
+
+func TestAndroidColor(t *testing.T) {
+    tests := map[string]string{
+        "#fff":           "#FFFFFF",
+        "#0a141e":        "#0A141E",
+        "red":            "#FF0000",
+        "no-es-un-color": "no-es-un-color",
+    }
+    for value, want := range tests {
+        if got := androidColor(value); got != want {
+            t.Errorf("androidColor(%q) = %q, se esperaba %q", value, got, want)
+        }
+    }
+}
+
+func TestExportAndroid(t *testing.T) {
+    e := newTestExporter(t, Config{Android: AndroidConfig{ResourceName: "ic_bell", BackgroundColor: "#0a141e"}})
+    summary, err := e.ExportAndroid("nonicons", "bell", 48, "")
+    if err != nil {
+        t.Fatal(err)
+    }
+    if summary.Processed != 2*len(AndroidDensities)+3 || summary.Errors != 0 {
+        t.Fatalf("resumen inesperado: %+v", summary)
+    }
+    if len(summary.Results) != summary.Processed {
+        t.Fatalf("%d resultados, se esperaban %d", len(summary.Results), summary.Processed)
+    }
+    for _, result := range summary.Results {
+        if _, err := os.Stat(result.Path); err != nil {
+            t.Fatal(err)
+        }
+    }
+    
+    res := filepath.Join(e.config.OutputDir, "android", "res")
+    tests := []struct {
+        density     string
+        icon, layer int
+    }{
+        {"mdpi", 48, 108},
+        {"hdpi", 72, 162},
+        {"xhdpi", 96, 216},
+        {"xxhdpi", 144, 324},
+        {"xxxhdpi", 192, 432},
+    }
+    for _, tt := range tests {
+        t.Run(tt.density, func(t *testing.T) {
+            for file, size := range map[string]int{"ic_bell.png": tt.icon, "ic_bell_foreground.png": tt.layer} {
+                f, err := os.Open(filepath.Join(res, "mipmap-"+tt.density, file))
+                if err != nil {
+                    t.Fatal(err)
+                }
+                img, err := png.Decode(f)
+                f.Close()
+                if err != nil || img.Bounds().Dx() != size || img.Bounds().Dy() != size {
+                    t.Fatalf("%s: %v, se esperaban %d px", file, err, size)
+                }
+                
+                // La capa frontal deja transparente el margen de 18dp
+                if file == "ic_bell_foreground.png" {
+                    margin := size * 18 / 108
+                    if _, _, _, a := img.At(margin/2, margin/2).RGBA(); a != 0 {
+                        t.Fatal("el margen de la capa frontal no es transparente")
+                    }
+                }
+            }
+        })
+    }
+    
+    adaptive, _ := os.ReadFile(filepath.Join(res, "mipmap-anydpi-v26", "ic_bell_round.xml"))
+    if !strings.Contains(string(adaptive), `@mipmap/ic_bell_foreground`) {
+        t.Fatalf("icono adaptativo inesperado:\n%s", adaptive)
+    }
+    values, _ := os.ReadFile(filepath.Join(res, "values", "ic_bell_background.xml"))
+    if !strings.Contains(string(values), `<color name="ic_bell_background">#0A141E</color>`) {
+        t.Fatalf("color de fondo inesperado:\n%s", values)
+    }
+}
+
+func TestExportAndroidErrors(t *testing.T) {
+    tests := []struct {
+        name     string
+        resource string
+        icon     string
+    }{
+        {"recurso con mayúsculas", "IcLauncher", "bell"},
+        {"recurso con guiones", "ic-launcher", "bell"},
+        {"icono inexistente", "", "campana"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            e := newTestExporter(t, Config{Android: AndroidConfig{ResourceName: tt.resource}})
+            if _, err := e.ExportAndroid("nonicons", tt.icon, 48, ""); err == nil {
+                t.Fatal("se esperaba un error")
+            }
+        })
+    }
+}
@@ -0,0 +1,359 @@
+// iconexporter/atlas.go
+package iconexporter
+
+import (
+    "encoding/json"
+    "fmt"
+    "image"
+    "io"
+    "math"
+    "path/filepath"
+    "sort"
+    "strings"
+
+    "github.com/disintegration/imaging"
+)
+
+// AtlasConfig configura las hojas de sprites raster (texture atlas)
+type AtlasConfig struct {
+    Enabled     bool   `json:"enabled"`
+    FileName    string `json:"fileName"`
+    Padding     int    `json:"padding"`
+    MaxSize     int    `json:"maxSize"`
+    ClassPrefix string `json:"classPrefix"`
+}
+
+// atlasPlacement es la posición de un rectángulo dentro de una hoja
+type atlasPlacement struct {
+    Sheet int
+    X, Y  int
+}
+
+// atlasRect, atlasSize y atlasFrame siguen el formato JSON (hash) de TexturePacker
+type atlasRect struct {
+    X int `json:"x"`
+    Y int `json:"y"`
+    W int `json:"w"`
+    H int `json:"h"`
+}
+
+type atlasSize struct {
+    W int `json:"w"`
+    H int `json:"h"`
+}
+
+type atlasFrame struct {
+    Frame            atlasRect `json:"frame"`
+    Rotated          bool      `json:"rotated"`
+    Trimmed          bool      `json:"trimmed"`
+    SpriteSourceSize atlasRect `json:"spriteSourceSize"`
+    SourceSize       atlasSize `json:"sourceSize"`
+}
+
+type atlasMeta struct {
+    App     string    `json:"app"`
+    Version string    `json:"version"`
+    Image   string    `json:"image"`
+    Format  string    `json:"format"`
+    Size    atlasSize `json:"size"`
+    Scale   string    `json:"scale"`
+}
+
+type atlasJSON struct {
+    Frames map[string]atlasFrame `json:"frames"`
+    Meta   atlasMeta             `json:"meta"`
+}
+
+// atlasConfig completa la configuración con valores por defecto
+func (e *IconExporter) atlasConfig() (AtlasConfig, error) {
+    cfg := e.config.Atlas
+    if cfg.FileName == "" {
+        cfg.FileName = "atlas"
+    }
+    if cfg.Padding < 0 {
+        return cfg, fmt.Errorf("padding del atlas no válido: %d", cfg.Padding)
+    }
+    if cfg.MaxSize == 0 {
+        cfg.MaxSize = 2048
+    }
+    if cfg.MaxSize < 0 {
+        return cfg, fmt.Errorf("tamaño máximo del atlas no válido: %d", cfg.MaxSize)
+    }
+    if cfg.ClassPrefix == "" {
+        cfg.ClassPrefix = "icon"
+    }
+    return cfg, nil
+}
+
+// packRects coloca los rectángulos en hojas de como máximo maxSize×maxSize con
+// el algoritmo de estanterías (shelf) por altura decreciente. Devuelve la
+// posición de cada rectángulo y el tamaño de cada hoja.
+func packRects(sizes [][2]int, maxSize, padding int) ([]atlasPlacement, [][2]int, error) {
+    order := make([]int, len(sizes))
+    totalArea := 0
+    widest := 0
+    for i, size := range sizes {
+        if size[0] > maxSize || size[1] > maxSize {
+            return nil, nil, fmt.Errorf("%dx%d no cabe en una hoja de %dx%d", size[0], size[1], maxSize, maxSize)
+        }
+        order[i] = i
+        totalArea += (size[0] + padding) * (size[1] + padding)
+        if size[0] > widest {
+            widest = size[0]
+        }
+    }
+    sort.SliceStable(order, func(a, b int) bool {
+        return sizes[order[a]][1] > sizes[order[b]][1]
+    })
+    
+    // Ancho objetivo: hojas aproximadamente cuadradas
+    width := int(math.Ceil(math.Sqrt(float64(totalArea))))
+    if width < widest {
+        width = widest
+    }
+    if width > maxSize {
+        width = maxSize
+    }
+    
+    placements := make([]atlasPlacement, len(sizes))
+    sheets := [][2]int{{0, 0}}
+    x, y, shelfHeight := 0, 0, 0
+    for _, i := range order {
+        w, h := sizes[i][0], sizes[i][1]
+        if x > 0 && x+w > width {
+            x, y = 0, y+shelfHeight+padding
+            shelfHeight = 0
+        }
+        if y+h > maxSize {
+            sheets = append(sheets, [2]int{0, 0})
+            x, y, shelfHeight = 0, 0, 0
+        }
+        
+        sheet := len(sheets) - 1
+        placements[i] = atlasPlacement{sheet, x, y}
+        sheets[sheet][0] = max(sheets[sheet][0], x+w)
+        sheets[sheet][1] = max(sheets[sheet][1], y+h)
+        x += w + padding
+        shelfHeight = max(shelfHeight, h)
+    }
+    return placements, sheets, nil
+}
+
+// atlasScaleSuffix devuelve el sufijo de archivo de una escala (@2x)
+func atlasScaleSuffix(scale float64) string {
+    if scale == 1 {
+        return ""
+    }
+    return "@" + formatNumber(scale, 2) + "x"
+}
+
+// exportAtlases empaqueta los iconos de cada tamaño y color en hojas PNG con su
+// CSS y JSON de TexturePacker. Las posiciones se calculan en 1x y se escalan
+// para las variantes retina, de modo que el mismo CSS sirve para todas.
+func (e *IconExporter) exportAtlases(entries []iconEntry, sizes [][2]int, colors []string) []ExportResult {
+    cfg, _ := e.atlasConfig() // validada en validateConfig
+    
+    scales := []float64{1}
+    for _, scale := range e.config.ScaleFactors {
+        if scale != 1 {
+            scales = append(scales, scale)
+        }
+    }
+    sort.Float64s(scales[1:])
+    
+    // Análisis previo de cada icono; el color no cambia el resultado, así que
+    // se hace una sola vez. Los iconos omitidos o fallidos se quedan fuera de
+    // las hojas y cada hoja reúne los avisos de los que contiene
+    preflight, _ := e.preflightConfig() // validada en validateConfig
+    entryIssues := make([][]string, len(entries))
+    if preflight.Mode != "off" {
+        for i, entry := range entries {
+            for _, issue := range preflightSvg(e.prepareSvgBuffer(entry.Icon, entry.Icon.Width, entry.Icon.Height, e.config.DefaultColor, "")) {
+                entryIssues[i] = append(entryIssues[i], entry.Collection+":"+entry.Name+": "+issue)
+            }
+        }
+    }
+    
+    var results []ExportResult
+    // issues solo se pasa en las hojas PNG, que son las que se rasterizan
+    save := func(fileName, format string, size [2]int, col string, issues []string, write func(w io.Writer) error) {
+        filePath := filepath.Join(e.config.OutputDir, fileName)
+        result := ExportResult{Format: format, Path: filePath, Width: size[0], Height: size[1], Color: col, Warnings: issues}
+        if err := writeFile(filePath, write); err != nil {
+            fmt.Printf("❌ Error al guardar %s: %v\n", filePath, err)
+            result.Error = err.Error()
+        } else {
+            fmt.Printf("✅ Exportado: %s\n", filePath)
+        }
+        printWarnings(result)
+        results = append(results, result)
+    }
+    
+    for _, size := range sizes {
+        for _, col := range colors {
+            groupName := fmt.Sprintf("%s-%dx%d", cfg.FileName, size[0], size[1])
+            if len(colors) > 1 {
+                groupName += "-" + strings.ReplaceAll(col, "#", "")
+            }
+            
+            var packed []iconEntry
+            var packedIssues [][]string
+            for i, entry := range entries {
+                result := ExportResult{
+                    Collection: entry.Collection,
+                    Icon:       entry.Name,
+                    Format:     "atlas",
+                    Path:       filepath.Join(e.config.OutputDir, groupName+".png"),
+                    Width:      size[0],
+                    Height:     size[1],
+                    Color:      col,
+                }
+                if !applyPreflight(preflight.Mode, entryIssues[i], &result) {
+                    reportSkippedOrFailed(result)
+                    results = append(results, result)
+                    continue
+                }
+                packed = append(packed, entry)
+                packedIssues = append(packedIssues, entryIssues[i])
+            }
+            if len(packed) == 0 {
+                continue
+            }
+            
+            rects := make([][2]int, len(packed))
+            ids := make([]string, len(packed))
+            for i, entry := range packed {
+                rects[i] = size
+                ids[i] = e.spriteID("{collection}-{icon}", entry.Collection, entry.Name, col, len(colors) > 1)
+            }
+            placements, sheets, err := packRects(rects, cfg.MaxSize, cfg.Padding)
+            if err != nil {
+                fmt.Printf("❌ Error empaquetando %s: %v\n", groupName, err)
+                filePath := filepath.Join(e.config.OutputDir, groupName+".png")
+                results = append(results, ExportResult{Format: "atlas", Path: filePath, Width: size[0], Height: size[1], Color: col, Error: err.Error()})
+                continue
+            }
+            
+            sheetName := func(sheet int, scale float64) string {
+                name := groupName
+                if len(sheets) > 1 {
+                    name = fmt.Sprintf("%s-%d", name, sheet)
+                }
+                return name + atlasScaleSuffix(scale)
+            }
+            
+            // Hojas PNG y JSON por escala. Cada celda va de px(x) a px(x+ancho):
+            // con escalas fraccionarias su tamaño varía en un píxel, pero las
+            // celdas vecinas no se solapan y el CSS de 1x sigue sirviendo
+            for _, scale := range scales {
+                px := func(v int) int { return int(float64(v)*scale + 0.5) }
+                canvases := make([]*image.NRGBA, len(sheets))
+                frames := make([]map[string]atlasFrame, len(sheets))
+                for i, sheet := range sheets {
+                    canvases[i] = image.NewNRGBA(image.Rect(0, 0, px(sheet[0]), px(sheet[1])))
+                    frames[i] = map[string]atlasFrame{}
+                }
+                
+                var renderErr error
+                for i, entry := range packed {
+                    p := placements[i]
+                    x, y := px(p.X), px(p.Y)
+                    iconW, iconH := px(p.X+size[0])-x, px(p.Y+size[1])-y
+                    img, err := e.rasterizeSvg(e.prepareSvgBuffer(entry.Icon, iconW, iconH, col, ""), iconW, iconH)
+                    if err != nil {
+                        renderErr = fmt.Errorf("%s:%s: %w", entry.Collection, entry.Name, err)
+                        break
+                    }
+                    canvases[p.Sheet] = imaging.Overlay(canvases[p.Sheet], img, image.Pt(x, y), 1.0)
+                    frames[p.Sheet][ids[i]+".png"] = atlasFrame{
+                        Frame:            atlasRect{x, y, iconW, iconH},
+                        SpriteSourceSize: atlasRect{0, 0, iconW, iconH},
+                        SourceSize:       atlasSize{iconW, iconH},
+                    }
+                }
+                
+                for i, sheet := range sheets {
+                    imageName := sheetName(i, scale) + ".png"
+                    canvas := canvases[i]
+                    var issues []string
+                    for j, p := range placements {
+                        if p.Sheet == i {
+                            issues = append(issues, packedIssues[j]...)
+                        }
+                    }
+                    save(imageName, "atlas", size, col, issues, func(w io.Writer) error {
+                        if renderErr != nil {
+                            return renderErr
+                        }
+                        return encodePNG(w, canvas, map[string]interface{}{"dpi": int(72*scale + 0.5)})
+                    })
+                    atlas := atlasJSON{
+                        Frames: frames[i],
+                        Meta: atlasMeta{
+                            App:     "iconexporter",
+                            Version: "1.0",
+                            Image:   imageName,
+                            Format:  "RGBA8888",
+                            Size:    atlasSize{px(sheet[0]), px(sheet[1])},
+                            Scale:   formatNumber(scale, 2),
+                        },
+                    }
+                    save(sheetName(i, scale)+".json", "json", size, col, nil, func(w io.Writer) error {
+                        encoder := json.NewEncoder(w)
+                        encoder.SetIndent("", "  ")
+                        return encoder.Encode(atlas)
+                    })
+                }
+            }
+            
+            save(groupName+".css", "css", size, col, nil, func(w io.Writer) error {
+                _, err := io.WriteString(w, atlasCSS(cfg.ClassPrefix, ids, placements, sheets, size, scales, sheetName))
+                return err
+            })
+        }
+    }
+    
+    return results
+}
+
+// atlasCSS genera una clase por icono con su background-position y, para cada
+// escala retina, una media query que cambia la imagen de la hoja
+func atlasCSS(prefix string, ids []string, placements []atlasPlacement, sheets [][2]int, size [2]int, scales []float64, sheetName func(int, float64) string) string {
+    var b strings.Builder
+    bySheet := make([][]string, len(sheets))
+    for i, id := range ids {
+        p := placements[i]
+        bySheet[p.Sheet] = append(bySheet[p.Sheet], "."+prefix+"-"+id)
+    }
+    
+    for sheet, selectors := range bySheet {
+        fmt.Fprintf(&b, "%s {\n", strings.Join(selectors, ",\n"))
+        fmt.Fprintf(&b, "  display: inline-block;\n  width: %dpx;\n  height: %dpx;\n", size[0], size[1])
+        fmt.Fprintf(&b, "  background-image: url(\"%s.png\");\n", sheetName(sheet, 1))
+        fmt.Fprintf(&b, "  background-repeat: no-repeat;\n")
+        fmt.Fprintf(&b, "  background-size: %dpx %dpx;\n}\n\n", sheets[sheet][0], sheets[sheet][1])
+    }
+    for i, id := range ids {
+        p := placements[i]
+        fmt.Fprintf(&b, ".%s-%s { background-position: %s %s; }\n", prefix, id, cssOffset(p.X), cssOffset(p.Y))
+    }
+    
+    for _, scale := range scales[1:] {
+        fmt.Fprintf(&b, "\n@media (-webkit-min-device-pixel-ratio: %s), (min-resolution: %sdpi) {\n",
+            formatNumber(scale, 2), formatNumber(96*scale, 0))
+        for sheet, selectors := range bySheet {
+            fmt.Fprintf(&b, "  %s {\n    background-image: url(\"%s.png\");\n  }\n",
+                strings.Join(selectors, ",\n  "), sheetName(sheet, scale))
+        }
+        b.WriteString("}\n")
+    }
+    return b.String()
+}
+
+// cssOffset escribe un desplazamiento de background-position
+func cssOffset(v int) string {
+    if v == 0 {
+        return "0"
+    }
+    return fmt.Sprintf("-%dpx", v)
+}
@@ -0,0 +1,163 @@
+// iconexporter/atlas_test.go
+package iconexporter
+
+import (
+    "encoding/json"
+    "fmt"
+    "image"
+    "image/png"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+func TestPackRects(t *testing.T) {
+    tests := []struct {
+        name    string
+        sizes   [][2]int
+        maxSize int
+        padding int
+        sheets  int
+        wantErr bool
+    }{
+        {"uno", [][2]int{{10, 10}}, 64, 0, 1, false},
+        {"cinco con margen", [][2]int{{10, 10}, {10, 10}, {10, 10}, {10, 10}, {10, 10}}, 64, 2, 1, false},
+        {"alturas distintas", [][2]int{{8, 4}, {4, 12}, {16, 16}, {2, 2}}, 64, 1, 1, false},
+        {"varias hojas", [][2]int{{10, 10}, {10, 10}, {10, 10}, {10, 10}, {10, 10}}, 24, 2, 2, false},
+        {"no cabe", [][2]int{{10, 10}, {30, 10}}, 24, 0, 0, true},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            placements, sheets, err := packRects(tt.sizes, tt.maxSize, tt.padding)
+            if (err != nil) != tt.wantErr {
+                t.Fatalf("error %v, se esperaba error: %v", err, tt.wantErr)
+            }
+            if err != nil {
+                return
+            }
+            if len(sheets) != tt.sheets {
+                t.Fatalf("%d hojas, se esperaban %d", len(sheets), tt.sheets)
+            }
+            rects := make([]image.Rectangle, len(placements))
+            for i, p := range placements {
+                rects[i] = image.Rect(p.X, p.Y, p.X+tt.sizes[i][0], p.Y+tt.sizes[i][1])
+                sheet := image.Rect(0, 0, sheets[p.Sheet][0], sheets[p.Sheet][1])
+                if !rects[i].In(sheet) || sheet.Dx() > tt.maxSize || sheet.Dy() > tt.maxSize {
+                    t.Fatalf("%v fuera de la hoja %v", rects[i], sheet)
+                }
+                for j := 0; j < i; j++ {
+                    padded := rects[j].Inset(-tt.padding)
+                    if placements[j].Sheet == p.Sheet && rects[i].Overlaps(padded) {
+                        t.Fatalf("%v se solapa con %v", rects[i], rects[j])
+                    }
+                }
+            }
+        })
+    }
+}
+
+// Con escalas fraccionarias las celdas no deben solaparse ni salirse de la hoja
+func TestExportAtlasesFractionalScale(t *testing.T) {
+    e := newTestExporter(t, Config{ScaleFactors: []float64{1, 1.5, 1.25}})
+    icon := Icon{Body: `<rect width="24" height="24"/>`, Width: 24, Height: 24, ViewBox: "0 0 24 24"}
+    var entries []iconEntry
+    for i := 0; i < 7; i++ {
+        entries = append(entries, iconEntry{"prueba", fmt.Sprintf("icono%d", i), icon})
+    }
+    results := e.exportAtlases(entries, [][2]int{{5, 5}}, []string{"black"})
+    for _, result := range results {
+        if result.Error != "" {
+            t.Fatalf("%s: %s", result.Path, result.Error)
+        }
+    }
+    
+    for _, suffix := range []string{"", "@1.25x", "@1.5x"} {
+        t.Run("escala"+suffix, func(t *testing.T) {
+            var atlas atlasJSON
+            data, err := os.ReadFile(filepath.Join(e.config.OutputDir, "atlas-5x5"+suffix+".json"))
+            if err != nil {
+                t.Fatal(err)
+            }
+            if err := json.Unmarshal(data, &atlas); err != nil {
+                t.Fatal(err)
+            }
+            file, err := os.Open(filepath.Join(e.config.OutputDir, atlas.Meta.Image))
+            if err != nil {
+                t.Fatal(err)
+            }
+            defer file.Close()
+            img, err := png.Decode(file)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if img.Bounds().Dx() != atlas.Meta.Size.W || img.Bounds().Dy() != atlas.Meta.Size.H {
+                t.Fatalf("hoja de %v, el JSON dice %+v", img.Bounds(), atlas.Meta.Size)
+            }
+            
+            var frames []image.Rectangle
+            for name, frame := range atlas.Frames {
+                r := image.Rect(frame.Frame.X, frame.Frame.Y, frame.Frame.X+frame.Frame.W, frame.Frame.Y+frame.Frame.H)
+                if !r.In(img.Bounds()) {
+                    t.Fatalf("%s en %v se sale de la hoja %v", name, r, img.Bounds())
+                }
+                for _, other := range frames {
+                    if r.Overlaps(other) {
+                        t.Fatalf("%s en %v se solapa con %v", name, r, other)
+                    }
+                }
+                frames = append(frames, r)
+                
+                // Cada celda está cubierta por completo por su cuadrado opaco
+                if _, _, _, a := img.At(r.Max.X-1, r.Max.Y-1).RGBA(); a == 0 {
+                    t.Fatalf("%s: esquina inferior de %v transparente", name, r)
+                }
+            }
+            if len(frames) != len(entries) {
+                t.Fatalf("%d marcos, se esperaban %d", len(frames), len(entries))
+            }
+        })
+    }
+}
+
+func TestExportAtlasesPackError(t *testing.T) {
+    e := newTestExporter(t, Config{Atlas: AtlasConfig{Enabled: true, MaxSize: 16}})
+    entries, _ := e.collectIconEntries()
+    results := e.exportAtlases(entries, [][2]int{{24, 24}}, []string{"red"})
+    if len(results) != 1 || results[0].Error == "" {
+        t.Fatalf("resultados inesperados: %+v", results)
+    }
+    if want := filepath.Join(e.config.OutputDir, "atlas-24x24.png"); results[0].Path != want {
+        t.Fatalf("ruta %q, se esperaba %q", results[0].Path, want)
+    }
+}
+
+func TestAtlasCSS(t *testing.T) {
+    e := newTestExporter(t, Config{
+        OutputFormats: []string{"svg"},
+        ScaleFactors:  []float64{2},
+        Atlas:         AtlasConfig{Enabled: true, Padding: 2, ClassPrefix: "i"},
+        FileNaming:    FileNamingConfig{Case: "kebab"},
+    })
+    if _, err := e.ExportWithVariants([][2]int{{24, 24}}, nil); err != nil {
+        t.Fatal(err)
+    }
+    data, err := os.ReadFile(filepath.Join(e.config.OutputDir, "atlas-24x24.css"))
+    if err != nil {
+        t.Fatal(err)
+    }
+    css := string(data)
+    for _, want := range []string{
+        ".i-nonicons-bell,\n.i-devicon-angular {",
+        "width: 24px;",
+        `background-image: url("atlas-24x24.png");`,
+        ".i-nonicons-bell { background-position: 0 0; }",
+        ".i-devicon-angular { background-position: 0 -26px; }",
+        "(-webkit-min-device-pixel-ratio: 2), (min-resolution: 192dpi)",
+        `background-image: url("atlas-24x24@2x.png");`,
+    } {
+        if !strings.Contains(css, want) {
+            t.Errorf("falta %q en:\n%s", want, css)
+        }
+    }
+}
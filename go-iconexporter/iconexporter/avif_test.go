@@ -0,0 +1,18 @@
+// iconexporter/avif_test.go
+package iconexporter
+
+import (
+    "strings"
+    "testing"
+)
+
+// AVIF no tiene codificador: la validación lo indica con el motivo
+func TestAVIFUnsupported(t *testing.T) {
+    if _, ok := LookupFormat("avif"); ok {
+        t.Fatal("avif no debería estar registrado")
+    }
+    _, err := NewIconExporter(Config{Collections: []string{"nonicons"}, OutputFormats: []string{"avif"}})
+    if err == nil || !strings.Contains(err.Error(), "formato de salida no soportado: avif") {
+        t.Fatalf("error inesperado: %v", err)
+    }
+}
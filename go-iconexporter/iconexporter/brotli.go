@@ -0,0 +1,22 @@
+// iconexporter/brotli.go
+package iconexporter
+
+import (
+    "bytes"
+
+    "github.com/andybalholm/brotli"
+)
+
+// brotliCompress comprime los datos de WOFF2 con el nivel máximo: las fuentes
+// de iconos son pequeñas y el tiempo de compresión no importa
+func brotliCompress(data []byte) ([]byte, error) {
+    var buf bytes.Buffer
+    bw := brotli.NewWriterLevel(&buf, brotli.BestCompression)
+    if _, err := bw.Write(data); err != nil {
+        return nil, err
+    }
+    if err := bw.Close(); err != nil {
+        return nil, err
+    }
+    return buf.Bytes(), nil
+}
@@ -0,0 +1,47 @@
+// iconexporter/brotli_test.go
+package iconexporter
+
+import (
+    "bytes"
+    "fmt"
+    "io"
+    "testing"
+
+    "github.com/andybalholm/brotli"
+)
+
+// decodeBrotli descomprime un flujo Brotli completo
+func decodeBrotli(data []byte) ([]byte, error) {
+    return io.ReadAll(brotli.NewReader(bytes.NewReader(data)))
+}
+
+func TestBrotliRoundTrip(t *testing.T) {
+    for _, size := range []int{0, 1, 255, 1 << 16, 1<<16 + 1, 200000} {
+        t.Run(fmt.Sprint(size), func(t *testing.T) {
+            data := testImage(size, 1, patternNoise).Pix[:size]
+            stream, err := brotliCompress(data)
+            if err != nil {
+                t.Fatal(err)
+            }
+            decoded, err := decodeBrotli(stream)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if !bytes.Equal(decoded, data) {
+                t.Fatal("los datos no coinciden")
+            }
+        })
+    }
+}
+
+// Los datos repetitivos, como las tablas de una fuente, se comprimen de verdad
+func TestBrotliCompresses(t *testing.T) {
+    data := bytes.Repeat([]byte("glyf loca hmtx cmap "), 1000)
+    stream, err := brotliCompress(data)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(stream) > len(data)/10 {
+        t.Fatalf("%d bytes de %d", len(stream), len(data))
+    }
+}
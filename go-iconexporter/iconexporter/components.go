@@ -0,0 +1,475 @@
+// iconexporter/components.go
+package iconexporter
+
+import (
+    "encoding/json"
+    "fmt"
+    "io"
+    "path/filepath"
+    "regexp"
+    "sort"
+    "strings"
+    "time"
+)
+
+// Frameworks admitidos para generar componentes
+var componentFrameworks = []string{"react", "vue", "svelte", "webcomponent"}
+
+// Extensión de los componentes y de su índice por framework
+var componentExtensions = map[string][2]string{
+    "react":        {".tsx", "index.ts"},
+    "vue":          {".vue", "index.ts"},
+    "svelte":       {".svelte", "index.ts"},
+    "webcomponent": {".ts", "index.ts"},
+}
+
+// ComponentsConfig configura la generación de componentes para frameworks web
+type ComponentsConfig struct {
+    Frameworks  []string `json:"frameworks"`
+    OutputDir   string   `json:"outputDir"`
+    NamePattern string   `json:"namePattern"`
+    TagPrefix   string   `json:"tagPrefix"`
+}
+
+// iconComponent es un icono con su nombre de componente ya resuelto
+type iconComponent struct {
+    Entry    iconEntry
+    Name     string // PascalCase, p. ej. NoniconsBell
+    Tag      string // nombre del custom element, p. ej. icon-nonicons-bell
+    Root     *svgNode
+    Warnings []string
+}
+
+var (
+    // Caracteres que no pueden formar parte de un identificador
+    invalidComponentChars = regexp.MustCompile(`[^A-Za-z0-9]+`)
+    // Prefijo válido para custom elements
+    validTagPrefix = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+)
+
+// componentsConfig completa la configuración con valores por defecto y la valida
+func (e *IconExporter) componentsConfig() (ComponentsConfig, error) {
+    cfg := e.config.Components
+    if len(cfg.Frameworks) == 0 {
+        cfg.Frameworks = []string{"react"}
+    }
+    for _, framework := range cfg.Frameworks {
+        if _, ok := componentExtensions[framework]; !ok {
+            return cfg, fmt.Errorf("framework no válido: %s (admitidos: %s)", framework, strings.Join(componentFrameworks, ", "))
+        }
+    }
+    if cfg.OutputDir == "" {
+        cfg.OutputDir = filepath.Join(e.config.OutputDir, "components")
+    }
+    if cfg.NamePattern == "" {
+        cfg.NamePattern = "{collection}-{icon}"
+    }
+    if cfg.TagPrefix == "" {
+        cfg.TagPrefix = "icon"
+    }
+    if !validTagPrefix.MatchString(cfg.TagPrefix) {
+        return cfg, fmt.Errorf("tagPrefix no válido para custom elements: %s", cfg.TagPrefix)
+    }
+    return cfg, nil
+}
+
+// componentName genera el nombre del componente en PascalCase con applyCase
+func (e *IconExporter) componentName(pattern, collection, iconName string) string {
+    name := strings.ReplaceAll(pattern, "{collection}", collection)
+    name = strings.ReplaceAll(name, "{icon}", iconName)
+    name = invalidComponentChars.ReplaceAllString(name, "-")
+    name = e.applyCase(name, "pascal")
+    
+    // Un identificador no puede empezar por número
+    if name == "" || (name[0] >= '0' && name[0] <= '9') {
+        name = "Icon" + name
+    }
+    return name
+}
+
+// jsString devuelve el texto como literal de cadena de JavaScript
+func jsString(s string) string {
+    var b strings.Builder
+    encoder := json.NewEncoder(&b)
+    encoder.SetEscapeHTML(false)
+    encoder.Encode(s)
+    return strings.TrimSuffix(b.String(), "\n")
+}
+
+// jsxName convierte un nombre de atributo o propiedad CSS SVG a su forma en JSX
+// (stroke-width → strokeWidth, xlink:href → xlinkHref, class → className)
+func jsxName(name string) string {
+    if name == "class" {
+        return "className"
+    }
+    if strings.HasPrefix(name, "data-") || strings.HasPrefix(name, "aria-") || strings.HasPrefix(name, "--") {
+        return name
+    }
+    return CamelCasePattern.ReplaceAllStringFunc(strings.ReplaceAll(name, ":", "-"), func(s string) string {
+        return strings.ToUpper(s[1:])
+    })
+}
+
+// writeJSX serializa el nodo como JSX; los comentarios se omiten y el texto se
+// escribe como expresión para no interpretar llaves ni etiquetas
+func writeJSX(b *strings.Builder, n *svgNode, indent string) {
+    switch n.Kind {
+    case svgTextNode:
+        if text := strings.TrimSpace(n.Data); text != "" {
+            b.WriteString(indent + "{" + jsString(text) + "}\n")
+        }
+        return
+    case svgCommentNode, svgRawNode:
+        return
+    }
+    
+    b.WriteString(indent + "<" + n.Name)
+    for _, a := range n.Attrs {
+        if a.Name == "style" {
+            var properties []string
+            for _, declaration := range parseStyleAttribute(a.Value) {
+                key := jsxName(declaration.Name)
+                if strings.HasPrefix(key, "--") {
+                    key = jsString(key) // propiedad personalizada
+                }
+                properties = append(properties, key+": "+jsString(declaration.Value))
+            }
+            fmt.Fprintf(b, " style={{ %s }}", strings.Join(properties, ", "))
+            continue
+        }
+        if strings.ContainsAny(a.Value, `"&{}<>`) {
+            fmt.Fprintf(b, " %s={%s}", jsxName(a.Name), jsString(a.Value))
+        } else {
+            fmt.Fprintf(b, ` %s="%s"`, jsxName(a.Name), a.Value)
+        }
+    }
+    if len(n.Children) == 0 {
+        b.WriteString(" />\n")
+        return
+    }
+    b.WriteString(">\n")
+    for _, child := range n.Children {
+        writeJSX(b, child, indent+"  ")
+    }
+    b.WriteString(indent + "</" + n.Name + ">\n")
+}
+
+// templateMarkup serializa los hijos del nodo para plantillas de Vue y Svelte,
+// escapando las llaves para que no se interpreten como expresiones
+func templateMarkup(n *svgNode) string {
+    var b strings.Builder
+    for _, child := range n.Children {
+        child.writeTo(&b)
+    }
+    return strings.NewReplacer("{", "&#123;", "}", "&#125;").Replace(b.String())
+}
+
+// innerMarkup serializa los hijos del nodo sin cambios
+func innerMarkup(n *svgNode) string {
+    var b strings.Builder
+    for _, child := range n.Children {
+        child.writeTo(&b)
+    }
+    return b.String()
+}
+
+// reactComponent genera un componente React con forwardRef y props size/color
+func (e *IconExporter) reactComponent(component iconComponent) string {
+    var body strings.Builder
+    for _, child := range component.Root.Children {
+        writeJSX(&body, child, "      ")
+    }
+    return fmt.Sprintf(`import { forwardRef } from "react";
+import type { IconProps } from "./types";
+
+const %[1]s = forwardRef<SVGSVGElement, IconProps>(
+  ({ size, color = %[2]s, ...props }, ref) => (
+    <svg
+      ref={ref}
+      xmlns="http://www.w3.org/2000/svg"
+      viewBox=%[3]s
+      width={size ?? %[4]d}
+      height={size ?? %[5]d}
+      color={color}
+      {...props}
+    >
+%[6]s    </svg>
+  ),
+);
+
+%[1]s.displayName = %[7]s;
+
+export default %[1]s;
+`, component.Name, jsString(e.config.DefaultColor), jsString(component.Entry.Icon.ViewBox),
+        e.config.DefaultSize[0], e.config.DefaultSize[1], body.String(), jsString(component.Name))
+}
+
+// vueComponent genera un componente Vue de un solo archivo
+func (e *IconExporter) vueComponent(component iconComponent) string {
+    return fmt.Sprintf(`<script setup lang="ts">
+withDefaults(defineProps<{ size?: number | string; color?: string }>(), {
+  size: undefined,
+  color: %[1]s,
+});
+</script>
+
+<template>
+  <svg
+    xmlns="http://www.w3.org/2000/svg"
+    viewBox=%[2]s
+    :width="size ?? %[3]d"
+    :height="size ?? %[4]d"
+    :color="color"
+  >%[5]s</svg>
+</template>
+`, jsString(e.config.DefaultColor), jsString(component.Entry.Icon.ViewBox),
+        e.config.DefaultSize[0], e.config.DefaultSize[1], templateMarkup(component.Root))
+}
+
+// svelteComponent genera un componente Svelte; el resto de props pasa al <svg>
+func (e *IconExporter) svelteComponent(component iconComponent) string {
+    return fmt.Sprintf(`<script lang="ts">
+  export let size: number | string | undefined = undefined;
+  export let color: string = %[1]s;
+</script>
+
+<svg
+  xmlns="http://www.w3.org/2000/svg"
+  viewBox=%[2]s
+  width={size ?? %[3]d}
+  height={size ?? %[4]d}
+  {color}
+  {...$$restProps}
+>%[5]s</svg>
+`, jsString(e.config.DefaultColor), jsString(component.Entry.Icon.ViewBox),
+        e.config.DefaultSize[0], e.config.DefaultSize[1], templateMarkup(component.Root))
+}
+
+// webComponent genera un custom element con shadow DOM y atributos size/color
+func (e *IconExporter) webComponent(component iconComponent) string {
+    svg := fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="%s">%s</svg>`,
+        component.Entry.Icon.ViewBox, innerMarkup(component.Root))
+    return fmt.Sprintf(`const template = document.createElement("template");
+template.innerHTML = %[1]s;
+
+export class %[2]sElement extends HTMLElement {
+  static observedAttributes = ["size", "color"];
+
+  constructor() {
+    super();
+    this.attachShadow({ mode: "open" }).appendChild(template.content.cloneNode(true));
+  }
+
+  connectedCallback(): void {
+    this.render();
+  }
+
+  attributeChangedCallback(): void {
+    this.render();
+  }
+
+  private render(): void {
+    const svg = this.shadowRoot!.querySelector("svg")!;
+    const size = this.getAttribute("size");
+    svg.setAttribute("width", size ?? "%[3]d");
+    svg.setAttribute("height", size ?? "%[4]d");
+    svg.setAttribute("color", this.getAttribute("color") ?? %[5]s);
+  }
+}
+
+if (!customElements.get(%[6]s)) {
+  customElements.define(%[6]s, %[2]sElement);
+}
+
+declare global {
+  interface HTMLElementTagNameMap {
+    %[6]s: %[2]sElement;
+  }
+}
+`, jsString(`<style>:host{display:inline-flex}</style>`+svg), component.Name,
+        e.config.DefaultSize[0], e.config.DefaultSize[1], jsString(e.config.DefaultColor), jsString(component.Tag))
+}
+
+// componentIndex genera el índice que reexporta todos los componentes
+func componentIndex(framework string, components []iconComponent) string {
+    var b strings.Builder
+    if framework == "react" {
+        b.WriteString("export type { IconProps } from \"./types\";\n")
+    }
+    for _, component := range components {
+        switch framework {
+        case "react":
+            fmt.Fprintf(&b, "export { default as %s } from \"./%s\";\n", component.Name, component.Name)
+        case "vue", "svelte":
+            fmt.Fprintf(&b, "export { default as %s } from \"./%s%s\";\n", component.Name, component.Name, componentExtensions[framework][0])
+        case "webcomponent":
+            fmt.Fprintf(&b, "export { %sElement } from \"./%s\";\n", component.Name, component.Name)
+        }
+    }
+    return b.String()
+}
+
+// Props comunes de los componentes React
+const reactIconTypes = `import type { SVGProps } from "react";
+
+export interface IconProps extends Omit<SVGProps<SVGSVGElement>, "ref"> {
+  size?: number | string;
+  color?: string;
+}
+`
+
+// iconComponents resuelve nombres y analiza el SVG de cada icono; los nombres
+// y etiquetas repetidos reciben un sufijo numérico
+func (e *IconExporter) iconComponents(cfg ComponentsConfig, entries []iconEntry) ([]iconComponent, []ExportResult) {
+    var components []iconComponent
+    var failed []ExportResult
+    seen := map[string]int{}
+    seenTags := map[string]int{}
+    for _, entry := range entries {
+        name := e.componentName(cfg.NamePattern, entry.Collection, entry.Name)
+        tag := strings.ReplaceAll(cfg.NamePattern, "{collection}", entry.Collection)
+        tag = strings.ReplaceAll(tag, "{icon}", entry.Name)
+        tag = cfg.TagPrefix + "-" + e.applyCase(invalidComponentChars.ReplaceAllString(tag, "-"), "kebab")
+        var warnings []string
+        if seen[name]++; seen[name] > 1 {
+            // El nombre con sufijo también puede existir ya (Bell, Bell, Bell2)
+            count := seen[name]
+            renamed := fmt.Sprintf("%s%d", name, count)
+            for seen[renamed] > 0 {
+                count++
+                renamed = fmt.Sprintf("%s%d", name, count)
+            }
+            seen[renamed]++
+            warnings = append(warnings, fmt.Sprintf("nombre de componente %s repetido, se usa %s", name, renamed))
+            name = renamed
+        }
+        if seenTags[tag]++; seenTags[tag] > 1 {
+            // Igual con las etiquetas: foo, foo y foo-2 no pueden compartir foo-2
+            count := seenTags[tag]
+            renamed := fmt.Sprintf("%s-%d", tag, count)
+            for seenTags[renamed] > 0 {
+                count++
+                renamed = fmt.Sprintf("%s-%d", tag, count)
+            }
+            seenTags[renamed]++
+            warnings = append(warnings, fmt.Sprintf("etiqueta %s repetida, se usa %s", tag, renamed))
+            tag = renamed
+        }
+        
+        // Las rutas sin relleno usan currentColor para que la prop color funcione
+        svg := e.prepareSvgBuffer(entry.Icon, entry.Icon.Width, entry.Icon.Height, "currentColor", e.svgIDPrefix(entry.Collection, entry.Name))
+        root, err := parseSvgDocument(svg)
+        if err != nil {
+            result := ExportResult{Collection: entry.Collection, Icon: entry.Name, Error: err.Error()}
+            fmt.Printf("❌ Error procesando %s:%s: %v\n", entry.Collection, entry.Name, err)
+            failed = append(failed, result)
+            continue
+        }
+        components = append(components, iconComponent{
+            Entry:    entry,
+            Name:     name,
+            Tag:      tag,
+            Root:     root,
+            Warnings: warnings,
+        })
+    }
+    return components, failed
+}
+
+// ExportComponents genera un componente por icono para cada framework
+// configurado, con un índice que los reexporta
+func (e *IconExporter) ExportComponents() (ExportSummary, error) {
+    startTime := time.Now()
+    
+    cfg, err := e.componentsConfig()
+    if err != nil {
+        return ExportSummary{}, err
+    }
+    
+    entries, missing := e.collectIconEntries()
+    if len(entries) == 0 {
+        return ExportSummary{}, fmt.Errorf("no hay iconos para generar componentes")
+    }
+    fmt.Printf("\n🧩 Generando componentes (%s): %d iconos\n", strings.Join(cfg.Frameworks, ", "), len(entries))
+    
+    components, results := e.iconComponents(cfg, entries)
+    sort.Slice(components, func(i, j int) bool { return components[i].Name < components[j].Name })
+    
+    for _, framework := range cfg.Frameworks {
+        dir := filepath.Join(cfg.OutputDir, framework)
+        if err := e.ensureOutputDir(dir); err != nil {
+            return ExportSummary{}, fmt.Errorf("error creando directorio de salida: %w", err)
+        }
+        
+        save := func(result ExportResult, content string) {
+            err := writeFile(result.Path, func(w io.Writer) error {
+                _, err := io.WriteString(w, content)
+                return err
+            })
+            if err != nil {
+                fmt.Printf("❌ Error al guardar %s: %v\n", result.Path, err)
+                result.Error = err.Error()
+            } else {
+                fmt.Printf("✅ Exportado: %s\n", result.Path)
+                printWarnings(result)
+            }
+            results = append(results, result)
+        }
+        
+        for _, component := range components {
+            var content string
+            switch framework {
+            case "react":
+                content = e.reactComponent(component)
+            case "vue":
+                content = e.vueComponent(component)
+            case "svelte":
+                content = e.svelteComponent(component)
+            case "webcomponent":
+                content = e.webComponent(component)
+            }
+            save(ExportResult{
+                Collection: component.Entry.Collection,
+                Icon:       component.Entry.Name,
+                Format:     framework,
+                Path:       filepath.Join(dir, component.Name+componentExtensions[framework][0]),
+                Warnings:   component.Warnings,
+            }, content)
+        }
+        
+        if framework == "react" {
+            save(ExportResult{Format: framework, Path: filepath.Join(dir, "types.ts")}, reactIconTypes)
+        }
+        save(ExportResult{Format: framework, Path: filepath.Join(dir, componentExtensions[framework][1])},
+            componentIndex(framework, components))
+    }
+    
+    processed, errors := 0, missing
+    for _, result := range results {
+        if result.Error != "" {
+            errors++
+        } else {
+            processed++
+        }
+    }
+    
+    duration := time.Since(startTime).Seconds()
+    e.printExportSummary(processed, errors, duration)
+    
+    return ExportSummary{
+        Processed: processed,
+        Errors:    errors,
+        Duration:  duration,
+        Results:   results,
+        Sanitized: e.sanitizeReports(),
+    }, nil
+}
+
+// ExportComponents genera los componentes de los iconos con la configuración dada
+func ExportComponents(config Config) (ExportSummary, error) {
+    exporter, err := NewIconExporter(config)
+    if err != nil {
+        return ExportSummary{}, err
+    }
+    return exporter.ExportComponents()
+}
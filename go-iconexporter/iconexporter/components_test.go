@@ -0,0 +1,209 @@
+// iconexporter/components_test.go
+package iconexporter
+
+import (
+    "os"
+    "path/filepath"
+    "reflect"
+    "strings"
+    "testing"
+)
+
+func TestJSXName(t *testing.T) {
+    tests := map[string]string{
+        "class":        "className",
+        "stroke-width": "strokeWidth",
+        "xlink:href":   "xlinkHref",
+        "fill-rule":    "fillRule",
+        "aria-hidden":  "aria-hidden",
+        "data-icon":    "data-icon",
+        "--accent":     "--accent",
+        "d":            "d",
+    }
+    for name, want := range tests {
+        if got := jsxName(name); got != want {
+            t.Errorf("jsxName(%q) = %q, se esperaba %q", name, got, want)
+        }
+    }
+}
+
+func TestWriteJSX(t *testing.T) {
+    tests := []struct {
+        name string
+        svg  string
+        want string
+    }{
+        {
+            name: "atributos en camelCase",
+            svg:  `<g stroke-width="2" class="a"><path d="M0 0"/></g>`,
+            want: "<g strokeWidth=\"2\" className=\"a\">\n  <path d=\"M0 0\" />\n</g>\n",
+        },
+        {
+            name: "style como objeto",
+            svg:  `<path style="fill-rule:evenodd;--c:red"/>`,
+            want: "<path style={{ fillRule: \"evenodd\", \"--c\": \"red\" }} />\n",
+        },
+        {
+            name: "texto y llaves como expresión",
+            svg:  `<text>a{b}</text>`,
+            want: "<text>\n  {\"a{b}\"}\n</text>\n",
+        },
+        {
+            name: "valores con comillas",
+            svg:  `<g data-x='a"b'/>`,
+            want: "<g data-x={\"a\\\"b\"} />\n",
+        },
+        {
+            name: "sin comentarios",
+            svg:  `<g><!-- nota --></g>`,
+            want: "<g>\n</g>\n",
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            root, err := parseSvgDocument([]byte(`<svg xmlns="http://www.w3.org/2000/svg">` + tt.svg + `</svg>`))
+            if err != nil {
+                t.Fatal(err)
+            }
+            var b strings.Builder
+            for _, child := range root.Children {
+                writeJSX(&b, child, "")
+            }
+            if b.String() != tt.want {
+                t.Fatalf("\n%s\nse esperaba\n%s", b.String(), tt.want)
+            }
+        })
+    }
+}
+
+func TestComponentName(t *testing.T) {
+    tests := []struct {
+        pattern, collection, icon, want string
+    }{
+        {"{collection}-{icon}", "nonicons", "bell", "NoniconsBell"},
+        {"{icon}", "nonicons", "arrow_up.small", "ArrowUpSmall"},
+        {"{icon}", "nonicons", "2fa", "Icon2fa"},
+        {"{icon}-icon", "devicon", "angular", "AngularIcon"},
+    }
+    e := newTestExporter(t, Config{})
+    for _, tt := range tests {
+        if got := e.componentName(tt.pattern, tt.collection, tt.icon); got != tt.want {
+            t.Errorf("componentName(%q, %q) = %q, se esperaba %q", tt.pattern, tt.icon, got, tt.want)
+        }
+    }
+}
+
+// Los nombres repetidos reciben un sufijo que tampoco puede repetirse
+func TestIconComponentsUniqueNames(t *testing.T) {
+    e := newTestExporter(t, Config{})
+    cfg, _ := e.componentsConfig()
+    cfg.NamePattern = "{icon}"
+    icon := Icon{Body: `<path d="M0 0h1v1z"/>`, Width: 24, Height: 24, ViewBox: "0 0 24 24"}
+    entries := []iconEntry{{"a", "bell", icon}, {"b", "bell", icon}, {"c", "bell2", icon}, {"d", "bell", icon}}
+    
+    components, failed := e.iconComponents(cfg, entries)
+    if len(failed) != 0 {
+        t.Fatalf("fallos inesperados: %+v", failed)
+    }
+    var names, tags []string
+    for _, component := range components {
+        names = append(names, component.Name)
+        tags = append(tags, component.Tag)
+    }
+    if want := []string{"Bell", "Bell2", "Bell22", "Bell3"}; !reflect.DeepEqual(names, want) {
+        t.Fatalf("nombres %v, se esperaban %v", names, want)
+    }
+    if want := []string{"icon-bell", "icon-bell-2", "icon-bell2", "icon-bell-3"}; !reflect.DeepEqual(tags, want) {
+        t.Fatalf("etiquetas %v, se esperaban %v", tags, want)
+    }
+    
+    // La etiqueta con sufijo puede coincidir con la de otro icono
+    entries = []iconEntry{{"a", "foo", icon}, {"b", "foo", icon}, {"c", "foo-2", icon}}
+    components, _ = e.iconComponents(cfg, entries)
+    tags = nil
+    for _, component := range components {
+        tags = append(tags, component.Tag)
+    }
+    if want := []string{"icon-foo", "icon-foo-2", "icon-foo-2-2"}; !reflect.DeepEqual(tags, want) {
+        t.Fatalf("etiquetas %v, se esperaban %v", tags, want)
+    }
+}
+
+func TestCollectIconEntriesKeepsConfigOrder(t *testing.T) {
+    icons := []string{"bell", "angular", "missing"}
+    e := newTestExporter(t, Config{IconsToExport: icons})
+    entries, missing := e.collectIconEntries()
+    if len(entries) != 2 || missing != 4 {
+        t.Fatalf("%d iconos y %d ausentes", len(entries), missing)
+    }
+    if want := []string{"bell", "angular", "missing"}; !reflect.DeepEqual(e.config.IconsToExport, want) {
+        t.Fatalf("IconsToExport modificado: %v", e.config.IconsToExport)
+    }
+}
+
+func TestExportComponents(t *testing.T) {
+    e := newTestExporter(t, Config{
+        DefaultColor: "currentColor",
+        Components:   ComponentsConfig{Frameworks: []string{"react", "vue", "svelte", "webcomponent"}},
+    })
+    summary, err := e.ExportComponents()
+    if err != nil {
+        t.Fatal(err)
+    }
+    // Dos componentes por framework, más índices y tipos de React
+    if summary.Processed != 4*2+4+1 || summary.Errors != 0 {
+        t.Fatalf("resumen inesperado: %+v", summary)
+    }
+    
+    tests := []struct {
+        file     string
+        contains []string
+    }{
+        {"react/NoniconsBell.tsx", []string{
+            "const NoniconsBell = forwardRef<SVGSVGElement, IconProps>(",
+            `({ size, color = "currentColor", ...props }, ref)`,
+            `viewBox="0 0 24 24"`,
+            `<path fill="currentColor" d="M12 22c1.1`,
+            `NoniconsBell.displayName = "NoniconsBell";`,
+        }},
+        {"react/index.ts", []string{
+            `export type { IconProps } from "./types";`,
+            `export { default as DeviconAngular } from "./DeviconAngular";`,
+        }},
+        {"react/types.ts", []string{"size?: number | string;"}},
+        {"vue/DeviconAngular.vue", []string{`<script setup lang="ts">`, `:width="size ?? 48"`, "<path"}},
+        {"vue/index.ts", []string{`export { default as NoniconsBell } from "./NoniconsBell.vue";`}},
+        {"svelte/NoniconsBell.svelte", []string{"{...$$restProps}", "export let color: string = \"currentColor\";"}},
+        {"webcomponent/NoniconsBell.ts", []string{
+            "export class NoniconsBellElement extends HTMLElement",
+            `customElements.define("icon-nonicons-bell", NoniconsBellElement);`,
+        }},
+        {"webcomponent/index.ts", []string{`export { DeviconAngularElement } from "./DeviconAngular";`}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.file, func(t *testing.T) {
+            data, err := os.ReadFile(filepath.Join(e.config.OutputDir, "components", tt.file))
+            if err != nil {
+                t.Fatal(err)
+            }
+            for _, want := range tt.contains {
+                if !strings.Contains(string(data), want) {
+                    t.Errorf("falta %q en:\n%s", want, data)
+                }
+            }
+        })
+    }
+}
+
+func TestComponentsConfigErrors(t *testing.T) {
+    for name, cfg := range map[string]ComponentsConfig{
+        "framework": {Frameworks: []string{"angular"}},
+        "tagPrefix": {TagPrefix: "Icon"},
+    } {
+        t.Run(name, func(t *testing.T) {
+            if _, err := NewIconExporter(Config{Collections: []string{"nonicons"}, Components: cfg}); err == nil {
+                t.Fatal("se esperaba un error")
+            }
+        })
+    }
+}
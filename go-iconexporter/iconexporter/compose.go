@@ -0,0 +1,142 @@
+// iconexporter/compose.go
+package iconexporter
+
+import (
+    "fmt"
+    "image/color"
+    "io"
+    "path/filepath"
+    "regexp"
+    "strings"
+)
+
+// composeFormat convierte el SVG preparado en código Kotlin que construye un
+// ImageVector de Jetpack Compose
+type composeFormat struct{}
+
+func init() {
+    mustRegisterFormat(composeFormat{})
+}
+
+func (composeFormat) Name() string      { return "compose" }
+func (composeFormat) Extension() string { return "kt" }
+func (composeFormat) Vector() bool      { return true }
+
+// Caracteres que no pueden formar parte de un identificador de Kotlin o Swift
+var invalidIdentifierChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// codeTypeName deriva el nombre del tipo del nombre de archivo, que ya sigue
+// FileNaming.Case; sin nombre de archivo se usa colección e icono
+func codeTypeName(input EncodeInput) string {
+    name := strings.TrimSuffix(input.FileName, filepath.Ext(input.FileName))
+    if name == "" {
+        name = input.Collection + "_" + input.Icon
+    }
+    name = invalidIdentifierChars.ReplaceAllString(name, "_")
+    if name == "" || (name[0] >= '0' && name[0] <= '9') {
+        name = "_" + name
+    }
+    return name
+}
+
+// viewBoxPath lleva los segmentos al origen del viewBox y elimina los arcos
+func viewBoxPath(segments []pathSegment, box [4]float64) []pathSegment {
+    return pathWithoutArcs(transformPath(segments, affine{1, 0, 0, 1, -box[0], -box[1]}))
+}
+
+// kotlinFloat escribe un literal Float de Kotlin
+func kotlinFloat(v float64, precision int) string {
+    return formatNumber(v, precision) + "f"
+}
+
+// kotlinColor escribe un Color de Compose en formato 0xAARRGGBB
+func kotlinColor(c color.NRGBA) string {
+    return fmt.Sprintf("Color(0x%02X%02X%02X%02X)", c.A, c.R, c.G, c.B)
+}
+
+func (composeFormat) Encode(w io.Writer, input EncodeInput, options map[string]interface{}) error {
+    shapes, box, err := flattenSvg(input.SVG, input.Color, input.warn)
+    if err != nil {
+        return err
+    }
+    precision := optionInt(options, "precision", 3)
+    packageName, _ := options["package"].(string)
+    if packageName == "" {
+        packageName = "icons"
+    }
+    name := codeTypeName(input)
+    
+    var b strings.Builder
+    fmt.Fprintf(&b, `package %s
+
+import androidx.compose.ui.graphics.Color
+import androidx.compose.ui.graphics.PathFillType
+import androidx.compose.ui.graphics.SolidColor
+import androidx.compose.ui.graphics.StrokeCap
+import androidx.compose.ui.graphics.StrokeJoin
+import androidx.compose.ui.graphics.vector.ImageVector
+import androidx.compose.ui.graphics.vector.path
+import androidx.compose.ui.unit.dp
+
+val %s: ImageVector by lazy {
+    ImageVector.Builder(
+        name = %q,
+        defaultWidth = %s.dp,
+        defaultHeight = %s.dp,
+        viewportWidth = %s,
+        viewportHeight = %s,
+    ).apply {
+`, packageName, name, name,
+        formatNumber(float64(input.Width), 2), formatNumber(float64(input.Height), 2),
+        kotlinFloat(box[2], precision), kotlinFloat(box[3], precision))
+    
+    caps := map[string]string{"butt": "Butt", "round": "Round", "square": "Square"}
+    joins := map[string]string{"miter": "Miter", "round": "Round", "bevel": "Bevel"}
+    for _, shape := range shapes {
+        b.WriteString("        path(\n")
+        if !shape.Fill.None {
+            fmt.Fprintf(&b, "            fill = SolidColor(%s),\n", kotlinColor(shape.Fill.Color))
+            fmt.Fprintf(&b, "            fillAlpha = %s,\n", kotlinFloat(shape.FillOpacity, 3))
+        }
+        if !shape.Stroke.None {
+            if dash := shape.Style.value("stroke-dasharray"); dash != "" && dash != "none" {
+                input.warn("stroke-dasharray no está soportado en ImageVector, se dibuja continuo")
+            }
+            fmt.Fprintf(&b, "            stroke = SolidColor(%s),\n", kotlinColor(shape.Stroke.Color))
+            fmt.Fprintf(&b, "            strokeAlpha = %s,\n", kotlinFloat(shape.StrokeOpacity, 3))
+            fmt.Fprintf(&b, "            strokeLineWidth = %s,\n", kotlinFloat(shape.StrokeWidth, precision))
+            fmt.Fprintf(&b, "            strokeLineCap = StrokeCap.%s,\n", caps[shape.LineCap])
+            fmt.Fprintf(&b, "            strokeLineJoin = StrokeJoin.%s,\n", joins[shape.LineJoin])
+            fmt.Fprintf(&b, "            strokeLineMiter = %s,\n", kotlinFloat(shape.Style.number("stroke-miterlimit"), 3))
+        }
+        fillType := "NonZero"
+        if shape.EvenOdd {
+            fillType = "EvenOdd"
+        }
+        fmt.Fprintf(&b, "            pathFillType = PathFillType.%s,\n        ) {\n", fillType)
+        
+        for _, seg := range viewBoxPath(shape.Segments, box) {
+            args := make([]string, len(seg.Args))
+            for i, v := range seg.Args {
+                args[i] = kotlinFloat(v, precision)
+            }
+            switch seg.Cmd {
+            case 'M':
+                fmt.Fprintf(&b, "            moveTo(%s)\n", strings.Join(args, ", "))
+            case 'L':
+                fmt.Fprintf(&b, "            lineTo(%s)\n", strings.Join(args, ", "))
+            case 'C':
+                fmt.Fprintf(&b, "            curveTo(%s)\n", strings.Join(args, ", "))
+            case 'Q':
+                fmt.Fprintf(&b, "            quadTo(%s)\n", strings.Join(args, ", "))
+            case 'Z':
+                b.WriteString("            close()\n")
+            }
+        }
+        b.WriteString("        }\n")
+    }
+    b.WriteString("    }.build()\n}\n")
+    
+    _, err = io.WriteString(w, b.String())
+    return err
+}
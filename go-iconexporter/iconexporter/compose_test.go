@@ -0,0 +1,96 @@
+// iconexporter/compose_test.go
+package iconexporter
+
+import (
+    "reflect"
+    "strings"
+    "testing"
+)
+
+func TestCodeTypeName(t *testing.T) {
+    tests := []struct {
+        input EncodeInput
+        want  string
+    }{
+        {EncodeInput{FileName: "NoniconsBell.kt"}, "NoniconsBell"},
+        {EncodeInput{FileName: "nonicons-bell-24.swift"}, "nonicons_bell_24"},
+        {EncodeInput{FileName: "24-bell.kt"}, "_24_bell"},
+        {EncodeInput{Collection: "devicon", Icon: "angular"}, "devicon_angular"},
+    }
+    for _, tt := range tests {
+        if got := codeTypeName(tt.input); got != tt.want {
+            t.Errorf("codeTypeName(%+v) = %q, se esperaba %q", tt.input, got, tt.want)
+        }
+    }
+}
+
+func TestComposeEncode(t *testing.T) {
+    tests := []struct {
+        name     string
+        svg      []byte
+        contains []string
+        warnings []string
+    }{
+        {
+            name: "relleno evenodd con opacidad",
+            svg:  testSvg(`<path fill="#00ff00" fill-opacity="0.5" fill-rule="evenodd" d="M4 4h10v10H4z M6 6h4v4H6z"/>`),
+            contains: []string{
+                "fill = SolidColor(Color(0xFF00FF00)),",
+                "fillAlpha = 0.5f,",
+                "pathFillType = PathFillType.EvenOdd,",
+                "moveTo(4f, 4f)\n            lineTo(14f, 4f)",
+                "close()",
+            },
+        },
+        {
+            name:     "viewBox desplazado al origen",
+            svg:      []byte(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="2 2 20 20"><path d="M4 4h2v2z"/></svg>`),
+            contains: []string{"viewportWidth = 20f,", "moveTo(2f, 2f)", "lineTo(4f, 2f)"},
+        },
+        {
+            name: "trazo con extremos y uniones",
+            svg:  testSvg(`<path fill="none" stroke="currentColor" stroke-width="2" stroke-linecap="round" stroke-linejoin="bevel" d="M2 2L6 6"/>`),
+            contains: []string{
+                "stroke = SolidColor(Color(0xFF123456)),",
+                "strokeLineWidth = 2f,",
+                "strokeLineCap = StrokeCap.Round,",
+                "strokeLineJoin = StrokeJoin.Bevel,",
+            },
+        },
+        {
+            name:     "valores no válidos usan butt y miter",
+            svg:      testSvg(`<path fill="none" stroke="red" stroke-linecap="flat" stroke-linejoin="sharp" d="M2 2L6 6"/>`),
+            contains: []string{"strokeLineCap = StrokeCap.Butt,", "strokeLineJoin = StrokeJoin.Miter,"},
+            warnings: []string{"stroke-linecap no válido: flat, se usa butt", "stroke-linejoin no válido: sharp, se usa miter"},
+        },
+        {
+            name:     "arcs se dibuja como miter",
+            svg:      testSvg(`<path fill="none" stroke="red" stroke-linejoin="arcs" d="M2 2L6 6"/>`),
+            contains: []string{"strokeLineJoin = StrokeJoin.Miter,"},
+        },
+        {
+            name:     "arcos convertidos en curvas",
+            svg:      testSvg(`<circle cx="12" cy="12" r="4"/>`),
+            contains: []string{"moveTo(8f, 12f)", "curveTo("},
+        },
+        {
+            name:     "trazo discontinuo",
+            svg:      testSvg(`<path fill="none" stroke="red" stroke-dasharray="2 1" d="M2 2L6 6"/>`),
+            contains: []string{"strokeLineCap = StrokeCap.Butt,"},
+            warnings: []string{"stroke-dasharray no está soportado en ImageVector, se dibuja continuo"},
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got, warnings := encodeWith(t, composeFormat{}, tt.svg, "NoniconsBell.kt", map[string]interface{}{"package": "com.example.icons"})
+            for _, want := range append([]string{"package com.example.icons\n", "val NoniconsBell: ImageVector by lazy {"}, tt.contains...) {
+                if !strings.Contains(got, want) {
+                    t.Errorf("falta %q en:\n%s", want, got)
+                }
+            }
+            if !reflect.DeepEqual(warnings, tt.warnings) {
+                t.Errorf("avisos %q, se esperaban %q", warnings, tt.warnings)
+            }
+        })
+    }
+}
@@ -0,0 +1,405 @@
+// iconexporter/embedded.go
+package iconexporter
+
+import (
+    "encoding/binary"
+    "fmt"
+    "image"
+    "image/color"
+    "io"
+    "strings"
+
+    "github.com/disintegration/imaging"
+)
+
+// Formatos para pantallas de microcontroladores: cabeceras C con el bitmap en
+// varios formatos de píxel y XBM. Todos parten de la imagen rasterizada.
+func init() {
+    mustRegisterFormat(cHeaderFormat{})
+    mustRegisterFormat(xbmFormat{})
+}
+
+// Matriz de Bayer 4x4 para el tramado ordenado
+var bayer4 = [4][4]float64{
+    {0, 8, 2, 10},
+    {12, 4, 14, 6},
+    {3, 11, 1, 9},
+    {15, 7, 13, 5},
+}
+
+// levelBitmap son los niveles de un canal, uno por píxel, fila a fila
+type levelBitmap struct {
+    Width, Height int
+    Levels        []uint8
+}
+
+// inkLevels extrae la intensidad de cada píxel para las salidas de 1 y 4 bits.
+// Opciones:
+//   - "source": alpha (por defecto, la cobertura del icono) o luminance (lo
+//     oscuro del icono compuesto sobre "background")
+//   - "invert": invierte los niveles
+func inkLevels(img image.Image, options map[string]interface{}) (levelBitmap, error) {
+    source, _ := options["source"].(string)
+    if source == "" {
+        source = "alpha"
+    }
+    src := imaging.Clone(img)
+    switch source {
+    case "alpha":
+    case "luminance":
+        background, _ := options["background"].(string)
+        if background == "" {
+            background = "white"
+        }
+        flat, err := flattenImage(img, background)
+        if err != nil {
+            return levelBitmap{}, err
+        }
+        src = flat
+    default:
+        return levelBitmap{}, fmt.Errorf("source no válido: %s (admitidos: alpha, luminance)", source)
+    }
+    
+    bounds := src.Bounds()
+    bitmap := levelBitmap{bounds.Dx(), bounds.Dy(), make([]uint8, bounds.Dx()*bounds.Dy())}
+    invert := optionBool(options, "invert", false)
+    for i := range bitmap.Levels {
+        p := src.Pix[i*4 : i*4+4]
+        level := p[3]
+        if source == "luminance" {
+            // Lo oscuro enciende el píxel, como la tinta sobre el papel
+            level = 255 - uint8((299*uint32(p[0])+587*uint32(p[1])+114*uint32(p[2])+500)/1000)
+        }
+        if invert {
+            level = 255 - level
+        }
+        bitmap.Levels[i] = level
+    }
+    return bitmap, nil
+}
+
+// quantizeLevels reduce los niveles a 1 o 4 bits (valores 0-1 o 0-15) con
+// "threshold" (0-255, por defecto 128; solo en 1 bit) y "dither": none,
+// floyd-steinberg u ordered (Bayer 4x4)
+func quantizeLevels(bitmap levelBitmap, bits int, options map[string]interface{}) ([]uint8, error) {
+    threshold := optionInt(options, "threshold", 128)
+    if threshold < 0 || threshold > 255 {
+        return nil, fmt.Errorf("umbral fuera de rango (0-255): %d", threshold)
+    }
+    dither := ditherOption(options)
+    
+    maxValue := float64(int(1)<<bits - 1)
+    step := 255 / maxValue
+    // Con 1 bit el umbral decide; con más bits se redondea al nivel más cercano
+    quantize := func(v float64) uint8 {
+        if bits == 1 {
+            if v >= float64(threshold) {
+                return 1
+            }
+            return 0
+        }
+        return uint8(min(max(v/step+0.5, 0), maxValue))
+    }
+    
+    w, h := bitmap.Width, bitmap.Height
+    out := make([]uint8, len(bitmap.Levels))
+    switch dither {
+    case "none":
+        for i, level := range bitmap.Levels {
+            out[i] = quantize(float64(level))
+        }
+    case "ordered":
+        for y := 0; y < h; y++ {
+            for x := 0; x < w; x++ {
+                // Desplazamiento de -0.5 a 0.5 pasos según la posición en la matriz
+                offset := ((bayer4[y%4][x%4]+0.5)/16 - 0.5) * step
+                out[y*w+x] = quantize(float64(bitmap.Levels[y*w+x]) + offset)
+            }
+        }
+    case "floyd-steinberg":
+        errs := make([]float64, len(bitmap.Levels))
+        for y := 0; y < h; y++ {
+            for x := 0; x < w; x++ {
+                i := y*w + x
+                v := float64(bitmap.Levels[i]) + errs[i]
+                out[i] = quantize(v)
+                e := v - float64(out[i])*step
+                if x+1 < w {
+                    errs[i+1] += e * 7 / 16
+                }
+                if y+1 < h {
+                    if x > 0 {
+                        errs[i+w-1] += e * 3 / 16
+                    }
+                    errs[i+w] += e * 5 / 16
+                    if x+1 < w {
+                        errs[i+w+1] += e * 1 / 16
+                    }
+                }
+            }
+        }
+    default:
+        return nil, fmt.Errorf("dither no válido: %s (admitidos: none, floyd-steinberg, ordered)", dither)
+    }
+    return out, nil
+}
+
+// packBits empaqueta valores de bits bits por fila; cada fila empieza en un byte
+// nuevo. msbFirst coloca el primer píxel en los bits altos.
+func packBits(values []uint8, width, height, bits int, msbFirst bool) []byte {
+    perByte := 8 / bits
+    stride := (width + perByte - 1) / perByte
+    out := make([]byte, stride*height)
+    for y := 0; y < height; y++ {
+        for x := 0; x < width; x++ {
+            shift := (x % perByte) * bits
+            if msbFirst {
+                shift = 8 - bits - shift
+            }
+            out[y*stride+x/perByte] |= values[y*width+x] << shift
+        }
+    }
+    return out
+}
+
+// rgb565 convierte la imagen compuesta sobre "background" a RGB565; con
+// "bigEndian" el byte alto va primero
+func rgb565(img image.Image, options map[string]interface{}) ([]byte, error) {
+    background, _ := options["background"].(string)
+    if background == "" {
+        background = "black"
+    }
+    flat, err := flattenImage(img, background)
+    if err != nil {
+        return nil, err
+    }
+    return packRGB565(flat.Pix, 4, optionBool(options, "bigEndian", false)), nil
+}
+
+// packRGB565 convierte píxeles de 8 bits por canal (separados por stride bytes)
+func packRGB565(pix []byte, stride int, bigEndian bool) []byte {
+    out := make([]byte, 0, len(pix)/stride*2)
+    for i := 0; i+2 < len(pix); i += stride {
+        v := uint16(pix[i]>>3)<<11 | uint16(pix[i+1]>>2)<<5 | uint16(pix[i+2]>>3)
+        if bigEndian {
+            out = binary.BigEndian.AppendUint16(out, v)
+        } else {
+            out = binary.LittleEndian.AppendUint16(out, v)
+        }
+    }
+    return out
+}
+
+// cIdentifier deriva el nombre de la variable C del nombre de archivo
+func cIdentifier(input EncodeInput) string {
+    return strings.ToLower(codeTypeName(input))
+}
+
+// writeCBytes escribe un array de bytes en hexadecimal, 12 por línea
+func writeCBytes(b *strings.Builder, data []byte) {
+    for i, v := range data {
+        if i%12 == 0 {
+            b.WriteString("\n    ")
+        } else {
+            b.WriteByte(' ')
+        }
+        fmt.Fprintf(b, "0x%02x,", v)
+    }
+    b.WriteString("\n")
+}
+
+// cHeaderFormat escribe una cabecera C con el bitmap del icono. La opción
+// "layout" elige el formato de píxel:
+//   - mono: 1 bit por píxel, MSB primero, filas alineadas a byte
+//   - gray4: 4 bits por píxel (0 apagado, 15 encendido), píxel izquierdo en el nibble alto
+//   - rgb565: 2 bytes por píxel compuesto sobre "background" (negro por defecto)
+//   - indexed: paleta RGB565 en <nombre>_palette e índices de 1, 2, 4 u 8 bits
+//     según el tamaño de la paleta; admite las opciones de quantize.go ("colors",
+//     16 por defecto, "palette" y "dither") sobre la imagen compuesta en "background"
+//   - lvgl: descriptor lv_img_dsc_t de LVGL 8; "colorFormat" true_color_alpha
+//     (RGB565 + alfa, por defecto), alpha_1bit, alpha_4bit o indexed_1bit a
+//     indexed_8bit (paleta cuantizada con alfa seguida de los índices)
+type cHeaderFormat struct{}
+
+func (cHeaderFormat) Name() string      { return "c" }
+func (cHeaderFormat) Extension() string { return "h" }
+func (cHeaderFormat) Vector() bool      { return false }
+
+func (cHeaderFormat) Encode(w io.Writer, input EncodeInput, options map[string]interface{}) error {
+    if input.Image == nil {
+        return fmt.Errorf("el formato c necesita una imagen rasterizada")
+    }
+    layout, _ := options["layout"].(string)
+    if layout == "" {
+        layout = "mono"
+    }
+    bounds := input.Image.Bounds()
+    width, height := bounds.Dx(), bounds.Dy()
+    
+    levels := func(bits int) ([]byte, error) {
+        bitmap, err := inkLevels(input.Image, options)
+        if err != nil {
+            return nil, err
+        }
+        values, err := quantizeLevels(bitmap, bits, options)
+        if err != nil {
+            return nil, err
+        }
+        return packBits(values, width, height, bits, true), nil
+    }
+    
+    var data, palette []byte
+    var err error
+    description := ""
+    lvglFormat := ""
+    switch layout {
+    case "mono":
+        data, err = levels(1)
+        description = "1 bit por píxel, MSB primero, filas alineadas a byte"
+    case "gray4":
+        data, err = levels(4)
+        description = "4 bits por píxel, píxel izquierdo en el nibble alto"
+    case "rgb565":
+        data, err = rgb565(input.Image, options)
+        description = "RGB565 little endian"
+        if optionBool(options, "bigEndian", false) {
+            description = "RGB565 big endian"
+        }
+    case "indexed":
+        var q quantizeOptions
+        var flat image.Image
+        if q, err = parseQuantizeOptions(options, 16, false); err != nil {
+            return err
+        }
+        background, _ := options["background"].(string)
+        if background == "" {
+            background = "black"
+        }
+        if flat, err = flattenImage(input.Image, background); err != nil {
+            return err
+        }
+        paletted := quantizeImage(flat, q)
+        bits := indexBits(len(paletted.Palette))
+        data = packBits(paletted.Pix, width, height, bits, true)
+        for _, c := range paletted.Palette {
+            r, g, b, _ := c.RGBA()
+            palette = append(palette, uint8(r>>8), uint8(g>>8), uint8(b>>8), 0)
+        }
+        palette = packRGB565(palette, 4, optionBool(options, "bigEndian", false))
+        description = fmt.Sprintf("%d bits por índice en %s_palette (RGB565, %d colores), MSB primero", bits, cIdentifier(input), len(paletted.Palette))
+    case "lvgl":
+        colorFormat, _ := options["colorFormat"].(string)
+        switch colorFormat {
+        case "", "true_color_alpha":
+            // LVGL guarda cada píxel como RGB565 seguido del alfa; "bigEndian"
+            // equivale a LV_COLOR_16_SWAP
+            src := imaging.Clone(input.Image)
+            colors := packRGB565(src.Pix, 4, optionBool(options, "bigEndian", false))
+            for i := 0; i < width*height; i++ {
+                data = append(data, colors[i*2], colors[i*2+1], src.Pix[i*4+3])
+            }
+            lvglFormat = "LV_IMG_CF_TRUE_COLOR_ALPHA"
+        case "alpha_1bit":
+            data, err = levels(1)
+            lvglFormat = "LV_IMG_CF_ALPHA_1BIT"
+        case "alpha_4bit":
+            data, err = levels(4)
+            lvglFormat = "LV_IMG_CF_ALPHA_4BIT"
+        case "indexed_1bit", "indexed_2bit", "indexed_4bit", "indexed_8bit":
+            bits := map[string]int{"indexed_1bit": 1, "indexed_2bit": 2, "indexed_4bit": 4, "indexed_8bit": 8}[colorFormat]
+            q, err := parseQuantizeOptions(options, 1<<bits, false)
+            if err != nil {
+                return err
+            }
+            if q.Colors > 1<<bits {
+                return fmt.Errorf("%s admite como máximo %d colores: %d", colorFormat, 1<<bits, q.Colors)
+            }
+            // La paleta (lv_color32_t: B, G, R, A) ocupa siempre 2^bits entradas
+            paletted := quantizeImage(input.Image, q)
+            for i := 0; i < 1<<bits; i++ {
+                var c color.NRGBA
+                if i < len(paletted.Palette) {
+                    c = color.NRGBAModel.Convert(paletted.Palette[i]).(color.NRGBA)
+                }
+                data = append(data, c.B, c.G, c.R, c.A)
+            }
+            data = append(data, packBits(paletted.Pix, width, height, bits, true)...)
+            lvglFormat = "LV_IMG_CF_" + strings.ToUpper(colorFormat)
+        default:
+            return fmt.Errorf("colorFormat de LVGL no válido: %s (admitidos: true_color_alpha, alpha_1bit, alpha_4bit, indexed_1bit, indexed_2bit, indexed_4bit, indexed_8bit)", colorFormat)
+        }
+    default:
+        return fmt.Errorf("layout no válido: %s (admitidos: mono, gray4, rgb565, indexed, lvgl)", layout)
+    }
+    if err != nil {
+        return err
+    }
+    
+    name := cIdentifier(input)
+    macro := strings.ToUpper(name)
+    var b strings.Builder
+    fmt.Fprintf(&b, "// %s:%s %dx%d", input.Collection, input.Icon, width, height)
+    if input.Color != "" {
+        fmt.Fprintf(&b, " %s", input.Color)
+    }
+    b.WriteString("\n// Generado por iconexporter\n\n")
+    fmt.Fprintf(&b, "#ifndef %s_H\n#define %s_H\n\n", macro, macro)
+    
+    if layout == "lvgl" {
+        b.WriteString("#include \"lvgl.h\"\n\n")
+        fmt.Fprintf(&b, "static const LV_ATTRIBUTE_MEM_ALIGN uint8_t %s_map[] = {", name)
+        writeCBytes(&b, data)
+        b.WriteString("};\n\n")
+        fmt.Fprintf(&b, "static const lv_img_dsc_t %s = {\n", name)
+        fmt.Fprintf(&b, "    .header.cf = %s,\n    .header.always_zero = 0,\n    .header.reserved = 0,\n", lvglFormat)
+        fmt.Fprintf(&b, "    .header.w = %d,\n    .header.h = %d,\n", width, height)
+        fmt.Fprintf(&b, "    .data_size = %d,\n    .data = %s_map,\n};\n", len(data), name)
+    } else {
+        b.WriteString("#include <stdint.h>\n\n")
+        fmt.Fprintf(&b, "#define %s_WIDTH %d\n#define %s_HEIGHT %d\n\n", macro, width, macro, height)
+        if palette != nil {
+            fmt.Fprintf(&b, "static const uint8_t %s_palette[%d] = {", name, len(palette))
+            writeCBytes(&b, palette)
+            b.WriteString("};\n\n")
+        }
+        fmt.Fprintf(&b, "// %s\n", description)
+        fmt.Fprintf(&b, "static const uint8_t %s[%d] = {", name, len(data))
+        writeCBytes(&b, data)
+        b.WriteString("};\n")
+    }
+    fmt.Fprintf(&b, "\n#endif // %s_H\n", macro)
+    
+    _, err = io.WriteString(w, b.String())
+    return err
+}
+
+// xbmFormat escribe un bitmap X11 de 1 bit (LSB primero) como código C
+type xbmFormat struct{}
+
+func (xbmFormat) Name() string      { return "xbm" }
+func (xbmFormat) Extension() string { return "xbm" }
+func (xbmFormat) Vector() bool      { return false }
+
+func (xbmFormat) Encode(w io.Writer, input EncodeInput, options map[string]interface{}) error {
+    if input.Image == nil {
+        return fmt.Errorf("el formato xbm necesita una imagen rasterizada")
+    }
+    bitmap, err := inkLevels(input.Image, options)
+    if err != nil {
+        return err
+    }
+    values, err := quantizeLevels(bitmap, 1, options)
+    if err != nil {
+        return err
+    }
+    data := packBits(values, bitmap.Width, bitmap.Height, 1, false)
+    
+    name := cIdentifier(input)
+    var b strings.Builder
+    fmt.Fprintf(&b, "#define %s_width %d\n#define %s_height %d\n", name, bitmap.Width, name, bitmap.Height)
+    fmt.Fprintf(&b, "static unsigned char %s_bits[] = {", name)
+    writeCBytes(&b, data)
+    b.WriteString("};\n")
+    _, err = io.WriteString(w, b.String())
+    return err
+}
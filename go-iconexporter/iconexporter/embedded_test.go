@@ -0,0 +1,341 @@
+// iconexporter/embedded_test.go
+package iconexporter
+
+import (
+    "bytes"
+    "encoding/binary"
+    "image"
+    "image/color"
+    "reflect"
+    "regexp"
+    "strconv"
+    "strings"
+    "testing"
+)
+
+// embeddedTestImage es un icono de 10x2: la fila de arriba tiene cinco píxeles
+// rojos a la izquierda y la de abajo cinco azules a la derecha; el resto es
+// transparente
+func embeddedTestImage() *image.NRGBA {
+    img := image.NewNRGBA(image.Rect(0, 0, 10, 2))
+    for x := 0; x < 5; x++ {
+        img.SetNRGBA(x, 0, color.NRGBA{255, 0, 0, 255})
+        img.SetNRGBA(x+5, 1, color.NRGBA{0, 0, 255, 255})
+    }
+    return img
+}
+
+var cArrayPattern = regexp.MustCompile(`(?s)(\w+)\[\d*\] = \{(.*?)\};`)
+
+// cArrays lee los arrays de bytes de una cabecera C o XBM
+func cArrays(t *testing.T, source string) map[string][]byte {
+    t.Helper()
+    arrays := map[string][]byte{}
+    for _, m := range cArrayPattern.FindAllStringSubmatch(source, -1) {
+        var data []byte
+        for _, field := range strings.Fields(strings.ReplaceAll(m[2], ",", " ")) {
+            v, err := strconv.ParseUint(field, 0, 8)
+            if err != nil {
+                t.Fatalf("byte no válido %q en %s", field, m[1])
+            }
+            data = append(data, byte(v))
+        }
+        arrays[m[1]] = data
+    }
+    return arrays
+}
+
+func TestPackBits(t *testing.T) {
+    tests := []struct {
+        name          string
+        values        []uint8
+        width, height int
+        bits          int
+        msbFirst      bool
+        want          []byte
+    }{
+        {"1 bit MSB", []uint8{1, 0, 1, 1, 0, 0, 0, 0, 1}, 9, 1, 1, true, []byte{0xb0, 0x80}},
+        {"1 bit LSB", []uint8{1, 0, 1, 1, 0, 0, 0, 0, 1}, 9, 1, 1, false, []byte{0x0d, 0x01}},
+        {"filas alineadas a byte", []uint8{1, 1, 1, 0, 0, 1}, 3, 2, 1, true, []byte{0xe0, 0x20}},
+        {"4 bits", []uint8{15, 1, 2}, 3, 1, 4, true, []byte{0xf1, 0x20}},
+        {"2 bits", []uint8{3, 2, 1, 0, 3}, 5, 1, 2, true, []byte{0xe4, 0xc0}},
+        {"8 bits", []uint8{7, 200}, 2, 1, 8, true, []byte{7, 200}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := packBits(tt.values, tt.width, tt.height, tt.bits, tt.msbFirst); !bytes.Equal(got, tt.want) {
+                t.Fatalf("% x, se esperaba % x", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestPackRGB565(t *testing.T) {
+    pix := []byte{255, 0, 0, 255, 0, 255, 0, 255, 0, 0, 255, 255, 255, 255, 255, 255}
+    if got, want := packRGB565(pix, 4, false), []byte{0x00, 0xf8, 0xe0, 0x07, 0x1f, 0x00, 0xff, 0xff}; !bytes.Equal(got, want) {
+        t.Fatalf("little endian % x, se esperaba % x", got, want)
+    }
+    if got, want := packRGB565(pix, 4, true), []byte{0xf8, 0x00, 0x07, 0xe0, 0x00, 0x1f, 0xff, 0xff}; !bytes.Equal(got, want) {
+        t.Fatalf("big endian % x, se esperaba % x", got, want)
+    }
+}
+
+func TestInkLevels(t *testing.T) {
+    img := embeddedTestImage()
+    tests := []struct {
+        name    string
+        options map[string]interface{}
+        want    []uint8 // niveles de los píxeles (0,0), (5,0) y (5,1)
+    }{
+        {"alfa", nil, []uint8{255, 0, 255}},
+        {"alfa invertido", map[string]interface{}{"invert": true}, []uint8{0, 255, 0}},
+        {"luminancia sobre blanco", map[string]interface{}{"source": "luminance"}, []uint8{179, 0, 226}},
+        {"luminancia sobre negro", map[string]interface{}{"source": "luminance", "background": "black"}, []uint8{179, 255, 226}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            bitmap, err := inkLevels(img, tt.options)
+            if err != nil {
+                t.Fatal(err)
+            }
+            got := []uint8{bitmap.Levels[0], bitmap.Levels[5], bitmap.Levels[15]}
+            if !reflect.DeepEqual(got, tt.want) {
+                t.Fatalf("niveles %v, se esperaban %v", got, tt.want)
+            }
+        })
+    }
+    if _, err := inkLevels(img, map[string]interface{}{"source": "red"}); err == nil {
+        t.Fatal("se esperaba un error con source no válido")
+    }
+}
+
+func TestQuantizeLevels(t *testing.T) {
+    gray := levelBitmap{8, 8, bytes.Repeat([]byte{128}, 64)}
+    ramp := levelBitmap{5, 1, []uint8{0, 60, 128, 200, 255}}
+    on := func(values []uint8) int {
+        n := 0
+        for _, v := range values {
+            n += int(v)
+        }
+        return n
+    }
+    
+    tests := []struct {
+        name    string
+        bitmap  levelBitmap
+        bits    int
+        options map[string]interface{}
+        check   func(values []uint8) bool
+    }{
+        {"umbral por defecto", ramp, 1, nil, func(v []uint8) bool { return reflect.DeepEqual(v, []uint8{0, 0, 1, 1, 1}) }},
+        {"umbral 200", ramp, 1, map[string]interface{}{"threshold": 200}, func(v []uint8) bool { return reflect.DeepEqual(v, []uint8{0, 0, 0, 1, 1}) }},
+        {"4 bits al nivel más cercano", ramp, 4, nil, func(v []uint8) bool { return reflect.DeepEqual(v, []uint8{0, 4, 8, 12, 15}) }},
+        // Con tramado un gris medio enciende aproximadamente la mitad
+        {"ordered", gray, 1, map[string]interface{}{"dither": "ordered"}, func(v []uint8) bool { return on(v) == 32 }},
+        {"floyd-steinberg", gray, 1, map[string]interface{}{"dither": "floyd-steinberg"}, func(v []uint8) bool { return on(v) >= 30 && on(v) <= 34 }},
+        {"dither true", gray, 1, map[string]interface{}{"dither": true}, func(v []uint8) bool { return on(v) >= 30 && on(v) <= 34 }},
+        {"sin tramado", gray, 1, map[string]interface{}{"dither": false}, func(v []uint8) bool { return on(v) == 64 }},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            values, err := quantizeLevels(tt.bitmap, tt.bits, tt.options)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if !tt.check(values) {
+                t.Fatalf("valores inesperados: %v", values)
+            }
+        })
+    }
+    
+    for _, options := range []map[string]interface{}{{"threshold": 256}, {"threshold": -1}, {"dither": "atkinson"}} {
+        if _, err := quantizeLevels(ramp, 1, options); err == nil {
+            t.Errorf("%v: se esperaba un error", options)
+        }
+    }
+}
+
+func TestCHeaderEncode(t *testing.T) {
+    tests := []struct {
+        name     string
+        options  map[string]interface{}
+        array    string
+        want     []byte
+        contains []string
+    }{
+        {
+            name:     "mono",
+            options:  nil,
+            array:    "icono_24",
+            want:     []byte{0xf8, 0x00, 0x07, 0xc0},
+            contains: []string{"#define ICONO_24_WIDTH 10\n#define ICONO_24_HEIGHT 2\n", "// 1 bit por píxel, MSB primero, filas alineadas a byte\n"},
+        },
+        {
+            name:    "mono por luminancia con umbral",
+            options: map[string]interface{}{"source": "luminance", "threshold": 200},
+            array:   "icono_24",
+            want:    []byte{0x00, 0x00, 0x07, 0xc0},
+        },
+        {
+            name:    "gray4",
+            options: map[string]interface{}{"layout": "gray4"},
+            array:   "icono_24",
+            want:    []byte{0xff, 0xff, 0xf0, 0x00, 0x00, 0x00, 0x00, 0x0f, 0xff, 0xff},
+        },
+        {
+            name:     "rgb565 big endian",
+            options:  map[string]interface{}{"layout": "rgb565", "bigEndian": true},
+            array:    "icono_24",
+            want:     bytes.Join([][]byte{bytes.Repeat([]byte{0xf8, 0x00}, 5), make([]byte, 10), make([]byte, 10), bytes.Repeat([]byte{0x00, 0x1f}, 5)}, nil),
+            contains: []string{"// RGB565 big endian\n"},
+        },
+        {
+            name:     "lvgl alpha_1bit",
+            options:  map[string]interface{}{"layout": "lvgl", "colorFormat": "alpha_1bit"},
+            array:    "icono_24_map",
+            want:     []byte{0xf8, 0x00, 0x07, 0xc0},
+            contains: []string{"#include \"lvgl.h\"", ".header.cf = LV_IMG_CF_ALPHA_1BIT,", ".header.w = 10,\n    .header.h = 2,", ".data_size = 4,"},
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            input := EncodeInput{Collection: "prueba", Icon: "icono", Color: "red", Image: embeddedTestImage(), FileName: "Icono-24.h"}
+            var buf bytes.Buffer
+            if err := (cHeaderFormat{}).Encode(&buf, input, tt.options); err != nil {
+                t.Fatal(err)
+            }
+            source := buf.String()
+            if got := cArrays(t, source)[tt.array]; !bytes.Equal(got, tt.want) {
+                t.Fatalf("%s = % x, se esperaba % x\n%s", tt.array, got, tt.want, source)
+            }
+            for _, want := range append([]string{"// prueba:icono 10x2 red\n", "#ifndef ICONO_24_H\n#define ICONO_24_H\n", "#endif // ICONO_24_H\n"}, tt.contains...) {
+                if !strings.Contains(source, want) {
+                    t.Errorf("falta %q en:\n%s", want, source)
+                }
+            }
+        })
+    }
+}
+
+// Los índices de la paleta reproducen los colores del icono compuesto sobre negro
+func TestCHeaderIndexed(t *testing.T) {
+    input := EncodeInput{Icon: "icono", Image: embeddedTestImage(), FileName: "icono.h"}
+    for _, options := range []map[string]interface{}{
+        {"layout": "indexed"},
+        {"layout": "lvgl", "colorFormat": "indexed_2bit"},
+    } {
+        var buf bytes.Buffer
+        if err := (cHeaderFormat{}).Encode(&buf, input, options); err != nil {
+            t.Fatal(err)
+        }
+        arrays := cArrays(t, buf.String())
+        
+        // Colores en RGB565 de cada entrada de la paleta
+        var palette []uint16
+        var indices []byte
+        if options["layout"] == "indexed" {
+            for i := 0; i+1 < len(arrays["icono_palette"]); i += 2 {
+                palette = append(palette, binary.LittleEndian.Uint16(arrays["icono_palette"][i:]))
+            }
+            indices = arrays["icono"]
+        } else {
+            data := arrays["icono_map"]
+            for i := 0; i < 4; i++ {
+                b, g, r := data[i*4], data[i*4+1], data[i*4+2]
+                palette = append(palette, uint16(r>>3)<<11|uint16(g>>2)<<5|uint16(b>>3))
+            }
+            indices = data[16:]
+        }
+        if len(indices) != 6 {
+            t.Fatalf("%v: %d bytes de índices, se esperaban 6 (2 bits, 3 bytes por fila)", options, len(indices))
+        }
+        
+        want := []uint16{0xf800, 0x0000, 0x0000, 0x001f}
+        for i, p := range [][2]int{{0, 0}, {9, 0}, {0, 1}, {9, 1}} {
+            x, y := p[0], p[1]
+            index := indices[y*3+x/4] >> (6 - 2*(x%4)) & 3
+            if int(index) >= len(palette) || palette[index] != want[i] {
+                t.Errorf("%v: píxel %d,%d con índice %d de %04x, se esperaba %04x", options, x, y, index, palette, want[i])
+            }
+        }
+    }
+}
+
+func TestCHeaderErrors(t *testing.T) {
+    input := EncodeInput{Icon: "icono", Image: embeddedTestImage()}
+    for _, options := range []map[string]interface{}{
+        {"layout": "rgb888"},
+        {"layout": "lvgl", "colorFormat": "rgb"},
+        {"layout": "lvgl", "colorFormat": "indexed_1bit", "colors": 4},
+        {"layout": "mono", "dither": "atkinson"},
+    } {
+        if err := (cHeaderFormat{}).Encode(&bytes.Buffer{}, input, options); err == nil {
+            t.Errorf("%v: se esperaba un error", options)
+        }
+    }
+    if err := (cHeaderFormat{}).Encode(&bytes.Buffer{}, EncodeInput{Icon: "icono"}, nil); err == nil {
+        t.Error("se esperaba un error sin imagen")
+    }
+}
+
+func TestXbmEncode(t *testing.T) {
+    input := EncodeInput{Icon: "icono", Image: embeddedTestImage(), FileName: "bell.xbm"}
+    var buf bytes.Buffer
+    if err := (xbmFormat{}).Encode(&buf, input, nil); err != nil {
+        t.Fatal(err)
+    }
+    want := "#define bell_width 10\n#define bell_height 2\nstatic unsigned char bell_bits[] = {\n    0x1f, 0x00, 0xe0, 0x03,\n};\n"
+    if buf.String() != want {
+        t.Fatalf("\n%s\nse esperaba\n%s", buf.String(), want)
+    }
+}
+
+// El BMP de 1 bit se lee a mano: x/image/bmp no admite paletas de 1 bit
+func TestEncodeMonoBMP(t *testing.T) {
+    tests := []struct {
+        name    string
+        options map[string]interface{}
+        palette []byte
+        rows    []byte // filas de abajo arriba, con relleno a 4 bytes
+    }{
+        {
+            name:    "blanco y negro",
+            options: map[string]interface{}{"bits": 1},
+            palette: []byte{0xff, 0xff, 0xff, 0, 0, 0, 0, 0},
+            rows:    []byte{0x07, 0xc0, 0, 0, 0xf8, 0x00, 0, 0},
+        },
+        {
+            name:    "colores e inversión",
+            options: map[string]interface{}{"bits": 1, "invert": true, "foreground": "#ff0000", "background": "#0000ff"},
+            palette: []byte{0xff, 0, 0, 0, 0, 0, 0xff, 0},
+            rows:    []byte{0xf8, 0x00, 0, 0, 0x07, 0xc0, 0, 0},
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            var buf bytes.Buffer
+            if err := encodeBMP(&buf, embeddedTestImage(), tt.options); err != nil {
+                t.Fatal(err)
+            }
+            data := buf.Bytes()
+            le32 := func(offset int) int { return int(binary.LittleEndian.Uint32(data[offset:])) }
+            le16 := func(offset int) int { return int(binary.LittleEndian.Uint16(data[offset:])) }
+            
+            if string(data[:2]) != "BM" || le32(2) != len(data) || le32(10) != 62 {
+                t.Fatalf("cabecera de archivo no válida: % x", data[:14])
+            }
+            if le32(14) != 40 || le32(18) != 10 || le32(22) != 2 || le16(26) != 1 || le16(28) != 1 || le32(30) != 0 {
+                t.Fatalf("cabecera DIB no válida: % x", data[14:54])
+            }
+            if le32(34) != 8 || le32(38) != 2835 || le32(46) != 2 {
+                t.Fatalf("tamaño, resolución o colores no válidos: % x", data[34:54])
+            }
+            if !bytes.Equal(data[54:62], tt.palette) {
+                t.Fatalf("paleta % x, se esperaba % x", data[54:62], tt.palette)
+            }
+            if !bytes.Equal(data[62:], tt.rows) {
+                t.Fatalf("píxeles % x, se esperaban % x", data[62:], tt.rows)
+            }
+        })
+    }
+}
@@ -0,0 +1,36 @@
+// iconexporter/eps.go
+package iconexporter
+
+import (
+    "fmt"
+    "io"
+    "math"
+)
+
+// epsFormat convierte el SVG preparado en un EPS vectorial
+type epsFormat struct{}
+
+func init() {
+    mustRegisterFormat(epsFormat{})
+}
+
+func (epsFormat) Name() string      { return "eps" }
+func (epsFormat) Extension() string { return "eps" }
+func (epsFormat) Vector() bool      { return true }
+
+func (epsFormat) Encode(w io.Writer, input EncodeInput, options map[string]interface{}) error {
+    shapes, box, err := flattenSvg(input.SVG, input.Color, input.warn)
+    if err != nil {
+        return err
+    }
+    width, height := vectorPageSize(input, options)
+    
+    painter := &vectorPainter{postscript: true, warn: input.warn}
+    painter.drawIcon(shapes, box, 0, 0, width, height)
+    
+    // Los títulos van en el comentario DSC; los paréntesis se escapan como en PDF
+    _, err = fmt.Fprintf(w, "%%!PS-Adobe-3.0 EPSF-3.0\n%%%%BoundingBox: 0 0 %d %d\n%%%%HiResBoundingBox: 0 0 %s %s\n%%%%Title: %s\n%%%%Creator: iconexporter\n%%%%LanguageLevel: 2\n%%%%Pages: 1\n%%%%EndComments\n%%%%Page: 1 1\n%sshowpage\n%%%%EOF\n",
+        int(math.Ceil(width)), int(math.Ceil(height)), formatNumber(width, 3), formatNumber(height, 3),
+        pdfString(input.Collection+":"+input.Icon), painter.b.String())
+    return err
+}
@@ -0,0 +1,80 @@
+// iconexporter/eps_test.go
+package iconexporter
+
+import (
+    "reflect"
+    "strings"
+    "testing"
+)
+
+func TestEpsEncode(t *testing.T) {
+    tests := []struct {
+        name     string
+        body     string
+        options  map[string]interface{}
+        contains []string
+        warnings []string
+    }{
+        {
+            name: "cabecera DSC",
+            body: `<path d="M2 2h4v4z"/>`,
+            contains: []string{
+                "%!PS-Adobe-3.0 EPSF-3.0\n%%BoundingBox: 0 0 24 24\n%%HiResBoundingBox: 0 0 24 24\n%%Title: (prueba:icono)\n",
+                "%%EndComments\n%%Page: 1 1\n",
+                "showpage\n%%EOF\n",
+            },
+        },
+        {
+            name:     "BoundingBox redondeado hacia arriba",
+            body:     `<path d="M2 2h4v4z"/>`,
+            options:  map[string]interface{}{"dpi": 96},
+            contains: []string{"%%BoundingBox: 0 0 18 18\n%%HiResBoundingBox: 0 0 18 18\n", "[0.75 0 0 -0.75 0 18] concat\n"},
+        },
+        {
+            name:     "relleno",
+            body:     `<path fill="#ff0000" d="M2 2h4v4z"/>`,
+            contains: []string{"gsave\n[1 0 0 -1 0 24] concat\n", "newpath\n2 2 moveto\n6 2 lineto\n6 6 lineto\nclosepath\n1 0 0 setrgbcolor\nfill\ngrestore\n"},
+        },
+        {
+            name: "evenodd con trazo conserva el trayecto",
+            body: `<path fill="#00ff00" fill-rule="evenodd" stroke="#0000ff" stroke-linecap="square" d="M0 0h10v10H0z M2 2h4v4H2z"/>`,
+            contains: []string{
+                "1 setlinewidth\n2 setlinecap 0 setlinejoin\n4 setmiterlimit\n",
+                "gsave\n0 1 0 setrgbcolor\neofill\ngrestore\n0 0 1 setrgbcolor\nstroke\n",
+            },
+        },
+        {
+            name:     "trazo discontinuo",
+            body:     `<path fill="none" stroke="red" stroke-dasharray="2,1" d="M2 2L6 6"/>`,
+            contains: []string{"[2 1] 0 setdash\n", "1 0 0 setrgbcolor\nstroke\n"},
+        },
+        {
+            name:     "transparencia sin soporte",
+            body:     `<path fill="red" fill-opacity="0.5" d="M2 2h4v4z"/>`,
+            contains: []string{"1 0 0 setrgbcolor\nfill\n"},
+            warnings: []string{"la transparencia no está soportada en PostScript, se dibuja opaco"},
+        },
+        {
+            name:     "dasharray no válido",
+            body:     `<path fill="none" stroke="red" stroke-dasharray="2 -1" d="M2 2L6 6"/>`,
+            contains: []string{"stroke\n"},
+            warnings: []string{"stroke-dasharray no válido: 2 -1, se dibuja continuo"},
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got, warnings := encodeWith(t, epsFormat{}, testSvg(tt.body), "", tt.options)
+            for _, want := range tt.contains {
+                if !strings.Contains(got, want) {
+                    t.Errorf("falta %q en:\n%s", want, got)
+                }
+            }
+            if strings.Count(got, "gsave") != strings.Count(got, "grestore") {
+                t.Errorf("gsave y grestore desequilibrados:\n%s", got)
+            }
+            if !reflect.DeepEqual(warnings, tt.warnings) {
+                t.Errorf("avisos %q, se esperaban %q", warnings, tt.warnings)
+            }
+        })
+    }
+}
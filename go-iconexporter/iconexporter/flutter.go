@@ -0,0 +1,260 @@
+// iconexporter/flutter.go
+package iconexporter
+
+import (
+    "fmt"
+    "io"
+    "path/filepath"
+    "regexp"
+    "strings"
+    "time"
+)
+
+// FlutterConfig configura el paquete Flutter con la fuente de iconos y la clase
+// de IconData. La fuente usa IconFontConfig (nombre y archivo de códigos), de
+// modo que comparte los códigos con ExportIconFont.
+type FlutterConfig struct {
+    OutputDir   string `json:"outputDir"`
+    ClassName   string `json:"className"`
+    PackageName string `json:"packageName"` // fontPackage si la fuente vive en un paquete
+    NamePattern string `json:"namePattern"`
+}
+
+// Palabras reservadas de Dart que no pueden usarse como nombre de campo
+var dartReservedWords = map[string]bool{
+    "abstract": true, "as": true, "assert": true, "async": true, "await": true, "break": true,
+    "case": true, "catch": true, "class": true, "const": true, "continue": true, "covariant": true,
+    "default": true, "deferred": true, "do": true, "dynamic": true, "else": true, "enum": true,
+    "export": true, "extends": true, "extension": true, "external": true, "factory": true,
+    "false": true, "final": true, "finally": true, "for": true, "function": true, "get": true,
+    "hide": true, "if": true, "implements": true, "import": true, "in": true, "interface": true,
+    "is": true, "late": true, "library": true, "mixin": true, "new": true, "null": true, "on": true,
+    "operator": true, "part": true, "required": true, "rethrow": true, "return": true, "set": true,
+    "show": true, "static": true, "super": true, "switch": true, "sync": true, "this": true,
+    "throw": true, "true": true, "try": true, "typedef": true, "var": true, "void": true,
+    "while": true, "with": true, "yield": true,
+}
+
+var (
+    validDartClassName   = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)
+    validDartPackageName = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+    dartWordBoundary     = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+)
+
+// flutterConfig completa la configuración con valores por defecto y la valida
+func (e *IconExporter) flutterConfig() (FlutterConfig, error) {
+    cfg := e.config.Flutter
+    if cfg.OutputDir == "" {
+        cfg.OutputDir = filepath.Join(e.config.OutputDir, "flutter")
+    }
+    if cfg.ClassName == "" {
+        cfg.ClassName = "AppIcons"
+    }
+    if !validDartClassName.MatchString(cfg.ClassName) {
+        return cfg, fmt.Errorf("className no válido para Dart: %s", cfg.ClassName)
+    }
+    if cfg.PackageName != "" && !validDartPackageName.MatchString(cfg.PackageName) {
+        return cfg, fmt.Errorf("packageName no válido para Dart: %s", cfg.PackageName)
+    }
+    if cfg.NamePattern == "" {
+        cfg.NamePattern = "{collection}-{icon}"
+    }
+    return cfg, nil
+}
+
+// dartFieldName genera el nombre del campo con FileNaming.Case; si el caso no
+// produce un identificador válido se usa camelCase
+func (e *IconExporter) dartFieldName(pattern, collection, iconName string) string {
+    name := strings.ReplaceAll(pattern, "{collection}", collection)
+    name = strings.ReplaceAll(name, "{icon}", iconName)
+    name = invalidComponentChars.ReplaceAllString(name, "-")
+    
+    field := e.applyCase(name, e.config.FileNaming.Case)
+    if strings.Contains(field, "-") {
+        field = e.applyCase(name, "camel")
+    }
+    if field == "" || (field[0] >= '0' && field[0] <= '9') {
+        field = "icon" + e.applyCase("-"+name, "pascal")
+    }
+    if dartReservedWords[field] {
+        field += "Icon"
+    }
+    return field
+}
+
+// dartFieldNames resuelve el campo de cada glifo; los repetidos llevan sufijo
+// y generan un aviso
+func (e *IconExporter) dartFieldNames(cfg FlutterConfig, glyphs []iconFontGlyph, warn func(message string)) []string {
+    fields := make([]string, len(glyphs))
+    // values es el mapa por nombre de la propia clase
+    seen := map[string]int{"values": 1}
+    for i, glyph := range glyphs {
+        field := e.dartFieldName(cfg.NamePattern, glyph.Entry.Collection, glyph.Entry.Name)
+        if seen[field]++; seen[field] > 1 {
+            // El campo con sufijo también puede existir ya (bell, bell, bell2)
+            count := seen[field]
+            renamed := fmt.Sprintf("%s%d", field, count)
+            for seen[renamed] > 0 {
+                count++
+                renamed = fmt.Sprintf("%s%d", field, count)
+            }
+            seen[renamed]++
+            warn(fmt.Sprintf("campo %s repetido, se usa %s", field, renamed))
+            field = renamed
+        }
+        fields[i] = field
+    }
+    return fields
+}
+
+// flutterDart genera la clase con un IconData por icono y un mapa por nombre
+func flutterDart(cfg FlutterConfig, fontName string, fields []string, glyphs []iconFontGlyph) string {
+    var b strings.Builder
+    b.WriteString("// GENERATED CODE - DO NOT MODIFY BY HAND\n\n")
+    b.WriteString("import 'package:flutter/widgets.dart';\n\n")
+    fmt.Fprintf(&b, "class %s {\n  %s._();\n\n", cfg.ClassName, cfg.ClassName)
+    fmt.Fprintf(&b, "  static const String _fontFamily = '%s';\n", fontName)
+    if cfg.PackageName != "" {
+        fmt.Fprintf(&b, "  static const String? _fontPackage = '%s';\n", cfg.PackageName)
+    } else {
+        b.WriteString("  static const String? _fontPackage = null;\n")
+    }
+    b.WriteString("\n")
+    
+    for i, glyph := range glyphs {
+        fmt.Fprintf(&b, "  /// %s:%s\n", glyph.Entry.Collection, glyph.Entry.Name)
+        fmt.Fprintf(&b, "  static const IconData %s = IconData(0x%x, fontFamily: _fontFamily, fontPackage: _fontPackage);\n",
+            fields[i], glyph.Codepoint)
+    }
+    
+    b.WriteString("\n  /// Iconos por nombre \"colección:icono\"\n")
+    b.WriteString("  static const Map<String, IconData> values = {\n")
+    for i, glyph := range glyphs {
+        fmt.Fprintf(&b, "    '%s:%s': %s,\n", glyph.Entry.Collection, glyph.Entry.Name, fields[i])
+    }
+    b.WriteString("  };\n}\n")
+    return b.String()
+}
+
+// flutterPubspec genera el fragmento de pubspec.yaml que declara la fuente
+func flutterPubspec(cfg FlutterConfig, fontName string) string {
+    target := "del pubspec.yaml de la aplicación"
+    if cfg.PackageName != "" {
+        target = "del pubspec.yaml del paquete " + cfg.PackageName
+    }
+    return fmt.Sprintf(`# Añadir a la sección flutter %s
+flutter:
+  fonts:
+    - family: %s
+      fonts:
+        - asset: fonts/%s.ttf
+`, target, fontName, fontName)
+}
+
+// ExportFlutter genera un paquete Flutter con la fuente de iconos, una clase
+// Dart con un IconData por icono y el fragmento de pubspec.yaml. Los códigos se
+// leen y guardan en el mismo archivo que la fuente de iconos, así que no cambian
+// al añadir iconos.
+func (e *IconExporter) ExportFlutter() (ExportSummary, error) {
+    startTime := time.Now()
+    
+    cfg, err := e.flutterConfig()
+    if err != nil {
+        return ExportSummary{}, err
+    }
+    fontCfg, err := e.iconFontConfig()
+    if err != nil {
+        return ExportSummary{}, err
+    }
+    for _, dir := range []string{"fonts", "lib"} {
+        if err := e.ensureOutputDir(filepath.Join(cfg.OutputDir, dir)); err != nil {
+            return ExportSummary{}, fmt.Errorf("error creando directorio de salida: %w", err)
+        }
+    }
+    
+    entries, missing := e.collectIconEntries()
+    if len(entries) == 0 {
+        return ExportSummary{}, fmt.Errorf("no hay iconos para el paquete Flutter")
+    }
+    fmt.Printf("\n💙 Generando paquete Flutter: %s (%d iconos)\n", cfg.ClassName, len(entries))
+    
+    glyphs, err := e.iconFontGlyphs(fontCfg, entries)
+    if err != nil {
+        return ExportSummary{}, err
+    }
+    
+    var warnings []string
+    warn := func(message string) {
+        for _, existing := range warnings {
+            if existing == message {
+                return
+            }
+        }
+        warnings = append(warnings, message)
+    }
+    ttf, err := e.buildIconFont(fontCfg, glyphs, warn)
+    if err != nil {
+        return ExportSummary{}, err
+    }
+    
+    fields := e.dartFieldNames(cfg, glyphs, warn)
+    
+    var results []ExportResult
+    save := func(path, format string, write func(w io.Writer) error) {
+        result := ExportResult{Icon: cfg.ClassName, Format: format, Path: path}
+        if err := writeFile(path, write); err != nil {
+            fmt.Printf("❌ Error al guardar %s: %v\n", path, err)
+            result.Error = err.Error()
+        } else {
+            fmt.Printf("✅ Exportado: %s\n", path)
+        }
+        results = append(results, result)
+    }
+    saveText := func(path, format, content string) {
+        save(path, format, func(w io.Writer) error {
+            _, err := io.WriteString(w, content)
+            return err
+        })
+    }
+    
+    save(filepath.Join(cfg.OutputDir, "fonts", fontCfg.FontName+".ttf"), "ttf", func(w io.Writer) error {
+        _, err := w.Write(ttf)
+        return err
+    })
+    // Los avisos de conversión se asocian a la fuente
+    results[0].Warnings = warnings
+    printWarnings(results[0])
+    
+    dartFile := strings.ToLower(dartWordBoundary.ReplaceAllString(cfg.ClassName, "${1}_${2}")) + ".dart"
+    saveText(filepath.Join(cfg.OutputDir, "lib", dartFile), "dart", flutterDart(cfg, fontCfg.FontName, fields, glyphs))
+    saveText(filepath.Join(cfg.OutputDir, "pubspec.fragment.yaml"), "yaml", flutterPubspec(cfg, fontCfg.FontName))
+    
+    processed, errors := 0, missing
+    for _, result := range results {
+        if result.Error != "" {
+            errors++
+        } else {
+            processed++
+        }
+    }
+    
+    duration := time.Since(startTime).Seconds()
+    e.printExportSummary(processed, errors, duration)
+    
+    return ExportSummary{
+        Processed: processed,
+        Errors:    errors,
+        Duration:  duration,
+        Results:   results,
+        Sanitized: e.sanitizeReports(),
+    }, nil
+}
+
+// ExportFlutter genera el paquete Flutter con la configuración dada
+func ExportFlutter(config Config) (ExportSummary, error) {
+    exporter, err := NewIconExporter(config)
+    if err != nil {
+        return ExportSummary{}, err
+    }
+    return exporter.ExportFlutter()
+}
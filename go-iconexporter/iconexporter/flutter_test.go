@@ -0,0 +1,122 @@
+// iconexporter/flutter_test.go
+package iconexporter
+
+import (
+    "encoding/json"
+    "os"
+    "path/filepath"
+    "reflect"
+    "strings"
+    "testing"
+)
+
+func TestDartFieldName(t *testing.T) {
+    tests := []struct {
+        fileCase, pattern, collection, icon, want string
+    }{
+        {"", "{collection}-{icon}", "nonicons", "bell", "noniconsBell"},
+        {"snake", "{collection}-{icon}", "mdi", "home", "mdi_home"},
+        {"kebab", "{icon}", "mdi", "arrow-up", "arrowUp"},
+        {"", "{icon}", "mdi", "class", "classIcon"},
+        {"", "{icon}", "mdi", "3d-rotation", "icon3dRotation"},
+        {"", "{icon}", "mdi", "arrow_up.small", "arrowUpSmall"},
+    }
+    for _, tt := range tests {
+        e := newTestExporter(t, Config{FileNaming: FileNamingConfig{Case: tt.fileCase}})
+        if got := e.dartFieldName(tt.pattern, tt.collection, tt.icon); got != tt.want {
+            t.Errorf("dartFieldName(%q, %q) con caso %q = %q, se esperaba %q", tt.pattern, tt.icon, tt.fileCase, got, tt.want)
+        }
+    }
+}
+
+// Los campos repetidos reciben un sufijo que tampoco puede repetirse ni chocar
+// con el mapa values
+func TestDartFieldNamesUnique(t *testing.T) {
+    e := newTestExporter(t, Config{})
+    cfg := FlutterConfig{NamePattern: "{icon}"}
+    var glyphs []iconFontGlyph
+    for _, name := range []string{"bell", "bell_", "bell2", "values", "bell-"} {
+        glyphs = append(glyphs, iconFontGlyph{Entry: iconEntry{Collection: "x", Name: name}})
+    }
+    var warnings []string
+    fields := e.dartFieldNames(cfg, glyphs, func(message string) { warnings = append(warnings, message) })
+    if want := []string{"bell", "bell2", "bell22", "values2", "bell3"}; !reflect.DeepEqual(fields, want) {
+        t.Fatalf("campos %v, se esperaban %v", fields, want)
+    }
+    if len(warnings) != 4 {
+        t.Fatalf("avisos inesperados: %q", warnings)
+    }
+}
+
+func TestFlutterConfigErrors(t *testing.T) {
+    for name, cfg := range map[string]FlutterConfig{
+        "className":   {ClassName: "appIcons"},
+        "packageName": {PackageName: "My-Icons"},
+    } {
+        t.Run(name, func(t *testing.T) {
+            if _, err := NewIconExporter(Config{Collections: []string{"nonicons"}, Flutter: cfg}); err == nil {
+                t.Fatal("se esperaba un error")
+            }
+        })
+    }
+}
+
+// El paquete Flutter reutiliza los códigos ya asignados por la fuente de iconos
+func TestExportFlutter(t *testing.T) {
+    dir := t.TempDir()
+    if _, err := ExportIconFont(Config{Collections: []string{"devicon"}, OutputDir: dir}); err != nil {
+        t.Fatal(err)
+    }
+    e := newTestExporter(t, Config{OutputDir: dir, Flutter: FlutterConfig{ClassName: "BrandIcons", PackageName: "my_icons"}})
+    summary, err := e.ExportFlutter()
+    if err != nil {
+        t.Fatal(err)
+    }
+    if summary.Processed != 3 || summary.Errors != 0 {
+        t.Fatalf("resumen inesperado: %+v", summary)
+    }
+    
+    data, err := os.ReadFile(filepath.Join(dir, "icons.codepoints.json"))
+    if err != nil {
+        t.Fatal(err)
+    }
+    var codepoints map[string]int
+    if err := json.Unmarshal(data, &codepoints); err != nil {
+        t.Fatal(err)
+    }
+    if want := map[string]int{"devicon:angular": 0xe000, "nonicons:bell": 0xe001}; !reflect.DeepEqual(codepoints, want) {
+        t.Fatalf("códigos %v, se esperaban %v", codepoints, want)
+    }
+    
+    tests := []struct {
+        file     string
+        contains []string
+    }{
+        {"lib/brand_icons.dart", []string{
+            "class BrandIcons {\n  BrandIcons._();",
+            "static const String _fontFamily = 'icons';",
+            "static const String? _fontPackage = 'my_icons';",
+            "static const IconData noniconsBell = IconData(0xe001, fontFamily: _fontFamily, fontPackage: _fontPackage);",
+            "static const IconData deviconAngular = IconData(0xe000,",
+            "'nonicons:bell': noniconsBell,\n    'devicon:angular': deviconAngular,",
+        }},
+        {"pubspec.fragment.yaml", []string{"del paquete my_icons", "- family: icons", "- asset: fonts/icons.ttf"}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.file, func(t *testing.T) {
+            data, err := os.ReadFile(filepath.Join(dir, "flutter", tt.file))
+            if err != nil {
+                t.Fatal(err)
+            }
+            for _, want := range tt.contains {
+                if !strings.Contains(string(data), want) {
+                    t.Errorf("falta %q en:\n%s", want, data)
+                }
+            }
+        })
+    }
+    
+    if _, err := os.Stat(filepath.Join(dir, "flutter", "fonts", "icons.ttf")); err != nil {
+        t.Fatal(err)
+    }
+}
@@ -0,0 +1,188 @@
+// iconexporter/formats.go
+package iconexporter
+
+import (
+    "fmt"
+    "image"
+    "io"
+    "sort"
+    "sync"
+)
+
+// FormatEncoder describe un formato de salida. Los formatos vectoriales reciben
+// el SVG preparado; los raster reciben además la imagen ya rasterizada.
+//
+// Las aplicaciones pueden añadir formatos propios con RegisterFormat y usarlos
+// por nombre en Config.OutputFormats; sus opciones llegan desde
+// Config.FormatOptions[Name()].
+type FormatEncoder interface {
+    Name() string
+    Extension() string
+    Vector() bool
+    Encode(w io.Writer, input EncodeInput, options map[string]interface{}) error
+}
+
+// BundleEncoder es un formato que agrupa en un único archivo todos los tamaños
+// de un mismo icono y color (por ejemplo .ico o .icns). El exportador le pasa
+// los tamaños solicitados, o los de la opción "sizes" del formato si existe.
+type BundleEncoder interface {
+    FormatEncoder
+    EncodeBundle(w io.Writer, inputs []EncodeInput, options map[string]interface{}) error
+}
+
+// EncodeInput contiene los datos de una variante de icono
+type EncodeInput struct {
+    Collection string
+    Icon       string
+    Color      string
+    Width      int
+    Height     int
+    SVG        []byte
+    Image      image.Image        // nil en formatos vectoriales
+    Scale      float64            // factor de escala (@2x, @3x...); 0 si no se usan escalas
+    FileName   string             // nombre del archivo de salida, sin carpeta
+    Warn       func(message string) // avisos que se añaden al resultado del archivo
+    Optimized  func(original, optimized int) // bytes antes y después del optimizador SVG
+}
+
+// warn notifica un aviso si el exportador lo ha solicitado
+func (input EncodeInput) warn(message string) {
+    if input.Warn != nil {
+        input.Warn(message)
+    }
+}
+
+var (
+    formatRegistry   = map[string]FormatEncoder{}
+    formatRegistryMu sync.RWMutex
+)
+
+// Formatos conocidos que no se pueden generar, con el motivo. AVIF necesita
+// libaom o un codificador en WebAssembly; no hay ninguno en Go puro.
+var unsupportedFormats = map[string]string{
+    "avif": "no hay codificador AVIF en Go puro; exporta PNG y conviértelo con avifenc",
+}
+
+func init() {
+    mustRegisterFormat(svgFormat{})
+}
+
+// RegisterFormat añade un formato al registro; falla si el nombre ya existe
+func RegisterFormat(encoder FormatEncoder) error {
+    name := encoder.Name()
+    if name == "" {
+        return fmt.Errorf("el formato debe tener nombre")
+    }
+    
+    formatRegistryMu.Lock()
+    defer formatRegistryMu.Unlock()
+    
+    if _, exists := formatRegistry[name]; exists {
+        return fmt.Errorf("formato ya registrado: %s", name)
+    }
+    formatRegistry[name] = encoder
+    if !encoder.Vector() {
+        ValidRasterFormats[name] = true
+    }
+    return nil
+}
+
+// unregisterFormat quita un formato del registro; solo lo usan las pruebas
+func unregisterFormat(name string) {
+    formatRegistryMu.Lock()
+    defer formatRegistryMu.Unlock()
+    
+    delete(formatRegistry, name)
+    delete(ValidRasterFormats, name)
+}
+
+// mustRegisterFormat registra formatos integrados durante la inicialización
+func mustRegisterFormat(encoder FormatEncoder) {
+    if err := RegisterFormat(encoder); err != nil {
+        panic(err)
+    }
+}
+
+// LookupFormat busca un formato registrado por nombre
+func LookupFormat(name string) (FormatEncoder, bool) {
+    formatRegistryMu.RLock()
+    defer formatRegistryMu.RUnlock()
+    
+    encoder, ok := formatRegistry[name]
+    return encoder, ok
+}
+
+// RegisteredFormats lista los nombres de los formatos registrados, ordenados
+func RegisteredFormats() []string {
+    formatRegistryMu.RLock()
+    defer formatRegistryMu.RUnlock()
+    
+    names := make([]string, 0, len(formatRegistry))
+    for name := range formatRegistry {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    return names
+}
+
+// isBundleFormat indica si el formato agrupa varios tamaños en un archivo
+func isBundleFormat(format string) bool {
+    encoder, ok := LookupFormat(format)
+    if !ok {
+        return false
+    }
+    _, isBundle := encoder.(BundleEncoder)
+    return isBundle
+}
+
+// svgFormat escribe el SVG preparado. Con la opción "optimize" lo pasa antes
+// por optimizeSvg, redondeando a "precision" decimales (3 por defecto).
+type svgFormat struct{}
+
+func (svgFormat) Name() string      { return "svg" }
+func (svgFormat) Extension() string { return "svg" }
+func (svgFormat) Vector() bool      { return true }
+
+func (svgFormat) Encode(w io.Writer, input EncodeInput, options map[string]interface{}) error {
+    data := input.SVG
+    if optionBool(options, "optimize", false) {
+        optimized, err := optimizeSvg(data, optionInt(options, "precision", 3))
+        if err != nil {
+            input.warn(fmt.Sprintf("no se pudo optimizar, se escribe sin cambios: %v", err))
+        } else {
+            if input.Optimized != nil {
+                input.Optimized(len(data), len(optimized))
+            }
+            data = optimized
+        }
+    }
+    _, err := w.Write(data)
+    return err
+}
+
+// rasterFormat adapta un rasterEncoder a la interfaz FormatEncoder
+type rasterFormat struct {
+    name   string
+    encode rasterEncoder
+}
+
+func (f rasterFormat) Name() string      { return f.name }
+func (f rasterFormat) Extension() string { return f.name }
+func (f rasterFormat) Vector() bool      { return false }
+
+func (f rasterFormat) Encode(w io.Writer, input EncodeInput, options map[string]interface{}) error {
+    if input.Image == nil {
+        return fmt.Errorf("el formato %s necesita una imagen rasterizada", f.name)
+    }
+    
+    // Con factores de escala, la resolución sigue a la densidad (72 DPI en 1x)
+    if _, explicit := options["dpi"]; input.Scale > 0 && !explicit {
+        scaled := make(map[string]interface{}, len(options)+1)
+        for key, value := range options {
+            scaled[key] = value
+        }
+        scaled["dpi"] = int(72*input.Scale + 0.5)
+        options = scaled
+    }
+    return f.encode(w, input.Image, options)
+}
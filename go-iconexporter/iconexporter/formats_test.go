@@ -0,0 +1,133 @@
+// iconexporter/formats_test.go
+package iconexporter
+
+import (
+    "bytes"
+    "io"
+    "os"
+    "path/filepath"
+    "testing"
+)
+
+// textFormat es un formato vectorial de prueba que escribe colección:icono:color
+type textFormat struct{ name string }
+
+func (f textFormat) Name() string      { return f.name }
+func (f textFormat) Extension() string { return "text" }
+func (f textFormat) Vector() bool      { return true }
+
+func (f textFormat) Encode(w io.Writer, input EncodeInput, options map[string]interface{}) error {
+    _, err := io.WriteString(w, input.Collection+":"+input.Icon+":"+input.Color)
+    return err
+}
+
+func TestRegisterFormat(t *testing.T) {
+    tests := []struct {
+        name    string
+        encoder FormatEncoder
+        wantErr bool
+    }{
+        {"nuevo", textFormat{"prueba-texto"}, false},
+        {"duplicado", textFormat{"prueba-texto"}, true},
+        {"integrado", textFormat{"png"}, true},
+        {"sin nombre", textFormat{""}, true},
+    }
+    t.Cleanup(func() { unregisterFormat("prueba-texto") })
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if err := RegisterFormat(tt.encoder); (err != nil) != tt.wantErr {
+                t.Fatalf("error %v, se esperaba error: %v", err, tt.wantErr)
+            }
+        })
+    }
+    
+    if _, ok := LookupFormat("prueba-texto"); !ok {
+        t.Fatal("el formato registrado no se encuentra")
+    }
+    if ValidRasterFormats["prueba-texto"] {
+        t.Fatal("un formato vectorial no es raster")
+    }
+}
+
+func TestValidRasterFormats(t *testing.T) {
+    formats := ValidRasterFormats
+    for _, name := range []string{"png", "jpeg", "webp", "gif", "bmp", "tiff"} {
+        if !formats[name] {
+            t.Errorf("falta el formato raster %s", name)
+        }
+    }
+    if formats["svg"] {
+        t.Error("svg no es un formato raster")
+    }
+}
+
+// Los formatos raster registrados aparecen en ValidRasterFormats hasta que se
+// quitan del registro
+func TestValidRasterFormatsRegistered(t *testing.T) {
+    png, _ := LookupFormat("png")
+    encoder := rasterFormat{"prueba-raster", png.(rasterFormat).encode}
+    if err := RegisterFormat(encoder); err != nil {
+        t.Fatal(err)
+    }
+    if !ValidRasterFormats["prueba-raster"] {
+        t.Fatal("falta el formato registrado")
+    }
+    unregisterFormat("prueba-raster")
+    if _, ok := LookupFormat("prueba-raster"); ok || ValidRasterFormats["prueba-raster"] {
+        t.Fatal("el formato sigue registrado")
+    }
+}
+
+func TestCustomFormatExport(t *testing.T) {
+    if err := RegisterFormat(textFormat{"prueba-exportacion"}); err != nil {
+        t.Fatal(err)
+    }
+    t.Cleanup(func() { unregisterFormat("prueba-exportacion") })
+    e := newTestExporter(t, Config{
+        Collections:   []string{"nonicons"},
+        OutputFormats: []string{"prueba-exportacion", "svg"},
+        FileNaming:    FileNamingConfig{Case: "kebab"},
+    })
+    summary, err := e.ExportIcons()
+    if err != nil {
+        t.Fatal(err)
+    }
+    if summary.Errors != 0 || summary.Processed != 2 {
+        t.Fatalf("resumen inesperado: %+v", summary)
+    }
+    data, err := os.ReadFile(filepath.Join(e.config.OutputDir, "nonicons-bell-48x48.text"))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if string(data) != "nonicons:bell:red" {
+        t.Fatalf("contenido %q", data)
+    }
+}
+
+func TestRasterFormatScaleDPI(t *testing.T) {
+    encoder, _ := LookupFormat("png")
+    tests := []struct {
+        name    string
+        scale   float64
+        options map[string]interface{}
+        want    []byte // píxeles por metro del chunk pHYs
+    }{
+        {"@2x usa 144 DPI", 2, nil, []byte{0, 0, 0x16, 0x25}},
+        {"dpi explícito gana", 2, map[string]interface{}{"dpi": 72}, []byte{0, 0, 0x0b, 0x13}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            var buf bytes.Buffer
+            input := EncodeInput{Image: testImage(2, 2, patternSolid), Scale: tt.scale}
+            if err := encoder.Encode(&buf, input, tt.options); err != nil {
+                t.Fatal(err)
+            }
+            if !bytes.Contains(buf.Bytes(), append([]byte("pHYs"), tt.want...)) {
+                t.Fatal("resolución inesperada")
+            }
+        })
+    }
+    if err := encoder.Encode(&bytes.Buffer{}, EncodeInput{}, nil); err == nil {
+        t.Fatal("un formato raster sin imagen debe fallar")
+    }
+}
@@ -0,0 +1,218 @@
+// iconexporter/gocode.go
+package iconexporter
+
+import (
+    "fmt"
+    "go/format"
+    "go/token"
+    "io"
+    "path/filepath"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// Identificadores que declara el paquete generado y no pueden usar los iconos
+var goPackageReserved = map[string]bool{"Icon": true, "Names": true, "Lookup": true, "HTML": true, "FuncMap": true}
+
+// GoPackageConfig configura el paquete Go generado con los SVG de los iconos
+type GoPackageConfig struct {
+    PackageName string `json:"packageName"`
+    OutputDir   string `json:"outputDir"`
+    FileName    string `json:"fileName"`
+}
+
+// goPackageConfig completa la configuración con valores por defecto y la valida
+func (e *IconExporter) goPackageConfig() (GoPackageConfig, error) {
+    cfg := e.config.GoPackage
+    if cfg.PackageName == "" {
+        cfg.PackageName = "icons"
+    }
+    if !token.IsIdentifier(cfg.PackageName) || cfg.PackageName == "_" {
+        return cfg, fmt.Errorf("nombre de paquete Go no válido: %s", cfg.PackageName)
+    }
+    if cfg.OutputDir == "" {
+        cfg.OutputDir = filepath.Join(e.config.OutputDir, cfg.PackageName)
+    }
+    if cfg.FileName == "" {
+        cfg.FileName = cfg.PackageName + ".go"
+    }
+    if filepath.Ext(cfg.FileName) != ".go" || strings.HasSuffix(cfg.FileName, "_test.go") {
+        return cfg, fmt.Errorf("fileName debe ser un archivo .go que no sea de test: %s", cfg.FileName)
+    }
+    return cfg, nil
+}
+
+// goPackageSource genera el código del paquete; los iconos llegan ordenados y
+// con el identificador ya resuelto
+func (e *IconExporter) goPackageSource(cfg GoPackageConfig, identifiers []string, entries []iconEntry) ([]byte, error) {
+    var b strings.Builder
+    b.WriteString("// Code generated by iconexporter. DO NOT EDIT.\n\n")
+    fmt.Fprintf(&b, "// Package %s contiene los iconos SVG de: %s.\n", cfg.PackageName, strings.Join(e.config.Collections, ", "))
+    fmt.Fprintf(&b, "package %s\n\n", cfg.PackageName)
+    b.WriteString(`import (
+    "fmt"
+    "html/template"
+)
+
+// Icon es el contenido SVG de un icono
+type Icon string
+
+// String devuelve el SVG como texto
+func (i Icon) String() string { return string(i) }
+
+// Bytes devuelve una copia del SVG
+func (i Icon) Bytes() []byte { return []byte(i) }
+
+// HTML devuelve el SVG marcado como seguro para html/template
+func (i Icon) HTML() template.HTML { return template.HTML(i) }
+
+`)
+    
+    b.WriteString("const (\n")
+    for i, entry := range entries {
+        svg := e.prepareSvgBuffer(entry.Icon, e.config.DefaultSize[0], e.config.DefaultSize[1], "", e.svgIDPrefix(entry.Collection, entry.Name))
+        fmt.Fprintf(&b, "// %s es el icono %s:%s\n", identifiers[i], entry.Collection, entry.Name)
+        fmt.Fprintf(&b, "%s Icon = %s\n", identifiers[i], strconv.Quote(string(svg)))
+    }
+    b.WriteString(")\n\n")
+    
+    keys := make([]string, len(entries))
+    for i, entry := range entries {
+        keys[i] = entry.Collection + ":" + entry.Name
+    }
+    b.WriteString("var icons = map[string]Icon{\n")
+    for i, key := range keys {
+        fmt.Fprintf(&b, "%s: %s,\n", strconv.Quote(key), identifiers[i])
+    }
+    b.WriteString("}\n\n")
+    
+    sort.Strings(keys)
+    b.WriteString("var names = []string{\n")
+    for _, key := range keys {
+        fmt.Fprintf(&b, "%s,\n", strconv.Quote(key))
+    }
+    b.WriteString("}\n\n")
+    
+    b.WriteString(`// Names devuelve los nombres "prefijo:nombre" de todos los iconos, ordenados
+func Names() []string {
+    return append([]string(nil), names...)
+}
+
+// Lookup busca un icono por su nombre "prefijo:nombre"
+func Lookup(name string) (Icon, bool) {
+    icon, ok := icons[name]
+    return icon, ok
+}
+
+// HTML devuelve el icono listo para html/template o un error si no existe
+func HTML(name string) (template.HTML, error) {
+    icon, ok := icons[name]
+    if !ok {
+        return "", fmt.Errorf("icono no encontrado: %s", name)
+    }
+    return icon.HTML(), nil
+}
+
+// FuncMap devuelve las funciones de plantilla, p. ej. {{ icon "prefijo:nombre" }}
+func FuncMap() template.FuncMap {
+    return template.FuncMap{"icon": HTML}
+}
+`)
+    
+    return format.Source([]byte(b.String()))
+}
+
+// goIdentifiers resuelve el identificador PascalCase de cada icono; los
+// repetidos o reservados llevan sufijo y generan un aviso
+func (e *IconExporter) goIdentifiers(entries []iconEntry) ([]string, []string) {
+    var warnings []string
+    identifiers := make([]string, len(entries))
+    seen := map[string]int{}
+    for i, entry := range entries {
+        name := e.componentName("{collection}-{icon}", entry.Collection, entry.Name)
+        if goPackageReserved[name] {
+            name += "Icon"
+        }
+        if seen[name]++; seen[name] > 1 {
+            // El identificador con sufijo también puede existir ya (Bell, Bell, Bell2)
+            count := seen[name]
+            renamed := fmt.Sprintf("%s%d", name, count)
+            for seen[renamed] > 0 {
+                count++
+                renamed = fmt.Sprintf("%s%d", name, count)
+            }
+            seen[renamed]++
+            warnings = append(warnings, fmt.Sprintf("identificador %s repetido, se usa %s", name, renamed))
+            name = renamed
+        }
+        identifiers[i] = name
+    }
+    return identifiers, warnings
+}
+
+// ExportGoPackage genera un paquete Go con una constante por icono, la lista de
+// nombres, la búsqueda por "prefijo:nombre" y funciones para html/template.
+// El resultado es determinista, por lo que puede regenerarse con go:generate
+// mediante cmd/iconpkg:
+//
+//    //go:generate go run iconexporter/cmd/iconpkg -config icons.json
+func (e *IconExporter) ExportGoPackage() (ExportSummary, error) {
+    startTime := time.Now()
+    
+    cfg, err := e.goPackageConfig()
+    if err != nil {
+        return ExportSummary{}, err
+    }
+    if err := e.ensureOutputDir(cfg.OutputDir); err != nil {
+        return ExportSummary{}, fmt.Errorf("error creando directorio de salida: %w", err)
+    }
+    
+    entries, missing := e.collectIconEntries()
+    if len(entries) == 0 {
+        return ExportSummary{}, fmt.Errorf("no hay iconos para el paquete Go")
+    }
+    fmt.Printf("\n🐹 Generando paquete Go: %s (%d iconos)\n", cfg.PackageName, len(entries))
+    
+    identifiers, warnings := e.goIdentifiers(entries)
+    
+    result := ExportResult{Format: "go", Path: filepath.Join(cfg.OutputDir, cfg.FileName), Warnings: warnings}
+    source, err := e.goPackageSource(cfg, identifiers, entries)
+    if err == nil {
+        err = writeFile(result.Path, func(w io.Writer) error {
+            _, err := w.Write(source)
+            return err
+        })
+    }
+    processed, errors := 0, missing
+    if err != nil {
+        fmt.Printf("❌ Error al guardar %s: %v\n", result.Path, err)
+        result.Error = err.Error()
+        errors++
+    } else {
+        fmt.Printf("✅ Exportado: %s (%d iconos)\n", result.Path, len(entries))
+        printWarnings(result)
+        processed++
+    }
+    
+    duration := time.Since(startTime).Seconds()
+    e.printExportSummary(processed, errors, duration)
+    
+    return ExportSummary{
+        Processed: processed,
+        Errors:    errors,
+        Duration:  duration,
+        Results:   []ExportResult{result},
+        Sanitized: e.sanitizeReports(),
+    }, nil
+}
+
+// ExportGoPackage genera el paquete Go de los iconos con la configuración dada
+func ExportGoPackage(config Config) (ExportSummary, error) {
+    exporter, err := NewIconExporter(config)
+    if err != nil {
+        return ExportSummary{}, err
+    }
+    return exporter.ExportGoPackage()
+}
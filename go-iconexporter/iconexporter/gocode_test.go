@@ -0,0 +1,144 @@
+// iconexporter/gocode_test.go
+package iconexporter
+
+import (
+    "os"
+    "os/exec"
+    "path/filepath"
+    "reflect"
+    "strings"
+    "testing"
+)
+
+func TestGoIdentifiers(t *testing.T) {
+    icon := Icon{Body: `<path d="M0 0h1v1z"/>`, Width: 24, Height: 24, ViewBox: "0 0 24 24"}
+    tests := []struct {
+        name     string
+        icons    []string
+        want     []string
+        warnings int
+    }{
+        {"sin repetidos", []string{"bell", "angular"}, []string{"XBell", "XAngular"}, 0},
+        {"sufijo libre", []string{"bell", "bell_", "bell2"}, []string{"XBell", "XBell2", "XBell22"}, 2},
+        {"sufijo ya usado", []string{"bell", "bell2", "bell-", "bell."}, []string{"XBell", "XBell2", "XBell3", "XBell4"}, 2},
+    }
+    e := newTestExporter(t, Config{})
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            var entries []iconEntry
+            for _, name := range tt.icons {
+                entries = append(entries, iconEntry{"x", name, icon})
+            }
+            identifiers, warnings := e.goIdentifiers(entries)
+            if !reflect.DeepEqual(identifiers, tt.want) {
+                t.Fatalf("identificadores %v, se esperaban %v", identifiers, tt.want)
+            }
+            if len(warnings) != tt.warnings {
+                t.Fatalf("avisos %v, se esperaban %d", warnings, tt.warnings)
+            }
+        })
+    }
+}
+
+// Los nombres que declara el paquete generado llevan el sufijo Icon
+func TestGoIdentifiersReserved(t *testing.T) {
+    e := newTestExporter(t, Config{})
+    icon := Icon{Body: `<path d="M0 0h1v1z"/>`, Width: 24, Height: 24, ViewBox: "0 0 24 24"}
+    entries := []iconEntry{{"", "names", icon}, {"", "lookup", icon}, {"", "names-icon", icon}}
+    identifiers, _ := e.goIdentifiers(entries)
+    if want := []string{"NamesIcon", "LookupIcon", "NamesIcon2"}; !reflect.DeepEqual(identifiers, want) {
+        t.Fatalf("identificadores %v, se esperaban %v", identifiers, want)
+    }
+}
+
+func TestGoPackageConfigErrors(t *testing.T) {
+    for name, cfg := range map[string]GoPackageConfig{
+        "paquete":  {PackageName: "1x"},
+        "blank":    {PackageName: "_"},
+        "extensión": {FileName: "icons.txt"},
+        "test":     {FileName: "icons_test.go"},
+    } {
+        t.Run(name, func(t *testing.T) {
+            if _, err := NewIconExporter(Config{Collections: []string{"nonicons"}, GoPackage: cfg}); err == nil {
+                t.Fatal("se esperaba un error")
+            }
+        })
+    }
+}
+
+// El paquete generado compila y sus funciones devuelven los iconos
+func TestExportGoPackage(t *testing.T) {
+    e := newTestExporter(t, Config{})
+    summary, err := e.ExportGoPackage()
+    if err != nil {
+        t.Fatal(err)
+    }
+    if summary.Processed != 1 || summary.Errors != 0 {
+        t.Fatalf("resumen inesperado: %+v", summary)
+    }
+    
+    dir := e.config.OutputDir
+    source, err := os.ReadFile(filepath.Join(dir, "icons", "icons.go"))
+    if err != nil {
+        t.Fatal(err)
+    }
+    for _, want := range []string{
+        "// Code generated by iconexporter. DO NOT EDIT.",
+        "package icons",
+        "NoniconsBell Icon = ",
+        `"devicon:angular": DeviconAngular,`,
+    } {
+        if !strings.Contains(string(source), want) {
+            t.Errorf("falta %q en:\n%s", want, source)
+        }
+    }
+    
+    if _, err := exec.LookPath("go"); err != nil {
+        t.Skip("go no está disponible")
+    }
+    files := map[string]string{
+        "go.mod": "module example\n\ngo 1.21\n",
+        "main.go": `package main
+
+import (
+    "fmt"
+    "html/template"
+    "os"
+
+    "example/icons"
+)
+
+func main() {
+    fmt.Println(icons.Names())
+    icon, ok := icons.Lookup("devicon:angular")
+    fmt.Println(ok, icon == icons.DeviconAngular)
+    _, err := icons.HTML("nonicons:missing")
+    fmt.Println(err != nil)
+    tmpl := template.Must(template.New("").Funcs(icons.FuncMap()).Parse("{{ icon \"nonicons:bell\" }}\n"))
+    tmpl.Execute(os.Stdout, nil)
+}
+`,
+    }
+    for name, content := range files {
+        if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+            t.Fatal(err)
+        }
+    }
+    cmd := exec.Command("go", "run", ".")
+    cmd.Dir = dir
+    cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOPROXY=off")
+    out, err := cmd.CombinedOutput()
+    if err != nil {
+        t.Fatalf("%v\n%s", err, out)
+    }
+    lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+    if len(lines) != 4 {
+        t.Fatalf("salida inesperada:\n%s", out)
+    }
+    if lines[0] != "[devicon:angular nonicons:bell]" || lines[1] != "true true" || lines[2] != "true" {
+        t.Fatalf("salida inesperada:\n%s", out)
+    }
+    if !strings.HasPrefix(lines[3], "<svg") || !strings.Contains(lines[3], "M12 22c1.1") {
+        t.Fatalf("la plantilla no incluye el SVG:\n%s", lines[3])
+    }
+}
@@ -0,0 +1,88 @@
+// iconexporter/icns.go
+package iconexporter
+
+import (
+    "bytes"
+    "encoding/binary"
+    "fmt"
+    "io"
+    "sort"
+    "strings"
+)
+
+// Huecos estándar de un .icns (PNG) por tamaño en píxeles; los tamaños que
+// coinciden con una versión retina rellenan ambos huecos
+var icnsSlots = map[int][]string{
+    16:   {"icp4"},
+    32:   {"icp5", "ic11"},
+    64:   {"ic12"},
+    128:  {"ic07"},
+    256:  {"ic08", "ic13"},
+    512:  {"ic09", "ic14"},
+    1024: {"ic10"},
+}
+
+// icnsFormat escribe un .icns de macOS con todas las resoluciones del icono
+type icnsFormat struct{}
+
+func init() {
+    mustRegisterFormat(icnsFormat{})
+}
+
+func (icnsFormat) Name() string      { return "icns" }
+func (icnsFormat) Extension() string { return "icns" }
+func (icnsFormat) Vector() bool      { return false }
+
+func (f icnsFormat) Encode(w io.Writer, input EncodeInput, options map[string]interface{}) error {
+    return f.EncodeBundle(w, []EncodeInput{input}, options)
+}
+
+func (icnsFormat) EncodeBundle(w io.Writer, inputs []EncodeInput, options map[string]interface{}) error {
+    if len(inputs) == 0 {
+        return fmt.Errorf("el ICNS necesita al menos una imagen")
+    }
+    
+    sorted := append([]EncodeInput(nil), inputs...)
+    sort.Slice(sorted, func(i, j int) bool { return sorted[i].Width < sorted[j].Width })
+    
+    var body bytes.Buffer
+    for _, input := range sorted {
+        slots, ok := icnsSlots[input.Width]
+        if !ok || input.Width != input.Height {
+            return fmt.Errorf("tamaño %dx%d no válido para ICNS. Soportados: %s",
+                input.Width, input.Height, icnsSupportedSizes())
+        }
+        var png bytes.Buffer
+        if err := encodePNG(&png, input.Image, nil); err != nil {
+            return err
+        }
+        for _, slot := range slots {
+            body.WriteString(slot)
+            binary.Write(&body, binary.BigEndian, uint32(8+png.Len()))
+            body.Write(png.Bytes())
+        }
+    }
+    
+    var header bytes.Buffer
+    header.WriteString("icns")
+    binary.Write(&header, binary.BigEndian, uint32(8+body.Len()))
+    if _, err := w.Write(header.Bytes()); err != nil {
+        return err
+    }
+    _, err := w.Write(body.Bytes())
+    return err
+}
+
+// icnsSupportedSizes lista los tamaños con hueco en un .icns
+func icnsSupportedSizes() string {
+    sizes := make([]int, 0, len(icnsSlots))
+    for size := range icnsSlots {
+        sizes = append(sizes, size)
+    }
+    sort.Ints(sizes)
+    names := make([]string, len(sizes))
+    for i, size := range sizes {
+        names[i] = fmt.Sprintf("%dx%d", size, size)
+    }
+    return strings.Join(names, ", ")
+}
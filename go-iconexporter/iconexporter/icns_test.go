@@ -0,0 +1,78 @@
+// iconexporter/icns_test.go
+package iconexporter
+
+import (
+    "bytes"
+    "encoding/binary"
+    "image/png"
+    "reflect"
+    "strings"
+    "testing"
+)
+
+func TestIcnsBundle(t *testing.T) {
+    tests := []struct {
+        name  string
+        sizes []int
+        slots []string
+    }{
+        {"16", []int{16}, []string{"icp4"}},
+        {"retina duplicada", []int{32, 16}, []string{"icp4", "icp5", "ic11"}},
+        {"extremos", []int{1024, 16, 512}, []string{"icp4", "ic09", "ic14", "ic10"}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            var inputs []EncodeInput
+            for _, size := range tt.sizes {
+                inputs = append(inputs, EncodeInput{Width: size, Height: size, Image: testImage(size, size, patternBands)})
+            }
+            var buf bytes.Buffer
+            if err := (icnsFormat{}).EncodeBundle(&buf, inputs, nil); err != nil {
+                t.Fatal(err)
+            }
+            data := buf.Bytes()
+            if string(data[:4]) != "icns" || int(binary.BigEndian.Uint32(data[4:])) != len(data) {
+                t.Fatal("cabecera icns no válida")
+            }
+            
+            var slots []string
+            for offset := 8; offset < len(data); {
+                length := int(binary.BigEndian.Uint32(data[offset+4:]))
+                slot := string(data[offset : offset+4])
+                slots = append(slots, slot)
+                
+                decoded, err := png.Decode(bytes.NewReader(data[offset+8 : offset+length]))
+                if err != nil {
+                    t.Fatalf("%s: %v", slot, err)
+                }
+                size := decoded.Bounds().Dx()
+                if !containsString(icnsSlots[size], slot) {
+                    t.Fatalf("imagen de %d px en el hueco %s", size, slot)
+                }
+                offset += length
+            }
+            if !reflect.DeepEqual(slots, tt.slots) {
+                t.Fatalf("huecos %v, se esperaban %v", slots, tt.slots)
+            }
+        })
+    }
+}
+
+func TestIcnsInvalidSize(t *testing.T) {
+    for _, size := range [][2]int{{48, 48}, {32, 16}} {
+        input := EncodeInput{Width: size[0], Height: size[1], Image: testImage(size[0], size[1], patternSolid)}
+        err := (icnsFormat{}).Encode(&bytes.Buffer{}, input, nil)
+        if err == nil || !strings.Contains(err.Error(), "16x16, 32x32, 64x64") {
+            t.Fatalf("%v: error inesperado %v", size, err)
+        }
+    }
+}
+
+func containsString(values []string, value string) bool {
+    for _, v := range values {
+        if v == value {
+            return true
+        }
+    }
+    return false
+}
@@ -0,0 +1,125 @@
+// iconexporter/ico.go
+package iconexporter
+
+import (
+    "bytes"
+    "encoding/binary"
+    "fmt"
+    "image"
+    "io"
+    "sort"
+
+    "github.com/disintegration/imaging"
+)
+
+// icoFormat escribe un .ico de Windows con todas las resoluciones del icono.
+// Las imágenes menores de 256 px se guardan como DIB de 32 bits (compatibles
+// con cualquier versión de Windows) y las de 256 px como PNG; con la opción
+// "png" todas se guardan como PNG.
+type icoFormat struct{}
+
+func init() {
+    mustRegisterFormat(icoFormat{})
+}
+
+func (icoFormat) Name() string      { return "ico" }
+func (icoFormat) Extension() string { return "ico" }
+func (icoFormat) Vector() bool      { return false }
+
+func (f icoFormat) Encode(w io.Writer, input EncodeInput, options map[string]interface{}) error {
+    return f.EncodeBundle(w, []EncodeInput{input}, options)
+}
+
+func (icoFormat) EncodeBundle(w io.Writer, inputs []EncodeInput, options map[string]interface{}) error {
+    if len(inputs) == 0 {
+        return fmt.Errorf("el ICO necesita al menos una imagen")
+    }
+    allPNG := optionBool(options, "png", false)
+    
+    // Ordenar de menor a mayor, como hacen las herramientas de Windows
+    sorted := append([]EncodeInput(nil), inputs...)
+    sort.Slice(sorted, func(i, j int) bool { return sorted[i].Width < sorted[j].Width })
+    
+    images := make([][]byte, 0, len(sorted))
+    for _, input := range sorted {
+        if input.Width > 256 || input.Height > 256 {
+            return fmt.Errorf("tamaño %dx%d no válido para ICO (máximo 256x256)", input.Width, input.Height)
+        }
+        var buf bytes.Buffer
+        var err error
+        if allPNG || input.Width == 256 || input.Height == 256 {
+            err = encodePNG(&buf, input.Image, nil)
+        } else {
+            err = writeIcoDIB(&buf, input.Image)
+        }
+        if err != nil {
+            return err
+        }
+        images = append(images, buf.Bytes())
+    }
+    
+    // Cabecera ICONDIR y una entrada ICONDIRENTRY por imagen
+    le := binary.LittleEndian
+    var header bytes.Buffer
+    binary.Write(&header, le, [3]uint16{0, 1, uint16(len(images))})
+    offset := uint32(6 + 16*len(images))
+    for i, input := range sorted {
+        binary.Write(&header, le, [4]uint8{icoDimension(input.Width), icoDimension(input.Height), 0, 0})
+        binary.Write(&header, le, [2]uint16{1, 32})
+        binary.Write(&header, le, [2]uint32{uint32(len(images[i])), offset})
+        offset += uint32(len(images[i]))
+    }
+    
+    if _, err := w.Write(header.Bytes()); err != nil {
+        return err
+    }
+    for _, data := range images {
+        if _, err := w.Write(data); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// icoDimension codifica 256 como 0, según el formato ICO
+func icoDimension(size int) uint8 {
+    if size >= 256 {
+        return 0
+    }
+    return uint8(size)
+}
+
+// writeIcoDIB escribe la imagen como BITMAPINFOHEADER + píxeles BGRA de abajo
+// arriba + máscara AND de 1 bit
+func writeIcoDIB(w io.Writer, img image.Image) error {
+    src := imaging.Clone(img)
+    width, height := src.Bounds().Dx(), src.Bounds().Dy()
+    maskStride := ((width + 31) / 32) * 4
+    pixelBytes := width * height * 4
+    maskBytes := maskStride * height
+    
+    le := binary.LittleEndian
+    var buf bytes.Buffer
+    binary.Write(&buf, le, uint32(40))
+    binary.Write(&buf, le, int32(width))
+    binary.Write(&buf, le, int32(height*2)) // imagen XOR + máscara AND
+    binary.Write(&buf, le, [2]uint16{1, 32})
+    binary.Write(&buf, le, [6]uint32{0, uint32(pixelBytes + maskBytes), 0, 0, 0, 0})
+    
+    mask := make([]byte, maskBytes)
+    for y := height - 1; y >= 0; y-- {
+        row := src.Pix[y*src.Stride : y*src.Stride+width*4]
+        maskRow := mask[(height-1-y)*maskStride:]
+        for x := 0; x < width; x++ {
+            r, g, b, a := row[x*4], row[x*4+1], row[x*4+2], row[x*4+3]
+            buf.Write([]byte{b, g, r, a})
+            if a == 0 {
+                maskRow[x/8] |= 0x80 >> uint(x%8)
+            }
+        }
+    }
+    buf.Write(mask)
+    
+    _, err := w.Write(buf.Bytes())
+    return err
+}
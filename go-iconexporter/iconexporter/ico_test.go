@@ -0,0 +1,136 @@
+// iconexporter/ico_test.go
+package iconexporter
+
+import (
+    "bytes"
+    "encoding/binary"
+    "image"
+    "image/color"
+    "image/png"
+    "testing"
+)
+
+// icoEntry es una entrada ICONDIRENTRY ya leída
+type icoEntry struct {
+    width, height int
+    data          []byte
+}
+
+// readIco separa las imágenes de un .ico
+func readIco(t *testing.T, data []byte) []icoEntry {
+    t.Helper()
+    le := binary.LittleEndian
+    if le.Uint16(data[0:]) != 0 || le.Uint16(data[2:]) != 1 {
+        t.Fatal("cabecera ICONDIR no válida")
+    }
+    count := int(le.Uint16(data[4:]))
+    entries := make([]icoEntry, count)
+    for i := range entries {
+        entry := data[6+16*i:]
+        size, offset := le.Uint32(entry[8:]), le.Uint32(entry[12:])
+        if int(offset+size) > len(data) {
+            t.Fatalf("entrada %d fuera del archivo", i)
+        }
+        width, height := int(entry[0]), int(entry[1])
+        if width == 0 {
+            width = 256
+        }
+        if height == 0 {
+            height = 256
+        }
+        entries[i] = icoEntry{width, height, data[offset : offset+size]}
+    }
+    return entries
+}
+
+// decodeIcoDIB lee un DIB de 32 bits de abajo arriba y comprueba la máscara AND
+func decodeIcoDIB(t *testing.T, data []byte) *image.NRGBA {
+    t.Helper()
+    le := binary.LittleEndian
+    width, height := int(le.Uint32(data[4:])), int(le.Uint32(data[8:]))/2
+    if bits := le.Uint16(data[14:]); bits != 32 {
+        t.Fatalf("%d bits por píxel", bits)
+    }
+    img := image.NewNRGBA(image.Rect(0, 0, width, height))
+    pixels := data[40:]
+    mask := pixels[width*height*4:]
+    maskStride := ((width + 31) / 32) * 4
+    for y := 0; y < height; y++ {
+        for x := 0; x < width; x++ {
+            p := pixels[((height-1-y)*width+x)*4:]
+            img.SetNRGBA(x, y, color.NRGBA{p[2], p[1], p[0], p[3]})
+            masked := mask[(height-1-y)*maskStride+x/8]&(0x80>>uint(x%8)) != 0
+            if masked != (p[3] == 0) {
+                t.Fatalf("máscara AND incorrecta en %d,%d", x, y)
+            }
+        }
+    }
+    return img
+}
+
+func TestIcoBundle(t *testing.T) {
+    tests := []struct {
+        name    string
+        sizes   []int
+        options map[string]interface{}
+        pngs    []bool // por entrada, en orden de tamaño
+    }{
+        {"un tamaño", []int{16}, nil, []bool{false}},
+        {"desordenado con 256", []int{256, 16, 48, 32}, nil, []bool{false, false, false, true}},
+        {"todo PNG", []int{24, 16}, map[string]interface{}{"png": true}, []bool{true, true}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            images := map[int]*image.NRGBA{}
+            var inputs []EncodeInput
+            for _, size := range tt.sizes {
+                images[size] = testImage(size, size, patternNoise)
+                inputs = append(inputs, EncodeInput{Width: size, Height: size, Image: images[size]})
+            }
+            var buf bytes.Buffer
+            if err := (icoFormat{}).EncodeBundle(&buf, inputs, tt.options); err != nil {
+                t.Fatal(err)
+            }
+            entries := readIco(t, buf.Bytes())
+            if len(entries) != len(tt.sizes) {
+                t.Fatalf("%d entradas, se esperaban %d", len(entries), len(tt.sizes))
+            }
+            previous := 0
+            for i, entry := range entries {
+                if entry.width <= previous || entry.width != entry.height {
+                    t.Fatalf("entrada %d de %dx%d fuera de orden", i, entry.width, entry.height)
+                }
+                previous = entry.width
+                
+                isPNG := bytes.HasPrefix(entry.data, []byte("\x89PNG"))
+                if isPNG != tt.pngs[i] {
+                    t.Fatalf("entrada %d: PNG %v, se esperaba %v", i, isPNG, tt.pngs[i])
+                }
+                var decoded image.Image
+                if isPNG {
+                    var err error
+                    if decoded, err = png.Decode(bytes.NewReader(entry.data)); err != nil {
+                        t.Fatal(err)
+                    }
+                } else {
+                    decoded = decodeIcoDIB(t, entry.data)
+                }
+                sameNRGBA(t, images[entry.width], decoded)
+            }
+        })
+    }
+}
+
+func TestIcoErrors(t *testing.T) {
+    tests := map[string][]EncodeInput{
+        "sin imágenes":  nil,
+        "mayor de 256": {{Width: 512, Height: 512, Image: testImage(512, 512, patternSolid)}},
+    }
+    for name, inputs := range tests {
+        t.Run(name, func(t *testing.T) {
+            if err := (icoFormat{}).EncodeBundle(&bytes.Buffer{}, inputs, nil); err == nil {
+                t.Fatal("se esperaba un error")
+            }
+        })
+    }
+}
@@ -2,14 +2,14 @@
 package iconexporter
 
 import (
-    "encoding/json"
     "fmt"
     "image"
-    "image/color"
     "io"
     "os"
     "path/filepath"
     "regexp"
+    "sort"
+    "strconv"
     "strings"
     "sync"
     "time"
@@ -17,7 +17,6 @@ import (
     "github.com/disintegration/imaging"
     "github.com/srwiley/oksvg"
     "github.com/srwiley/rasterx"
-    "golang.org/x/image/draw"
 )
 
 // Configuración por defecto
@@ -45,7 +44,7 @@ var DefaultConfig = Config{
 // Constantes y patrones
 var (
     ValidCaseTypes         = map[string]bool{"camel": true, "pascal": true, "snake": true, "kebab": true, "original": true}
-    ValidRasterFormats     = map[string]bool{"png": true, "jpeg": true, "webp": true}
+    ValidRasterFormats     = map[string]bool{} // se completa con RegisterFormat; solo lectura, ver LookupFormat
     InvalidFilenameChars   = regexp.MustCompile(`[<>:"/\\|?*]`)
     MultipleHyphens        = regexp.MustCompile(`-+`)
     LeadingTrailingHyphens = regexp.MustCompile(`^-+|-+$`)
@@ -76,8 +75,25 @@ type Config struct {
     DefaultSize     [2]int                `json:"defaultSize"`
     DefaultColor    string                `json:"defaultColor"`
     OutputFormats   []string              `json:"outputFormats"`
+    ScaleFactors    []float64             `json:"scaleFactors"`
     FileNaming      FileNamingConfig      `json:"fileNaming"`
     FolderStructure FolderStructureConfig `json:"folderStructure"`
+    FormatOptions   map[string]map[string]interface{} `json:"formatOptions"`
+    Raster          RasterConfig          `json:"raster"`
+    Preflight       PreflightConfig       `json:"preflight"`
+    Sanitize        SanitizeConfig        `json:"sanitize"`
+    IDPrefix        IDPrefixConfig        `json:"idPrefix"`
+    WebApp          WebAppConfig          `json:"webApp"`
+    Android         AndroidConfig         `json:"android"`
+    Xcassets        XcassetsConfig        `json:"xcassets"`
+    Sprite          SpriteConfig          `json:"sprite"`
+    Atlas           AtlasConfig           `json:"atlas"`
+    Xaml            XamlConfig            `json:"xaml"`
+    PdfCatalog      PdfCatalogConfig      `json:"pdfCatalog"`
+    IconFont        IconFontConfig        `json:"iconFont"`
+    Components      ComponentsConfig      `json:"components"`
+    GoPackage       GoPackageConfig       `json:"goPackage"`
+    Flutter         FlutterConfig         `json:"flutter"`
 }
 
 type IconData struct {
@@ -96,15 +112,34 @@ type Icon struct {
 }
 
 type ExportSummary struct {
-    Processed int     `json:"processed"`
-    Errors    int     `json:"errors"`
-    Duration  float64 `json:"duration"`
+    Processed int              `json:"processed"`
+    Errors    int              `json:"errors"`
+    Skipped   int              `json:"skipped,omitempty"`
+    Duration  float64          `json:"duration"`
+    Results   []ExportResult   `json:"results,omitempty"`
+    Sanitized []SanitizeReport `json:"sanitized,omitempty"` // lo que Sanitize quitó de cada icono
+}
+
+// ExportResult describe cada archivo exportado, con sus avisos o el error
+type ExportResult struct {
+    Collection string      `json:"collection"`
+    Icon       string      `json:"icon"`
+    Format     string      `json:"format"`
+    Path       string      `json:"path"`
+    Width      int         `json:"width"`
+    Height     int         `json:"height"`
+    Color      string      `json:"color"`
+    Warnings   []string    `json:"warnings,omitempty"`
+    Error      string      `json:"error,omitempty"`
+    Skipped    bool        `json:"skipped,omitempty"` // no exportado por el análisis previo
+    Savings    *SvgSavings `json:"savings,omitempty"` // solo con la opción optimize del formato svg
 }
 
 // IconExporter maneja la exportación de iconos
 type IconExporter struct {
-    config Config
-    mu     sync.Mutex
+    config    Config
+    mu        sync.Mutex
+    sanitized map[string]SanitizeReport // informes de saneado por colección/icono
 }
 
 // NewIconExporter crea una nueva instancia de IconExporter
@@ -163,9 +198,69 @@ func mergeConfig(defaultConfig, userConfig Config) Config {
     merged.FolderStructure.GroupBySize = userConfig.FolderStructure.GroupBySize
     merged.FolderStructure.GroupByColor = userConfig.FolderStructure.GroupByColor
     
+    if userConfig.FormatOptions != nil {
+        merged.FormatOptions = userConfig.FormatOptions
+    }
+    merged.Raster = userConfig.Raster
+    merged.Preflight = userConfig.Preflight
+    merged.Sanitize = userConfig.Sanitize
+    merged.IDPrefix = userConfig.IDPrefix
+    merged.WebApp = userConfig.WebApp
+    merged.Android = userConfig.Android
+    merged.Xcassets = userConfig.Xcassets
+    merged.Sprite = userConfig.Sprite
+    merged.Atlas = userConfig.Atlas
+    merged.Xaml = userConfig.Xaml
+    merged.PdfCatalog = userConfig.PdfCatalog
+    merged.IconFont = userConfig.IconFont
+    merged.Components = userConfig.Components
+    merged.GoPackage = userConfig.GoPackage
+    merged.Flutter = userConfig.Flutter
+    if len(userConfig.ScaleFactors) > 0 {
+        merged.ScaleFactors = userConfig.ScaleFactors
+    }
+    
     return merged
 }
 
+// optionInt lee una opción entera (acepta números decodificados desde JSON)
+func optionInt(options map[string]interface{}, key string, fallback int) int {
+    switch v := options[key].(type) {
+    case int:
+        return v
+    case float64:
+        return int(v)
+    default:
+        return fallback
+    }
+}
+
+// optionSizes lee una lista de tamaños cuadrados, p. ej. "sizes": [16, 32, 48]
+func optionSizes(options map[string]interface{}, key string) [][2]int {
+    var sizes [][2]int
+    switch v := options[key].(type) {
+    case []int:
+        for _, size := range v {
+            sizes = append(sizes, [2]int{size, size})
+        }
+    case []interface{}:
+        for _, item := range v {
+            if size := optionInt(map[string]interface{}{key: item}, key, 0); size > 0 {
+                sizes = append(sizes, [2]int{size, size})
+            }
+        }
+    }
+    return sizes
+}
+
+// optionBool lee una opción booleana
+func optionBool(options map[string]interface{}, key string, fallback bool) bool {
+    if v, ok := options[key].(bool); ok {
+        return v
+    }
+    return fallback
+}
+
 // validateConfig valida la configuración
 func (e *IconExporter) validateConfig() error {
     if len(e.config.Collections) == 0 {
@@ -177,11 +272,58 @@ func (e *IconExporter) validateConfig() error {
     }
     
     for _, format := range e.config.OutputFormats {
-        if format != "svg" && !ValidRasterFormats[format] {
-            return fmt.Errorf("formato de salida no válido: %s. Soportados: svg, png, jpeg, webp", format)
+        if _, ok := LookupFormat(format); !ok {
+            if reason, known := unsupportedFormats[format]; known {
+                return fmt.Errorf("formato de salida no soportado: %s (%s)", format, reason)
+            }
+            return fmt.Errorf("formato de salida no válido: %s. Soportados: %s", format, strings.Join(RegisteredFormats(), ", "))
         }
     }
     
+    for _, scale := range e.config.ScaleFactors {
+        if scale <= 0 {
+            return fmt.Errorf("factor de escala no válido: %g", scale)
+        }
+    }
+    
+    if _, err := e.rasterConfig(); err != nil {
+        return err
+    }
+    if _, err := e.preflightConfig(); err != nil {
+        return err
+    }
+    if _, err := e.sanitizeConfig(); err != nil {
+        return err
+    }
+    if _, err := e.idPrefixConfig(); err != nil {
+        return err
+    }
+    if _, err := e.spriteConfig(); err != nil {
+        return err
+    }
+    if _, err := e.atlasConfig(); err != nil {
+        return err
+    }
+    if _, err := e.pdfCatalogConfig(); err != nil {
+        return err
+    }
+    if _, err := e.iconFontConfig(); err != nil {
+        return err
+    }
+    if _, err := e.componentsConfig(); err != nil {
+        return err
+    }
+    if _, err := e.goPackageConfig(); err != nil {
+        return err
+    }
+    if _, err := e.flutterConfig(); err != nil {
+        return err
+    }
+    
+    if webpOptions := e.config.FormatOptions["webp"]; !optionBool(webpOptions, "lossless", true) {
+        return fmt.Errorf("WebP con pérdida (VP8) no está soportado todavía, usa lossless=true")
+    }
+    
     return nil
 }
 
@@ -224,19 +366,41 @@ func (e *IconExporter) generateFileName(collection, iconName string, options map
     format := options["format"].(string)
     
     fileName := e.config.FileNaming.Pattern
+    
+    // Con factores de escala {width} y {height} son píxeles; si el patrón no usa
+    // {scale} se sigue la convención nombre@2x con el tamaño lógico
+    scale, scaled := options["scale"].(float64)
+    logicalWidth, logicalHeight := width, height
+    if scaled {
+        logicalWidth = options["logicalWidth"].(int)
+        logicalHeight = options["logicalHeight"].(int)
+        if !strings.Contains(fileName, "{scale}") {
+            fileName = strings.ReplaceAll(fileName, "{width}", "{logicalWidth}")
+            fileName = strings.ReplaceAll(fileName, "{height}", "{logicalHeight}")
+            fileName += "{scale}"
+        }
+    }
+    scaleSuffix := ""
+    if scaled && scale != 1 {
+        scaleSuffix = "@" + strconv.FormatFloat(scale, 'f', -1, 64) + "x"
+    }
+    
     fileName = strings.ReplaceAll(fileName, "{collection}", collection)
     fileName = strings.ReplaceAll(fileName, "{icon}", iconName)
     fileName = strings.ReplaceAll(fileName, "{width}", fmt.Sprintf("%d", width))
     fileName = strings.ReplaceAll(fileName, "{height}", fmt.Sprintf("%d", height))
     fileName = strings.ReplaceAll(fileName, "{color}", color)
     fileName = strings.ReplaceAll(fileName, "{format}", format)
+    fileName = strings.ReplaceAll(fileName, "{logicalWidth}", fmt.Sprintf("%d", logicalWidth))
+    fileName = strings.ReplaceAll(fileName, "{logicalHeight}", fmt.Sprintf("%d", logicalHeight))
+    fileName = strings.ReplaceAll(fileName, "{scale}", scaleSuffix)
     
     // Sanitización
     if e.config.FileNaming.Sanitize {
         fileName = InvalidFilenameChars.ReplaceAllString(fileName, "")
         fileName = strings.ReplaceAll(fileName, " ", "-")
-        // Permitir solo letras, números, guiones, puntos y barras
-        re := regexp.MustCompile(`[^\w\-\.\/]`)
+        // Permitir solo letras, números, guiones, puntos, barras y @ (sufijo de escala)
+        re := regexp.MustCompile(`[^\w\-\.\/@]`)
         fileName = re.ReplaceAllString(fileName, "")
         fileName = MultipleHyphens.ReplaceAllString(fileName, "-")
         fileName = LeadingTrailingHyphens.ReplaceAllString(fileName, "")
@@ -244,7 +408,11 @@ func (e *IconExporter) generateFileName(collection, iconName string, options map
     
     fileName = e.applyCase(fileName, e.config.FileNaming.Case)
     
-    return fmt.Sprintf("%s.%s", fileName, format)
+    extension := format
+    if encoder, ok := LookupFormat(format); ok {
+        extension = encoder.Extension()
+    }
+    return fmt.Sprintf("%s.%s", fileName, extension)
 }
 
 // generateFolderPath genera la ruta de la carpeta
@@ -256,6 +424,11 @@ func (e *IconExporter) generateFolderPath(collection string, options map[string]
     width := options["width"].(int)
     height := options["height"].(int)
     col := options["color"].(string)
+    
+    // Todas las escalas de un mismo tamaño lógico comparten carpeta
+    if logicalWidth, ok := options["logicalWidth"].(int); ok {
+        width, height = logicalWidth, options["logicalHeight"].(int)
+    }
     sizeString := fmt.Sprintf("%dx%d", width, height)
     
     folderPattern := e.config.FolderStructure.Pattern
@@ -284,6 +457,23 @@ func (e *IconExporter) ensureOutputDir(dirPath string) error {
     return os.MkdirAll(dirPath, 0755)
 }
 
+// writeFile crea el archivo y delega la escritura; si falla elimina el archivo parcial
+func writeFile(filePath string, write func(w io.Writer) error) error {
+    file, err := os.Create(filePath)
+    if err != nil {
+        return err
+    }
+    
+    err = write(file)
+    if closeErr := file.Close(); err == nil {
+        err = closeErr
+    }
+    if err != nil {
+        os.Remove(filePath)
+    }
+    return err
+}
+
 // applySvgColor aplica color al SVG
 func (e *IconExporter) applySvgColor(svgBody, color string) string {
     targetColor := color
@@ -297,9 +487,10 @@ func (e *IconExporter) applySvgColor(svgBody, color string) string {
     return svgBody
 }
 
-// prepareSvgBuffer prepara el contenido SVG como bytes
-func (e *IconExporter) prepareSvgBuffer(icon Icon, width, height int, color string) []byte {
-    processedBody := e.applySvgColor(icon.Body, color)
+// prepareSvgBuffer prepara el contenido SVG como bytes; con idPrefix (ver
+// svgIDPrefix) reescribe los id del icono y sus referencias
+func (e *IconExporter) prepareSvgBuffer(icon Icon, width, height int, color, idPrefix string) []byte {
+    processedBody := e.applySvgColor(prefixSvgIds(icon.Body, idPrefix), color)
     svgContent := fmt.Sprintf(
         `<svg xmlns="http://www.w3.org/2000/svg" viewBox="%s" width="%d" height="%d">%s</svg>`, 
         icon.ViewBox, width, height, processedBody,
@@ -307,47 +498,64 @@ func (e *IconExporter) prepareSvgBuffer(icon Icon, width, height int, color stri
     return []byte(svgContent)
 }
 
-// saveImage guarda la imagen en el formato especificado
-func (e *IconExporter) saveImage(svgData []byte, filePath, format string, width, height int) error {
-    if format == "svg" {
-        return os.WriteFile(filePath, svgData, 0644)
-    }
+// rasterizeSvg dibuja el SVG en una imagen del tamaño indicado. Con
+// Raster.Supersample dibuja a N veces el tamaño y reduce con el filtro elegido.
+func (e *IconExporter) rasterizeSvg(svgData []byte, width, height int) (*image.NRGBA, error) {
+    cfg, _ := e.rasterConfig() // validada en validateConfig
     
     // Parsear SVG
     icon, err := oksvg.ReadIconStream(strings.NewReader(string(svgData)))
     if err != nil {
-        return fmt.Errorf("error parsing SVG: %w", err)
+        return nil, fmt.Errorf("error parsing SVG: %w", err)
     }
     
-    icon.SetTarget(0, 0, float64(width), float64(height))
+    factor := cfg.Supersample
+    if max(width, height) > cfg.MaxSize {
+        factor = 1
+    }
+    x, y, w, h := rasterTarget(icon, width, height, cfg.PixelSnap)
+    f := float64(factor)
+    icon.SetTarget(x*f, y*f, w*f, h*f)
     
     // Crear imagen RGBA
-    img := image.NewRGBA(image.Rect(0, 0, width, height))
+    canvasW, canvasH := width*factor, height*factor
+    img := image.NewRGBA(image.Rect(0, 0, canvasW, canvasH))
     
     // Configurar drawer
-    drawer := rasterx.NewDasher(width, height, rasterx.NewScannerGV(width, height, img, img.Bounds()))
+    drawer := rasterx.NewDasher(canvasW, canvasH, rasterx.NewScannerGV(canvasW, canvasH, img, img.Bounds()))
     
     // Dibujar icono
     icon.Draw(drawer, 1)
     
     // Convertir a imagen de imaging
-    imagingImg := imaging.Clone(img)
-    
-    // Guardar en formato especificado
-    switch format {
-    case "png":
-        return imaging.Save(imagingImg, filePath)
-    case "jpeg":
-        return imaging.Save(imagingImg, filePath)
-    case "webp":
-        // Para WebP simple, guardamos como PNG por ahora
-        // En producción, usar librería WebP específica
-        pngPath := strings.TrimSuffix(filePath, ".webp") + ".png"
-        fmt.Printf("⚠️ WebP no soportado directamente, guardando como PNG: %s\n", pngPath)
-        return imaging.Save(imagingImg, pngPath)
-    default:
+    if factor == 1 {
+        return imaging.Clone(img), nil
+    }
+    filter := resampleFilters[cfg.Filter]
+    if cfg.Gamma {
+        return resampleLinear(imaging.Clone(img), width, height, filter), nil
+    }
+    return imaging.Resize(img, width, height, filter), nil
+}
+
+// saveImage guarda la imagen con el codificador registrado para el formato
+func (e *IconExporter) saveImage(input EncodeInput, filePath, format string) error {
+    encoder, ok := LookupFormat(format)
+    if !ok {
         return fmt.Errorf("formato no soportado: %s", format)
     }
+    
+    if !encoder.Vector() {
+        img, err := e.rasterizeSvg(input.SVG, input.Width, input.Height)
+        if err != nil {
+            return err
+        }
+        input.Image = img
+    }
+    
+    return writeFile(filePath, func(w io.Writer) error {
+        return encoder.Encode(w, input, e.config.FormatOptions[format])
+    })
 }
 
 // loadCollectionData carga los datos de una colección
@@ -388,7 +596,7 @@ func (e *IconExporter) loadCollectionData(collection string) (IconData, error) {
         return IconData{}, fmt.Errorf("colección no encontrada: %s", collection)
     }
     
-    return iconData, nil
+    return e.sanitizeCollection(collection, iconData), nil
 }
 
 // getIconsToProcess obtiene la lista de iconos a procesar
@@ -405,45 +613,221 @@ func (e *IconExporter) getIconsToProcess(iconData IconData) []string {
 }
 
 // processVariant procesa una variante de icono
-func (e *IconExporter) processVariant(iconData IconData, collection, iconName string, options map[string]interface{}) (int, error) {
+func (e *IconExporter) processVariant(iconData IconData, collection, iconName string, options map[string]interface{}) ([]ExportResult, error) {
     width := options["width"].(int)
     height := options["height"].(int)
     col := options["color"].(string)
-    successCount := 0
+    scale, _ := options["scale"].(float64)
+    var results []ExportResult
     
     icon, exists := iconData.Icons[iconName]
     if !exists {
-        return 0, fmt.Errorf("icono '%s' no encontrado en %s", iconName, collection)
+        return nil, fmt.Errorf("icono '%s' no encontrado en %s", iconName, collection)
     }
     
-    svgBuffer := e.prepareSvgBuffer(icon, width, height, col)
+    svgBuffer := e.prepareSvgBuffer(icon, width, height, col, e.svgIDPrefix(collection, iconName))
     folderPath := e.generateFolderPath(collection, options)
+    preflight := e.rasterPreflight(svgBuffer)
     
     if err := e.ensureOutputDir(folderPath); err != nil {
-        return 0, fmt.Errorf("error creando directorio: %w", err)
+        return nil, fmt.Errorf("error creando directorio: %w", err)
     }
     
-    // Exportar a todos los formatos
+    // Exportar a todos los formatos (los que agrupan tamaños se exportan en processBundle)
     for _, format := range e.config.OutputFormats {
-        fileName := e.generateFileName(collection, iconName, map[string]interface{}{
-            "width":  width,
-            "height": height,
-            "color":  col,
-            "format": format,
-        })
+        if isBundleFormat(format) {
+            continue
+        }
+        
+        nameOptions := map[string]interface{}{"format": format}
+        for key, value := range options {
+            nameOptions[key] = value
+        }
+        fileName := e.generateFileName(collection, iconName, nameOptions)
         
         filePath := filepath.Join(folderPath, fileName)
+        result := ExportResult{
+            Collection: collection,
+            Icon:       iconName,
+            Format:     format,
+            Path:       filePath,
+            Width:      width,
+            Height:     height,
+            Color:      col,
+        }
+        input := EncodeInput{
+            Collection: collection,
+            Icon:       iconName,
+            Color:      col,
+            Width:      width,
+            Height:     height,
+            SVG:        svgBuffer,
+            Scale:      scale,
+            FileName:   fileName,
+            Warn: func(message string) {
+                result.Warnings = append(result.Warnings, message)
+            },
+            Optimized: func(original, optimized int) {
+                result.Savings = newSvgSavings(original, optimized)
+            },
+        }
         
-        if err := e.saveImage(svgBuffer, filePath, format, width, height); err != nil {
+        // El análisis previo solo afecta a los formatos que pasan por oksvg
+        if encoder, ok := LookupFormat(format); ok && !encoder.Vector() && !preflight(&result) {
+            results = append(results, result)
+            continue
+        }
+        
+        if err := e.saveImage(input, filePath, format); err != nil {
             fmt.Printf("❌ Error al guardar %s para '%s' (%dx%d, %s): %v\n", 
                 format, iconName, width, height, col, err)
+            result.Error = err.Error()
+        } else if result.Savings != nil {
+            fmt.Printf("✅ Exportado: %s (%d → %d bytes, -%.1f%%)\n", filePath,
+                result.Savings.OriginalBytes, result.Savings.OptimizedBytes, result.Savings.Percent)
         } else {
             fmt.Printf("✅ Exportado: %s\n", filePath)
-            successCount++
         }
+        printWarnings(result)
+        results = append(results, result)
     }
     
-    return successCount, nil
+    return results, nil
+}
+
+// reportSkippedOrFailed muestra un archivo que el análisis previo no dejó exportar
+func reportSkippedOrFailed(result ExportResult) {
+    if result.Skipped {
+        fmt.Printf("⏭️ Omitido: %s\n", result.Path)
+        printWarnings(result)
+    } else {
+        fmt.Printf("❌ Error al guardar %s para '%s': %s\n", result.Format, result.Icon, result.Error)
+    }
+}
+
+// printWarnings muestra los avisos generados al exportar un archivo
+func printWarnings(result ExportResult) {
+    for _, warning := range result.Warnings {
+        fmt.Printf("⚠️ %s (%s): %s\n", result.Icon, result.Format, warning)
+    }
+}
+
+// processBundle exporta en un único archivo todos los tamaños de un icono y color
+// para cada formato que implemente BundleEncoder
+func (e *IconExporter) processBundle(iconData IconData, collection, iconName, col string, sizes [][2]int) ([]ExportResult, error) {
+    var results []ExportResult
+    
+    icon, exists := iconData.Icons[iconName]
+    if !exists {
+        return nil, fmt.Errorf("icono '%s' no encontrado en %s", iconName, collection)
+    }
+    
+    for _, format := range e.config.OutputFormats {
+        encoder, _ := LookupFormat(format)
+        bundle, isBundle := encoder.(BundleEncoder)
+        if !isBundle {
+            continue
+        }
+        
+        options := e.config.FormatOptions[format]
+        bundleSizes := sizes
+        if custom := optionSizes(options, "sizes"); len(custom) > 0 {
+            bundleSizes = custom
+        }
+        
+        // Renderizar cada tamaño; el nombre y la carpeta usan el mayor
+        var err error
+        result := ExportResult{
+            Collection: collection,
+            Icon:       iconName,
+            Format:     format,
+            Color:      col,
+        }
+        largest := bundleSizes[0]
+        inputs := make([]EncodeInput, 0, len(bundleSizes))
+        for _, size := range bundleSizes {
+            input := EncodeInput{
+                Collection: collection,
+                Icon:       iconName,
+                Color:      col,
+                Width:      size[0],
+                Height:     size[1],
+                SVG:        e.prepareSvgBuffer(icon, size[0], size[1], col, e.svgIDPrefix(collection, iconName)),
+                Warn: func(message string) {
+                    result.Warnings = append(result.Warnings, message)
+                },
+            }
+            if !bundle.Vector() {
+                if input.Image, err = e.rasterizeSvg(input.SVG, size[0], size[1]); err != nil {
+                    break
+                }
+            }
+            if size[0]*size[1] > largest[0]*largest[1] {
+                largest = size
+            }
+            inputs = append(inputs, input)
+        }
+        
+        nameOptions := map[string]interface{}{
+            "width":  largest[0],
+            "height": largest[1],
+            "color":  col,
+            "format": format,
+        }
+        folderPath := e.generateFolderPath(collection, nameOptions)
+        filePath := filepath.Join(folderPath, e.generateFileName(collection, iconName, nameOptions))
+        result.Path, result.Width, result.Height = filePath, largest[0], largest[1]
+        
+        if err == nil && !bundle.Vector() && !e.rasterPreflight(inputs[0].SVG)(&result) {
+            results = append(results, result)
+            continue
+        }
+        if err == nil {
+            err = e.ensureOutputDir(folderPath)
+        }
+        if err == nil {
+            err = writeFile(filePath, func(w io.Writer) error {
+                return bundle.EncodeBundle(w, inputs, options)
+            })
+        }
+        
+        if err != nil {
+            fmt.Printf("❌ Error al guardar %s para '%s' (%d tamaños, %s): %v\n",
+                format, iconName, len(bundleSizes), col, err)
+            result.Error = err.Error()
+        } else {
+            fmt.Printf("✅ Exportado: %s (%d tamaños)\n", filePath, len(bundleSizes))
+        }
+        printWarnings(result)
+        results = append(results, result)
+    }
+    
+    return results, nil
+}
+
+// variantOptions devuelve las variantes de un tamaño y un color; con ScaleFactors
+// el tamaño es lógico y se genera una variante por escala con su tamaño en píxeles
+func (e *IconExporter) variantOptions(size [2]int, col string) []map[string]interface{} {
+    if len(e.config.ScaleFactors) == 0 {
+        return []map[string]interface{}{{
+            "width":  size[0],
+            "height": size[1],
+            "color":  col,
+        }}
+    }
+    
+    variants := make([]map[string]interface{}, 0, len(e.config.ScaleFactors))
+    for _, scale := range e.config.ScaleFactors {
+        variants = append(variants, map[string]interface{}{
+            "width":         int(float64(size[0])*scale + 0.5),
+            "height":        int(float64(size[1])*scale + 0.5),
+            "color":         col,
+            "scale":         scale,
+            "logicalWidth":  size[0],
+            "logicalHeight": size[1],
+        })
+    }
+    return variants
 }
 
 // ExportWithVariants exporta iconos con variantes
@@ -457,16 +841,28 @@ func (e *IconExporter) ExportWithVariants(sizes [][2]int, colors []string) (Expo
         colors = []string{e.config.DefaultColor}
     }
     
-    var totalProcessed, totalErrors int
+    // variantOutcome es lo que devuelve cada goroutine de exportación
+    type variantOutcome struct {
+        results []ExportResult
+        err     error
+    }
+    
+    var totalProcessed, totalErrors, totalSkipped int
+    var allResults []ExportResult
+    var iconEntries []iconEntry
     var wg sync.WaitGroup
-    errorsChan := make(chan error, 100)
-    resultsChan := make(chan int, 100)
+    outcomesChan := make(chan variantOutcome)
     
     // Crear directorio de salida
     if err := e.ensureOutputDir(e.config.OutputDir); err != nil {
         return ExportSummary{}, fmt.Errorf("error creando directorio de salida: %w", err)
     }
     
+    hasBundles := false
+    for _, format := range e.config.OutputFormats {
+        hasBundles = hasBundles || isBundleFormat(format)
+    }
+    
     // Cargar y procesar colecciones
     for _, collection := range e.config.Collections {
         iconData, err := e.loadCollectionData(collection)
@@ -481,30 +877,37 @@ func (e *IconExporter) ExportWithVariants(sizes [][2]int, colors []string) (Expo
         for _, iconName := range icons {
             if _, exists := iconData.Icons[iconName]; !exists {
                 fmt.Printf("⚠️ Icono '%s' no encontrado en %s\n", iconName, collection)
-                totalErrors += len(sizes) * len(colors) * len(e.config.OutputFormats)
+                totalErrors += len(sizes) * len(colors) * max(len(e.config.ScaleFactors), 1) * len(e.config.OutputFormats)
                 continue
             }
+            iconEntries = append(iconEntries, iconEntry{collection, iconName, iconData.Icons[iconName]})
             
             for _, size := range sizes {
+                for _, col := range colors {
+                    for _, options := range e.variantOptions(size, col) {
+                        wg.Add(1)
+                        
+                        go func(coll, name string, opts map[string]interface{}) {
+                            defer wg.Done()
+                            
+                            results, err := e.processVariant(iconData, coll, name, opts)
+                            outcomesChan <- variantOutcome{results, err}
+                        }(collection, iconName, options)
+                    }
+                }
+            }
+            
+            // Formatos que agrupan todos los tamaños en un archivo
+            if hasBundles {
                 for _, col := range colors {
                     wg.Add(1)
                     
-                    go func(coll, name string, w, h int, clr string) {
+                    go func(coll, name, clr string) {
                         defer wg.Done()
                         
-                        options := map[string]interface{}{
-                            "width":  w,
-                            "height": h,
-                            "color":  clr,
-                        }
-                        
-                        success, err := e.processVariant(iconData, coll, name, options)
-                        if err != nil {
-                            errorsChan <- err
-                        } else {
-                            resultsChan <- success
-                        }
-                    }(collection, iconName, size[0], size[1], col)
+                        results, err := e.processBundle(iconData, coll, name, clr, sizes)
+                        outcomesChan <- variantOutcome{results, err}
+                    }(collection, iconName, col)
                 }
             }
         }
@@ -513,27 +916,78 @@ func (e *IconExporter) ExportWithVariants(sizes [][2]int, colors []string) (Expo
     // Esperar a que todas las goroutines terminen
     go func() {
         wg.Wait()
-        close(errorsChan)
-        close(resultsChan)
+        close(outcomesChan)
     }()
     
-    // Procesar resultados
-    for success := range resultsChan {
-        totalProcessed += success
+    // Procesar resultados y contar errores
+    for outcome := range outcomesChan {
+        if outcome.err != nil {
+            totalErrors++
+            continue
+        }
+        for _, result := range outcome.results {
+            switch {
+            case result.Skipped:
+                totalSkipped++
+            case result.Error != "":
+                totalErrors++
+            default:
+                totalProcessed++
+            }
+        }
+        allResults = append(allResults, outcome.results...)
+    }
+    
+    // Sprite SVG con un <symbol> por icono y color
+    var grouped []ExportResult
+    if e.config.Sprite.Enabled && len(iconEntries) > 0 {
+        grouped = append(grouped, e.exportSprite(iconEntries, colors)...)
+    }
+    
+    // Hojas de sprites raster por tamaño y color
+    if e.config.Atlas.Enabled && len(iconEntries) > 0 {
+        grouped = append(grouped, e.exportAtlases(iconEntries, sizes, colors)...)
     }
     
-    // Contar errores
-    for range errorsChan {
-        totalErrors++
+    // ResourceDictionary de XAML con geometrías e imágenes por color
+    if e.config.Xaml.Enabled && len(iconEntries) > 0 {
+        grouped = append(grouped, e.exportXaml(iconEntries, colors)...)
     }
     
+    // Catálogo PDF de varias páginas por colección
+    if e.config.PdfCatalog.Enabled && len(iconEntries) > 0 {
+        grouped = append(grouped, e.exportPdfCatalogs(iconEntries, colors)...)
+    }
+    for _, result := range grouped {
+        switch {
+        case result.Skipped:
+            totalSkipped++
+        case result.Error != "":
+            totalErrors++
+        default:
+            totalProcessed++
+        }
+        allResults = append(allResults, result)
+    }
+    
+    // Las goroutines terminan en cualquier orden; el informe sigue siendo estable
+    sort.SliceStable(allResults, func(i, j int) bool {
+        return allResults[i].Path < allResults[j].Path
+    })
+    
     duration := time.Since(startTime).Seconds()
+    if totalSkipped > 0 {
+        fmt.Printf("\n⏭️ Omitidos por el análisis previo: %d\n", totalSkipped)
+    }
     e.printExportSummary(totalProcessed, totalErrors, duration)
     
     return ExportSummary{
         Processed: totalProcessed,
         Errors:    totalErrors,
+        Skipped:   totalSkipped,
         Duration:  duration,
+        Results:   allResults,
+        Sanitized: e.sanitizeReports(),
     }, nil
 }
 
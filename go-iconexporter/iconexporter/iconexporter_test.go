@@ -0,0 +1,229 @@
+// iconexporter/iconexporter_test.go
+package iconexporter
+
+import (
+    "bytes"
+    "encoding/binary"
+    "image"
+    "image/color"
+    "image/png"
+    "math/rand"
+    "os"
+    "path/filepath"
+    "testing"
+)
+
+// Patrones de imagen de prueba: ruido (sin repeticiones), pocos colores
+// (muchas repeticiones), opaca uniforme y bandas con transparencia
+const (
+    patternNoise = iota
+    patternFewColors
+    patternSolid
+    patternBands
+)
+
+// testImage genera una imagen determinista con el patrón indicado
+func testImage(width, height, pattern int) *image.NRGBA {
+    rnd := rand.New(rand.NewSource(int64(width*31 + height*7 + pattern)))
+    img := image.NewNRGBA(image.Rect(0, 0, width, height))
+    for i := range img.Pix {
+        switch pattern {
+        case patternNoise:
+            img.Pix[i] = byte(rnd.Intn(256))
+        case patternFewColors:
+            img.Pix[i] = byte(rnd.Intn(3) * 100)
+        case patternSolid:
+            img.Pix[i] = 255
+        case patternBands:
+            img.Pix[i] = byte((i / 7) % 5 * 40)
+        }
+    }
+    return img
+}
+
+// sameNRGBA compara dos imágenes píxel a píxel; los píxeles totalmente
+// transparentes se consideran iguales sea cual sea su color
+func sameNRGBA(t *testing.T, want *image.NRGBA, got image.Image) {
+    t.Helper()
+    if got.Bounds().Size() != want.Bounds().Size() {
+        t.Fatalf("tamaño %v, se esperaba %v", got.Bounds().Size(), want.Bounds().Size())
+    }
+    offset := got.Bounds().Min
+    for y := 0; y < want.Bounds().Dy(); y++ {
+        for x := 0; x < want.Bounds().Dx(); x++ {
+            a := want.NRGBAAt(x, y)
+            b := color.NRGBAModel.Convert(got.At(x+offset.X, y+offset.Y)).(color.NRGBA)
+            if a != b && !(a.A == 0 && b.A == 0) {
+                t.Fatalf("píxel %d,%d: %v, se esperaba %v", x, y, b, a)
+            }
+        }
+    }
+}
+
+// newTestExporter crea un exportador con las colecciones de ejemplo que
+// escribe en un directorio temporal
+func newTestExporter(t *testing.T, config Config) *IconExporter {
+    t.Helper()
+    if len(config.Collections) == 0 {
+        config.Collections = []string{"nonicons", "devicon"}
+    }
+    if config.OutputDir == "" {
+        config.OutputDir = t.TempDir()
+    }
+    exporter, err := NewIconExporter(config)
+    if err != nil {
+        t.Fatal(err)
+    }
+    return exporter
+}
+
+// testSvg envuelve un cuerpo en un documento SVG de 24x24
+func testSvg(body string) []byte {
+    return []byte(`<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" viewBox="0 0 24 24" width="24" height="24">` + body + `</svg>`)
+}
+
+// encodeWith codifica un SVG con el formato indicado y devuelve la salida y
+// los avisos
+func encodeWith(t *testing.T, encoder FormatEncoder, svg []byte, fileName string, options map[string]interface{}) (string, []string) {
+    t.Helper()
+    var warnings []string
+    var buf bytes.Buffer
+    input := EncodeInput{
+        Collection: "prueba",
+        Icon:       "icono",
+        Color:      "#123456",
+        Width:      24,
+        Height:     24,
+        SVG:        svg,
+        FileName:   fileName,
+        Warn:       func(message string) { warnings = append(warnings, message) },
+    }
+    if err := encoder.Encode(&buf, input, options); err != nil {
+        t.Fatal(err)
+    }
+    return buf.String(), warnings
+}
+
+// pixelDiff cuenta los píxeles cuyos canales difieren en más de tolerance
+func pixelDiff(a, b *image.NRGBA, tolerance int) int {
+    diff := 0
+    for i := 0; i < len(a.Pix); i += 4 {
+        for c := 0; c < 4; c++ {
+            d := int(a.Pix[i+c]) - int(b.Pix[i+c])
+            if d > tolerance || d < -tolerance {
+                diff++
+                break
+            }
+        }
+    }
+    return diff
+}
+
+func TestGenerateFileNameScale(t *testing.T) {
+    tests := []struct {
+        name    string
+        pattern string
+        options map[string]interface{}
+        want    string
+    }{
+        {
+            name:    "sin escalas",
+            pattern: "{icon}-{width}x{height}",
+            options: map[string]interface{}{"width": 24, "height": 24},
+            want:    "bell-24x24.png",
+        },
+        {
+            name:    "1x sin sufijo",
+            pattern: "{icon}-{width}x{height}",
+            options: map[string]interface{}{"width": 24, "height": 24, "scale": 1.0, "logicalWidth": 24, "logicalHeight": 24},
+            want:    "bell-24x24.png",
+        },
+        {
+            name:    "@2x con tamaño lógico",
+            pattern: "{icon}-{width}x{height}",
+            options: map[string]interface{}{"width": 48, "height": 48, "scale": 2.0, "logicalWidth": 24, "logicalHeight": 24},
+            want:    "bell-24x24@2x.png",
+        },
+        {
+            name:    "@1.5x",
+            pattern: "{icon}-{width}",
+            options: map[string]interface{}{"width": 36, "height": 36, "scale": 1.5, "logicalWidth": 24, "logicalHeight": 24},
+            want:    "bell-24@1.5x.png",
+        },
+        {
+            name:    "{scale} explícito usa píxeles",
+            pattern: "{icon}-{logicalWidth}-{width}{scale}",
+            options: map[string]interface{}{"width": 72, "height": 72, "scale": 3.0, "logicalWidth": 24, "logicalHeight": 24},
+            want:    "bell-24-72@3x.png",
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            e := newTestExporter(t, Config{FileNaming: FileNamingConfig{Pattern: tt.pattern, Case: "kebab"}})
+            options := map[string]interface{}{"color": "red", "format": "png"}
+            for key, value := range tt.options {
+                options[key] = value
+            }
+            if got := e.generateFileName("nonicons", "bell", options); got != tt.want {
+                t.Fatalf("%q, se esperaba %q", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestExportScaleFactors(t *testing.T) {
+    e := newTestExporter(t, Config{
+        Collections:   []string{"nonicons"},
+        OutputFormats: []string{"png"},
+        ScaleFactors:  []float64{1, 1.5, 2, 3},
+        FileNaming:    FileNamingConfig{Case: "kebab"},
+    })
+    summary, err := e.ExportWithVariants([][2]int{{24, 24}}, nil)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if summary.Processed != 4 || summary.Errors != 0 {
+        t.Fatalf("resumen inesperado: %+v", summary)
+    }
+    
+    tests := []struct {
+        file string
+        size int
+        dpi  int
+    }{
+        {"nonicons-bell-24x24.png", 24, 72},
+        {"nonicons-bell-24x24@1.5x.png", 36, 108},
+        {"nonicons-bell-24x24@2x.png", 48, 144},
+        {"nonicons-bell-24x24@3x.png", 72, 216},
+    }
+    for _, tt := range tests {
+        t.Run(tt.file, func(t *testing.T) {
+            data, err := os.ReadFile(filepath.Join(e.config.OutputDir, tt.file))
+            if err != nil {
+                t.Fatal(err)
+            }
+            img, err := png.Decode(bytes.NewReader(data))
+            if err != nil || img.Bounds().Dx() != tt.size || img.Bounds().Dy() != tt.size {
+                t.Fatalf("%v, se esperaban %d px", err, tt.size)
+            }
+            
+            // La resolución del pHYs sigue a la densidad
+            phys := bytes.Index(data, []byte("pHYs"))
+            if phys < 0 {
+                t.Fatal("falta el chunk pHYs")
+            }
+            want := uint32(float64(tt.dpi)/0.0254 + 0.5)
+            if got := binary.BigEndian.Uint32(data[phys+4:]); got != want {
+                t.Fatalf("%d píxeles por metro, se esperaban %d", got, want)
+            }
+        })
+    }
+}
+
+func TestScaleFactorsValidation(t *testing.T) {
+    for _, scales := range [][]float64{{0}, {2, -1}} {
+        if _, err := NewIconExporter(Config{Collections: []string{"nonicons"}, ScaleFactors: scales}); err == nil {
+            t.Errorf("%v: se esperaba un error", scales)
+        }
+    }
+}
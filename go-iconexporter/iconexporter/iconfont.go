@@ -0,0 +1,438 @@
+// iconexporter/iconfont.go
+package iconexporter
+
+import (
+    "encoding/json"
+    "fmt"
+    "io"
+    "os"
+    "path/filepath"
+    "strings"
+    "time"
+)
+
+// Métricas de la fuente: los iconos ocupan el em completo, de Descent a Ascent
+const (
+    iconFontUnitsPerEm = 1000
+    iconFontAscent     = 850
+    iconFontDescent    = -150
+    iconFontTolerance  = 1.0 // error máximo al convertir cúbicas, en unidades
+)
+
+// Formatos de fuente admitidos, en el orden de preferencia de @font-face
+var iconFontFormats = []struct {
+    Name, CSSFormat string
+}{
+    {"woff2", "woff2"},
+    {"woff", "woff"},
+    {"ttf", "truetype"},
+}
+
+// IconFontConfig configura la generación de la fuente de iconos
+type IconFontConfig struct {
+    FontName       string   `json:"fontName"`
+    Formats        []string `json:"formats"`
+    CodepointsFile string   `json:"codepointsFile"`
+    StartCodepoint int      `json:"startCodepoint"`
+    Ligatures      bool     `json:"ligatures"`
+    ClassPrefix    string   `json:"classPrefix"`
+}
+
+// iconFontGlyph es un icono ya asignado a su código en la fuente
+type iconFontGlyph struct {
+    Entry     iconEntry
+    Name      string // nombre de clase CSS y clave del JSON
+    Codepoint int
+    Ligature  string
+}
+
+// iconFontConfig completa la configuración con valores por defecto y la valida
+func (e *IconExporter) iconFontConfig() (IconFontConfig, error) {
+    cfg := e.config.IconFont
+    if cfg.FontName == "" {
+        cfg.FontName = "icons"
+    }
+    if len(cfg.Formats) == 0 {
+        cfg.Formats = []string{"ttf", "woff", "woff2"}
+    }
+    for _, format := range cfg.Formats {
+        valid := false
+        for _, known := range iconFontFormats {
+            valid = valid || known.Name == format
+        }
+        if !valid {
+            return cfg, fmt.Errorf("formato de fuente no válido: %s (admitidos: ttf, woff, woff2)", format)
+        }
+    }
+    if cfg.CodepointsFile == "" {
+        cfg.CodepointsFile = filepath.Join(e.config.OutputDir, cfg.FontName+".codepoints.json")
+    }
+    if cfg.StartCodepoint == 0 {
+        cfg.StartCodepoint = 0xE000 // inicio del área de uso privado
+    }
+    if cfg.StartCodepoint < 0xE000 || (cfg.StartCodepoint > 0xF8FF && cfg.StartCodepoint < 0xF0000) || cfg.StartCodepoint > 0x10FFFD {
+        return cfg, fmt.Errorf("startCodepoint fuera del área de uso privado: U+%04X", cfg.StartCodepoint)
+    }
+    if cfg.ClassPrefix == "" {
+        cfg.ClassPrefix = "icon"
+    }
+    return cfg, nil
+}
+
+// loadCodepoints lee el mapa persistido "colección:icono" → código
+func loadCodepoints(path string) (map[string]int, error) {
+    codepoints := map[string]int{}
+    data, err := os.ReadFile(path)
+    if os.IsNotExist(err) {
+        return codepoints, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+    if err := json.Unmarshal(data, &codepoints); err != nil {
+        return nil, fmt.Errorf("error leyendo %s: %w", path, err)
+    }
+    return codepoints, nil
+}
+
+// assignCodepoints asigna códigos a los iconos nuevos sin tocar los existentes,
+// de modo que los códigos no cambian entre ejecuciones. Devuelve si hubo cambios.
+func assignCodepoints(codepoints map[string]int, keys []string, start int) (bool, error) {
+    used := map[int]bool{}
+    next := start
+    for _, code := range codepoints {
+        used[code] = true
+        if code >= next {
+            next = code + 1
+        }
+    }
+    
+    changed := false
+    for _, key := range keys {
+        if _, ok := codepoints[key]; ok {
+            continue
+        }
+        for used[next] || (next > 0xF8FF && next < 0xF0000) {
+            next++
+        }
+        if next > 0x10FFFD {
+            return changed, fmt.Errorf("no quedan códigos de uso privado para %s", key)
+        }
+        codepoints[key] = next
+        used[next] = true
+        changed = true
+    }
+    return changed, nil
+}
+
+// iconFontGlyphs asigna códigos (persistidos en CodepointsFile) y ligaduras
+func (e *IconExporter) iconFontGlyphs(cfg IconFontConfig, entries []iconEntry) ([]iconFontGlyph, error) {
+    codepoints, err := loadCodepoints(cfg.CodepointsFile)
+    if err != nil {
+        return nil, err
+    }
+    keys := make([]string, len(entries))
+    for i, entry := range entries {
+        keys[i] = entry.Collection + ":" + entry.Name
+    }
+    changed, err := assignCodepoints(codepoints, keys, cfg.StartCodepoint)
+    if err != nil {
+        return nil, err
+    }
+    if changed {
+        if err := e.ensureOutputDir(filepath.Dir(cfg.CodepointsFile)); err != nil {
+            return nil, err
+        }
+        err := writeFile(cfg.CodepointsFile, func(w io.Writer) error {
+            encoder := json.NewEncoder(w)
+            encoder.SetIndent("", "  ")
+            return encoder.Encode(codepoints)
+        })
+        if err != nil {
+            return nil, fmt.Errorf("error guardando %s: %w", cfg.CodepointsFile, err)
+        }
+    }
+    
+    glyphs := make([]iconFontGlyph, len(entries))
+    for i, entry := range entries {
+        glyphs[i] = iconFontGlyph{
+            Entry:     entry,
+            Name:      e.spriteID("{collection}-{icon}", entry.Collection, entry.Name, "", false),
+            Codepoint: codepoints[keys[i]],
+            Ligature:  strings.ToLower(entry.Name),
+        }
+    }
+    return glyphs, nil
+}
+
+// buildIconFont convierte los iconos en glifos y genera el TTF. Los trazos,
+// degradados y la regla evenodd no se pueden representar y se avisan en warn.
+func (e *IconExporter) buildIconFont(cfg IconFontConfig, glyphs []iconFontGlyph, warn func(message string)) ([]byte, error) {
+    font := &ttFont{
+        FamilyName: cfg.FontName,
+        UnitsPerEm: iconFontUnitsPerEm,
+        Ascent:     iconFontAscent,
+        Descent:    iconFontDescent,
+        Glyphs:     []ttGlyph{{Name: ".notdef", Advance: iconFontUnitsPerEm / 2}},
+        CharMap:    map[rune]int{},
+    }
+    
+    for _, glyph := range glyphs {
+        label := glyph.Entry.Collection + ":" + glyph.Entry.Name
+        svg := e.prepareSvgBuffer(glyph.Entry.Icon, glyph.Entry.Icon.Width, glyph.Entry.Icon.Height, "", "")
+        shapes, box, err := flattenSvg(svg, e.config.DefaultColor, func(message string) {
+            warn(label + ": " + message)
+        })
+        if err != nil {
+            return nil, fmt.Errorf("%s: %w", label, err)
+        }
+        if box[3] <= 0 {
+            return nil, fmt.Errorf("%s: viewBox sin altura", label)
+        }
+        
+        // viewBox → unidades de la fuente con el eje Y hacia arriba
+        scale := float64(iconFontUnitsPerEm) / box[3]
+        toFont := affine{scale, 0, 0, -scale, -box[0] * scale, iconFontAscent + box[1]*scale}
+        
+        g := ttGlyph{Name: glyph.Name, Advance: int(box[2]*scale + 0.5)}
+        for _, shape := range shapes {
+            if !shape.Stroke.None {
+                warn(label + ": los trazos no se pueden representar en una fuente, se omiten")
+            }
+            if shape.Fill.None {
+                continue
+            }
+            if shape.EvenOdd {
+                warn(label + ": fill-rule evenodd se dibuja como nonzero en la fuente")
+            }
+            g.Contours = append(g.Contours, glyphContours(transformPath(shape.Segments, toFont), iconFontTolerance)...)
+        }
+        font.CharMap[rune(glyph.Codepoint)] = len(font.Glyphs)
+        font.Glyphs = append(font.Glyphs, g)
+    }
+    
+    if cfg.Ligatures {
+        addIconFontLigatures(font, glyphs, warn)
+    }
+    return font.encode()
+}
+
+// addIconFontLigatures añade un glifo vacío por carácter usado en los nombres y
+// una ligadura por icono que sustituye el nombre por el glifo
+func addIconFontLigatures(font *ttFont, glyphs []iconFontGlyph, warn func(message string)) {
+    seen := map[string]bool{}
+    for i, glyph := range glyphs {
+        if seen[glyph.Ligature] {
+            warn(fmt.Sprintf("ligadura %q repetida, solo se usa para el primer icono", glyph.Ligature))
+            continue
+        }
+        seen[glyph.Ligature] = true
+        
+        components := make([]int, 0, len(glyph.Ligature))
+        for _, r := range glyph.Ligature {
+            index, ok := font.CharMap[r]
+            if !ok {
+                index = len(font.Glyphs)
+                font.Glyphs = append(font.Glyphs, ttGlyph{Name: string(r)})
+                font.CharMap[r] = index
+            }
+            components = append(components, index)
+        }
+        // Los glifos de iconos empiezan en 1, tras .notdef
+        font.Ligatures = append(font.Ligatures, ttLigature{components, i + 1})
+    }
+}
+
+// iconFontCSS genera el @font-face y una clase por icono
+func iconFontCSS(cfg IconFontConfig, glyphs []iconFontGlyph) string {
+    var b strings.Builder
+    b.WriteString(iconFontFace(cfg))
+    fmt.Fprintf(&b, "\n[class^=\"%[1]s-\"],\n[class*=\" %[1]s-\"] {\n", cfg.ClassPrefix)
+    b.WriteString(iconFontBaseRules(cfg, "  "))
+    b.WriteString("}\n\n")
+    for _, glyph := range glyphs {
+        fmt.Fprintf(&b, ".%s-%s::before { content: \"\\%x\"; }\n", cfg.ClassPrefix, glyph.Name, glyph.Codepoint)
+    }
+    return b.String()
+}
+
+// iconFontSCSS genera variables, un mapa de códigos y las clases con @each
+func iconFontSCSS(cfg IconFontConfig, glyphs []iconFontGlyph) string {
+    variable := strings.ReplaceAll(cfg.FontName, " ", "-")
+    var b strings.Builder
+    fmt.Fprintf(&b, "$%s-font-family: \"%s\" !default;\n", variable, cfg.FontName)
+    fmt.Fprintf(&b, "$%s-prefix: \"%s\" !default;\n\n", variable, cfg.ClassPrefix)
+    fmt.Fprintf(&b, "$%s-codepoints: (\n", variable)
+    for _, glyph := range glyphs {
+        fmt.Fprintf(&b, "  \"%s\": \"\\%x\",\n", glyph.Name, glyph.Codepoint)
+    }
+    b.WriteString(");\n\n")
+    b.WriteString(iconFontFace(cfg))
+    fmt.Fprintf(&b, "\n%%%s-base {\n", variable)
+    b.WriteString(iconFontBaseRules(cfg, "  "))
+    b.WriteString("}\n\n")
+    fmt.Fprintf(&b, "@each $name, $codepoint in $%s-codepoints {\n", variable)
+    fmt.Fprintf(&b, "  .#{$%s-prefix}-#{$name}::before {\n", variable)
+    fmt.Fprintf(&b, "    @extend %%%s-base;\n", variable)
+    b.WriteString("    content: $codepoint;\n  }\n}\n")
+    return b.String()
+}
+
+// iconFontFace genera la regla @font-face con los formatos exportados
+func iconFontFace(cfg IconFontConfig) string {
+    var sources []string
+    for _, format := range iconFontFormats {
+        for _, name := range cfg.Formats {
+            if name == format.Name {
+                sources = append(sources, fmt.Sprintf("url(\"%s.%s\") format(\"%s\")", cfg.FontName, format.Name, format.CSSFormat))
+            }
+        }
+    }
+    return fmt.Sprintf(`@font-face {
+  font-family: "%s";
+  src: %s;
+  font-weight: normal;
+  font-style: normal;
+  font-display: block;
+}
+`, cfg.FontName, strings.Join(sources, ",\n       "))
+}
+
+// iconFontBaseRules son las propiedades comunes a todos los iconos
+func iconFontBaseRules(cfg IconFontConfig, indent string) string {
+    rules := []string{
+        fmt.Sprintf("font-family: \"%s\" !important;", cfg.FontName),
+        "font-style: normal;",
+        "font-weight: normal;",
+        "font-variant: normal;",
+        "text-transform: none;",
+        "line-height: 1;",
+        "-webkit-font-smoothing: antialiased;",
+        "-moz-osx-font-smoothing: grayscale;",
+    }
+    if cfg.Ligatures {
+        rules = append(rules, "font-feature-settings: \"liga\";")
+    }
+    return indent + strings.Join(rules, "\n"+indent) + "\n"
+}
+
+// ExportIconFont genera la fuente de iconos (TTF, WOFF, WOFF2) con su CSS, SCSS
+// y la tabla de códigos en JSON
+func (e *IconExporter) ExportIconFont() (ExportSummary, error) {
+    startTime := time.Now()
+    
+    cfg, err := e.iconFontConfig()
+    if err != nil {
+        return ExportSummary{}, err
+    }
+    if err := e.ensureOutputDir(e.config.OutputDir); err != nil {
+        return ExportSummary{}, fmt.Errorf("error creando directorio de salida: %w", err)
+    }
+    
+    entries, missing := e.collectIconEntries()
+    if len(entries) == 0 {
+        return ExportSummary{}, fmt.Errorf("no hay iconos para la fuente")
+    }
+    fmt.Printf("\n🔤 Generando fuente de iconos: %s (%d iconos)\n", cfg.FontName, len(entries))
+    
+    glyphs, err := e.iconFontGlyphs(cfg, entries)
+    if err != nil {
+        return ExportSummary{}, err
+    }
+    
+    var warnings []string
+    ttf, err := e.buildIconFont(cfg, glyphs, func(message string) {
+        for _, existing := range warnings {
+            if existing == message {
+                return
+            }
+        }
+        warnings = append(warnings, message)
+    })
+    if err != nil {
+        return ExportSummary{}, err
+    }
+    
+    var results []ExportResult
+    save := func(fileName, format string, write func(w io.Writer) error) {
+        filePath := filepath.Join(e.config.OutputDir, fileName)
+        result := ExportResult{Icon: cfg.FontName, Format: format, Path: filePath}
+        if err := writeFile(filePath, write); err != nil {
+            fmt.Printf("❌ Error al guardar %s: %v\n", filePath, err)
+            result.Error = err.Error()
+        } else {
+            fmt.Printf("✅ Exportado: %s\n", filePath)
+        }
+        results = append(results, result)
+    }
+    
+    for _, format := range cfg.Formats {
+        save(cfg.FontName+"."+format, format, func(w io.Writer) error {
+            data := ttf
+            var err error
+            switch format {
+            case "woff":
+                data, err = encodeWOFF(ttf)
+            case "woff2":
+                data, err = encodeWOFF2(ttf)
+            }
+            if err != nil {
+                return err
+            }
+            _, err = w.Write(data)
+            return err
+        })
+    }
+    // Los avisos de conversión se asocian a la fuente
+    if len(results) > 0 {
+        results[0].Warnings = warnings
+        printWarnings(results[0])
+    }
+    
+    save(cfg.FontName+".css", "css", func(w io.Writer) error {
+        _, err := io.WriteString(w, iconFontCSS(cfg, glyphs))
+        return err
+    })
+    save(cfg.FontName+".scss", "scss", func(w io.Writer) error {
+        _, err := io.WriteString(w, iconFontSCSS(cfg, glyphs))
+        return err
+    })
+    save(cfg.FontName+".json", "json", func(w io.Writer) error {
+        table := make(map[string]int, len(glyphs))
+        for _, glyph := range glyphs {
+            table[glyph.Name] = glyph.Codepoint
+        }
+        encoder := json.NewEncoder(w)
+        encoder.SetIndent("", "  ")
+        return encoder.Encode(table)
+    })
+    
+    processed, errors := 0, missing
+    for _, result := range results {
+        if result.Error != "" {
+            errors++
+        } else {
+            processed++
+        }
+    }
+    
+    duration := time.Since(startTime).Seconds()
+    e.printExportSummary(processed, errors, duration)
+    
+    return ExportSummary{
+        Processed: processed,
+        Errors:    errors,
+        Duration:  duration,
+        Results:   results,
+        Sanitized: e.sanitizeReports(),
+    }, nil
+}
+
+// ExportIconFont genera la fuente de iconos con la configuración dada
+func ExportIconFont(config Config) (ExportSummary, error) {
+    exporter, err := NewIconExporter(config)
+    if err != nil {
+        return ExportSummary{}, err
+    }
+    return exporter.ExportIconFont()
+}
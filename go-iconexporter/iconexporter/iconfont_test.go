@@ -0,0 +1,199 @@
+// iconexporter/iconfont_test.go
+package iconexporter
+
+import (
+    "encoding/json"
+    "os"
+    "path/filepath"
+    "reflect"
+    "strings"
+    "testing"
+
+    "golang.org/x/image/font"
+    "golang.org/x/image/font/sfnt"
+    "golang.org/x/image/math/fixed"
+)
+
+// testIconFont genera el TTF de las colecciones de ejemplo
+func testIconFont(t *testing.T, ligatures bool) ([]byte, []iconFontGlyph) {
+    t.Helper()
+    e := newTestExporter(t, Config{IconFont: IconFontConfig{Ligatures: ligatures}})
+    cfg, err := e.iconFontConfig()
+    if err != nil {
+        t.Fatal(err)
+    }
+    entries, _ := e.collectIconEntries()
+    glyphs, err := e.iconFontGlyphs(cfg, entries)
+    if err != nil {
+        t.Fatal(err)
+    }
+    ttf, err := e.buildIconFont(cfg, glyphs, func(message string) { t.Log(message) })
+    if err != nil {
+        t.Fatal(err)
+    }
+    return ttf, glyphs
+}
+
+func TestAssignCodepoints(t *testing.T) {
+    tests := []struct {
+        name     string
+        existing map[string]int
+        keys     []string
+        start    int
+        want     map[string]int
+        changed  bool
+        wantErr  bool
+    }{
+        {
+            name:    "vacío",
+            keys:    []string{"a:x", "a:y"},
+            start:   0xE000,
+            want:    map[string]int{"a:x": 0xE000, "a:y": 0xE001},
+            changed: true,
+        },
+        {
+            name:     "se conservan los existentes",
+            existing: map[string]int{"a:y": 0xE005},
+            keys:     []string{"a:x", "a:y"},
+            start:    0xE000,
+            want:     map[string]int{"a:x": 0xE006, "a:y": 0xE005},
+            changed:  true,
+        },
+        {
+            name:     "sin cambios",
+            existing: map[string]int{"a:x": 0xE000},
+            keys:     []string{"a:x"},
+            start:    0xE000,
+            want:     map[string]int{"a:x": 0xE000},
+        },
+        {
+            name:    "salta al área suplementaria",
+            keys:    []string{"a:x", "a:y"},
+            start:   0xF8FF,
+            want:    map[string]int{"a:x": 0xF8FF, "a:y": 0xF0000},
+            changed: true,
+        },
+        {
+            name:    "sin códigos libres",
+            keys:    []string{"a:x", "a:y"},
+            start:   0x10FFFD,
+            want:    map[string]int{"a:x": 0x10FFFD},
+            changed: true,
+            wantErr: true,
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            codepoints := map[string]int{}
+            for key, code := range tt.existing {
+                codepoints[key] = code
+            }
+            changed, err := assignCodepoints(codepoints, tt.keys, tt.start)
+            if (err != nil) != tt.wantErr || changed != tt.changed || !reflect.DeepEqual(codepoints, tt.want) {
+                t.Fatalf("%v, %v, %x; se esperaba %v, %x", changed, err, codepoints, tt.changed, tt.want)
+            }
+        })
+    }
+}
+
+// El TTF se lee con x/image/font/sfnt y cada código lleva a un glifo con contornos
+func TestIconFontSfnt(t *testing.T) {
+    for _, ligatures := range []bool{false, true} {
+        ttf, glyphs := testIconFont(t, ligatures)
+        f, err := sfnt.Parse(ttf)
+        if err != nil {
+            t.Fatal(err)
+        }
+        var buf sfnt.Buffer
+        if name, err := f.Name(&buf, sfnt.NameIDFamily); err != nil || name != "icons" {
+            t.Fatalf("familia %q (%v)", name, err)
+        }
+        
+        for _, glyph := range glyphs {
+            index, err := f.GlyphIndex(&buf, rune(glyph.Codepoint))
+            if err != nil || index == 0 {
+                t.Fatalf("%s: U+%04X sin glifo (%v)", glyph.Name, glyph.Codepoint, err)
+            }
+            segments, err := f.LoadGlyph(&buf, index, fixed.I(1000), nil)
+            if err != nil || len(segments) == 0 {
+                t.Fatalf("%s: %d segmentos (%v)", glyph.Name, len(segments), err)
+            }
+            // Los iconos de 24x24 ocupan un em de ancho
+            advance, err := f.GlyphAdvance(&buf, index, fixed.I(1000), font.HintingNone)
+            if err != nil || advance.Round() != 1000 {
+                t.Fatalf("%s: avance %v (%v)", glyph.Name, advance, err)
+            }
+            bounds, _, err := f.GlyphBounds(&buf, index, fixed.I(1000), font.HintingNone)
+            if err != nil || bounds.Min.X < 0 || bounds.Max.X.Round() > 1000 || bounds.Min.Y.Round() < -850 || bounds.Max.Y.Round() > 150 {
+                t.Fatalf("%s: límites %v fuera del em (%v)", glyph.Name, bounds, err)
+            }
+        }
+        
+        // Con ligaduras cada letra del nombre tiene glifo propio
+        index, err := f.GlyphIndex(&buf, 'b')
+        if hasLetter := err == nil && index != 0; hasLetter != ligatures {
+            t.Fatalf("glifo de 'b': %v, con ligaduras %v", hasLetter, ligatures)
+        }
+    }
+}
+
+func TestExportIconFont(t *testing.T) {
+    e := newTestExporter(t, Config{IconFont: IconFontConfig{FontName: "app-icons", ClassPrefix: "ai"}})
+    summary, err := e.ExportIconFont()
+    if err != nil {
+        t.Fatal(err)
+    }
+    if summary.Processed != 6 || summary.Errors != 0 {
+        t.Fatalf("resumen inesperado: %+v", summary)
+    }
+    
+    read := func(name string) string {
+        data, err := os.ReadFile(filepath.Join(e.config.OutputDir, name))
+        if err != nil {
+            t.Fatal(err)
+        }
+        return string(data)
+    }
+    var codepoints map[string]int
+    if err := json.Unmarshal([]byte(read("app-icons.codepoints.json")), &codepoints); err != nil {
+        t.Fatal(err)
+    }
+    want := map[string]int{"nonicons:bell": 0xE000, "devicon:angular": 0xE001}
+    if !reflect.DeepEqual(codepoints, want) {
+        t.Fatalf("códigos %x, se esperaban %x", codepoints, want)
+    }
+    css := read("app-icons.css")
+    for _, fragment := range []string{
+        `font-family: "app-icons";`,
+        `url("app-icons.woff2") format("woff2")`,
+        `.ai-nonicons-bell::before { content: "\e000"; }`,
+    } {
+        if !strings.Contains(css, fragment) {
+            t.Errorf("falta %q en:\n%s", fragment, css)
+        }
+    }
+    
+    // Los códigos persisten aunque cambien los iconos exportados
+    e.config.Collections = []string{"devicon"}
+    if _, err := e.ExportIconFont(); err != nil {
+        t.Fatal(err)
+    }
+    if !strings.Contains(read("app-icons.css"), `.ai-devicon-angular::before { content: "\e001"; }`) {
+        t.Fatal("el código de devicon:angular ha cambiado")
+    }
+}
+
+func TestIconFontConfigErrors(t *testing.T) {
+    for name, cfg := range map[string]IconFontConfig{
+        "formato":        {Formats: []string{"otf"}},
+        "código bajo":    {StartCodepoint: 0x41},
+        "entre áreas":    {StartCodepoint: 0xF900},
+        "fuera de rango": {StartCodepoint: 0x110000},
+    } {
+        t.Run(name, func(t *testing.T) {
+            if _, err := NewIconExporter(Config{Collections: []string{"nonicons"}, IconFont: cfg}); err == nil {
+                t.Fatal("se esperaba un error")
+            }
+        })
+    }
+}
@@ -0,0 +1,313 @@
+// iconexporter/pdf.go
+package iconexporter
+
+import (
+    "bytes"
+    "compress/zlib"
+    "fmt"
+    "io"
+    "path/filepath"
+    "strings"
+)
+
+// pdfFormat convierte el SVG preparado en un PDF vectorial de una página
+type pdfFormat struct{}
+
+func init() {
+    mustRegisterFormat(pdfFormat{})
+}
+
+func (pdfFormat) Name() string      { return "pdf" }
+func (pdfFormat) Extension() string { return "pdf" }
+func (pdfFormat) Vector() bool      { return true }
+
+// pdfPage es una página con su contenido ya en operadores PDF
+type pdfPage struct {
+    Width, Height float64
+    Content       string
+}
+
+// pdfBuilder reúne las páginas y los recursos compartidos de un documento
+type pdfBuilder struct {
+    pages  []pdfPage
+    states []string // diccionarios ExtGState; el nombre es /GS<índice>
+    fonts  bool
+}
+
+// alphaState devuelve (y registra si es nuevo) el ExtGState con esas opacidades
+func (p *pdfBuilder) alphaState(fill, stroke float64) string {
+    state := fmt.Sprintf("<< /Type /ExtGState /ca %s /CA %s >>", formatNumber(fill, 3), formatNumber(stroke, 3))
+    for i, existing := range p.states {
+        if existing == state {
+            return fmt.Sprintf("GS%d", i)
+        }
+    }
+    p.states = append(p.states, state)
+    return fmt.Sprintf("GS%d", len(p.states)-1)
+}
+
+// painter crea un pintor de operadores PDF enlazado a los recursos del documento
+func (p *pdfBuilder) painter(warn func(message string)) *vectorPainter {
+    return &vectorPainter{alphaState: p.alphaState, warn: warn}
+}
+
+// pdfString escribe un literal de cadena PDF en WinAnsi (Latin-1 aproximado)
+func pdfString(s string) string {
+    var b strings.Builder
+    b.WriteByte('(')
+    for _, r := range s {
+        switch {
+        case r == '(' || r == ')' || r == '\\':
+            b.WriteByte('\\')
+            b.WriteRune(r)
+        case r < 32 || r > 255:
+            b.WriteByte('?')
+        case r > 126:
+            fmt.Fprintf(&b, "\\%03o", r)
+        default:
+            b.WriteRune(r)
+        }
+    }
+    b.WriteByte(')')
+    return b.String()
+}
+
+// encode escribe el documento PDF 1.4 con los contenidos comprimidos
+func (p *pdfBuilder) encode(title string) ([]byte, error) {
+    var objects []string
+    add := func(body string) int {
+        objects = append(objects, body)
+        return len(objects)
+    }
+    stream := func(data string) (int, error) {
+        var compressed bytes.Buffer
+        zw := zlib.NewWriter(&compressed)
+        if _, err := io.WriteString(zw, data); err != nil {
+            return 0, err
+        }
+        if err := zw.Close(); err != nil {
+            return 0, err
+        }
+        return add(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", compressed.Len(), compressed.String())), nil
+    }
+    
+    catalogID := add("") // se completa al final
+    pagesID := add("")
+    infoID := add(fmt.Sprintf("<< /Title %s /Producer (iconexporter) >>", pdfString(title)))
+    
+    var resources strings.Builder
+    resources.WriteString("<<")
+    if len(p.states) > 0 {
+        resources.WriteString(" /ExtGState <<")
+        for i, state := range p.states {
+            fmt.Fprintf(&resources, " /GS%d %d 0 R", i, add(state))
+        }
+        resources.WriteString(" >>")
+    }
+    if p.fonts {
+        regular := add("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+        bold := add("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+        fmt.Fprintf(&resources, " /Font << /F1 %d 0 R /F2 %d 0 R >>", regular, bold)
+    }
+    resources.WriteString(" >>")
+    resourcesID := add(resources.String())
+    
+    kids := make([]string, len(p.pages))
+    for i, page := range p.pages {
+        contentID, err := stream(page.Content)
+        if err != nil {
+            return nil, err
+        }
+        pageID := add(fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %s %s] /Resources %d 0 R /Contents %d 0 R >>",
+            pagesID, formatNumber(page.Width, 3), formatNumber(page.Height, 3), resourcesID, contentID))
+        kids[i] = fmt.Sprintf("%d 0 R", pageID)
+    }
+    objects[catalogID-1] = fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesID)
+    objects[pagesID-1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids))
+    
+    var out bytes.Buffer
+    out.WriteString("%PDF-1.4\n%\xE2\xE3\xCF\xD3\n")
+    offsets := make([]int, len(objects))
+    for i, object := range objects {
+        offsets[i] = out.Len()
+        fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, object)
+    }
+    xref := out.Len()
+    fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+    for _, offset := range offsets {
+        fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+    }
+    fmt.Fprintf(&out, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+        len(objects)+1, catalogID, infoID, xref)
+    return out.Bytes(), nil
+}
+
+// vectorPageSize convierte el tamaño en píxeles a puntos según la opción "dpi"
+// (72 por defecto, un píxel por punto)
+func vectorPageSize(input EncodeInput, options map[string]interface{}) (float64, float64) {
+    dpi := optionInt(options, "dpi", 72)
+    if dpi <= 0 {
+        dpi = 72
+    }
+    return float64(input.Width) * 72 / float64(dpi), float64(input.Height) * 72 / float64(dpi)
+}
+
+func (pdfFormat) Encode(w io.Writer, input EncodeInput, options map[string]interface{}) error {
+    shapes, box, err := flattenSvg(input.SVG, input.Color, input.warn)
+    if err != nil {
+        return err
+    }
+    width, height := vectorPageSize(input, options)
+    
+    document := &pdfBuilder{}
+    painter := document.painter(input.warn)
+    painter.drawIcon(shapes, box, 0, 0, width, height)
+    document.pages = append(document.pages, pdfPage{width, height, painter.b.String()})
+    
+    data, err := document.encode(input.Collection + ":" + input.Icon)
+    if err != nil {
+        return err
+    }
+    _, err = w.Write(data)
+    return err
+}
+
+// Tamaños de página admitidos en el catálogo, en puntos
+var pdfPageSizes = map[string][2]float64{
+    "a4":     {595.276, 841.89},
+    "letter": {612, 792},
+}
+
+// PdfCatalogConfig configura el catálogo PDF con todos los iconos de cada
+// colección en una cuadrícula de varias páginas
+type PdfCatalogConfig struct {
+    Enabled  bool    `json:"enabled"`
+    FileName string  `json:"fileName"`
+    PageSize string  `json:"pageSize"`
+    IconSize float64 `json:"iconSize"` // en puntos
+}
+
+// pdfCatalogConfig completa la configuración con valores por defecto y la valida
+func (e *IconExporter) pdfCatalogConfig() (PdfCatalogConfig, error) {
+    cfg := e.config.PdfCatalog
+    if cfg.FileName == "" {
+        cfg.FileName = "{collection}-catalog.pdf"
+    }
+    if cfg.PageSize == "" {
+        cfg.PageSize = "a4"
+    }
+    if _, ok := pdfPageSizes[cfg.PageSize]; !ok {
+        return cfg, fmt.Errorf("tamaño de página no válido para el catálogo: %s (admitidos: a4, letter)", cfg.PageSize)
+    }
+    if cfg.IconSize == 0 {
+        cfg.IconSize = 48
+    }
+    if cfg.IconSize < 8 || cfg.IconSize > 400 {
+        return cfg, fmt.Errorf("iconSize del catálogo fuera de rango: %g (8-400 pt)", cfg.IconSize)
+    }
+    return cfg, nil
+}
+
+// exportPdfCatalogs escribe un catálogo por colección con cada icono y color
+// en una celda con su nombre debajo
+func (e *IconExporter) exportPdfCatalogs(entries []iconEntry, colors []string) []ExportResult {
+    cfg, _ := e.pdfCatalogConfig() // validada en validateConfig
+    const margin, header, labelSize, gutter = 36.0, 28.0, 7.0, 12.0
+    
+    pageSize := pdfPageSizes[cfg.PageSize]
+    cellWidth := max(cfg.IconSize, 72) + gutter
+    cellHeight := cfg.IconSize + labelSize*3 + gutter
+    columns := max(1, int((pageSize[0]-2*margin)/cellWidth))
+    rows := max(1, int((pageSize[1]-2*margin-header)/cellHeight))
+    maxLabel := int(cellWidth / (labelSize * 0.55)) // ancho medio de Helvetica
+    
+    // Conservar el orden de las colecciones de la configuración
+    var collections []string
+    byCollection := map[string][]iconEntry{}
+    for _, entry := range entries {
+        if _, ok := byCollection[entry.Collection]; !ok {
+            collections = append(collections, entry.Collection)
+        }
+        byCollection[entry.Collection] = append(byCollection[entry.Collection], entry)
+    }
+    
+    var results []ExportResult
+    for _, collection := range collections {
+        filePath := filepath.Join(e.config.OutputDir, strings.ReplaceAll(cfg.FileName, "{collection}", collection))
+        result := ExportResult{Collection: collection, Format: "pdf", Path: filePath}
+        warned := map[string]bool{}
+        warn := func(message string) {
+            if !warned[message] {
+                warned[message] = true
+                result.Warnings = append(result.Warnings, message)
+            }
+        }
+        
+        document := &pdfBuilder{fonts: true}
+        var painter *vectorPainter
+        cells := 0
+        newPage := func() {
+            if painter != nil {
+                document.pages = append(document.pages, pdfPage{pageSize[0], pageSize[1], painter.b.String()})
+            }
+            painter = document.painter(warn)
+            fmt.Fprintf(&painter.b, "BT /F2 14 Tf 0 g %s %s Td %s Tj ET\n",
+                formatNumber(margin, 3), formatNumber(pageSize[1]-margin-14, 3),
+                pdfString(fmt.Sprintf("%s (%d)", collection, len(document.pages)+1)))
+        }
+        
+        for _, entry := range byCollection[collection] {
+            for _, col := range colors {
+                svg := e.prepareSvgBuffer(entry.Icon, entry.Icon.Width, entry.Icon.Height, col, "")
+                shapes, box, err := flattenSvg(svg, col, func(message string) {
+                    warn(entry.Name + ": " + message)
+                })
+                if err != nil {
+                    warn(entry.Name + ": " + err.Error())
+                    continue
+                }
+                
+                if cells%(columns*rows) == 0 {
+                    newPage()
+                }
+                column, row := cells%columns, (cells/columns)%rows
+                x := margin + float64(column)*cellWidth
+                top := pageSize[1] - margin - header - float64(row)*cellHeight
+                painter.drawIcon(shapes, box, x, top-cfg.IconSize, cfg.IconSize, cfg.IconSize)
+                
+                label := entry.Name
+                if len(colors) > 1 {
+                    label += " " + col
+                }
+                if runes := []rune(label); len(runes) > maxLabel {
+                    label = string(runes[:maxLabel-3]) + "..."
+                }
+                fmt.Fprintf(&painter.b, "BT /F1 %s Tf 0.3 g %s %s Td %s Tj ET\n", formatNumber(labelSize, 3),
+                    formatNumber(x, 3), formatNumber(top-cfg.IconSize-labelSize*1.8, 3), pdfString(label))
+                cells++
+            }
+        }
+        if painter == nil {
+            continue
+        }
+        document.pages = append(document.pages, pdfPage{pageSize[0], pageSize[1], painter.b.String()})
+        
+        err := writeFile(filePath, func(w io.Writer) error {
+            data, err := document.encode(collection)
+            if err != nil {
+                return err
+            }
+            _, err = w.Write(data)
+            return err
+        })
+        if err != nil {
+            fmt.Printf("❌ Error al guardar %s: %v\n", filePath, err)
+            result.Error = err.Error()
+        } else {
+            fmt.Printf("✅ Exportado: %s (%d iconos, %d páginas)\n", filePath, cells, len(document.pages))
+            printWarnings(result)
+        }
+        results = append(results, result)
+    }
+    return results
+}
@@ -0,0 +1,226 @@
+// iconexporter/pdf_test.go
+package iconexporter
+
+import (
+    "bytes"
+    "compress/zlib"
+    "io"
+    "os"
+    "reflect"
+    "regexp"
+    "strconv"
+    "strings"
+    "testing"
+)
+
+var (
+    pdfStartXref = regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`)
+    pdfStream    = regexp.MustCompile(`(?s)<< /Length (\d+) /Filter /FlateDecode >>\nstream\n`)
+)
+
+// readPdf comprueba que la tabla xref apunta a cada objeto y devuelve los
+// flujos de contenido descomprimidos
+func readPdf(t *testing.T, data []byte) []string {
+    t.Helper()
+    if !bytes.HasPrefix(data, []byte("%PDF-1.4\n")) {
+        t.Fatalf("cabecera no válida: %q", data[:min(len(data), 16)])
+    }
+    m := pdfStartXref.FindSubmatch(data)
+    if m == nil {
+        t.Fatal("falta startxref")
+    }
+    xref, _ := strconv.Atoi(string(m[1]))
+    if !bytes.HasPrefix(data[xref:], []byte("xref\n0 ")) {
+        t.Fatalf("startxref %d no apunta a la tabla xref", xref)
+    }
+    lines := strings.Split(string(data[xref:]), "\n")
+    count, _ := strconv.Atoi(strings.Fields(lines[1])[1])
+    for i := 1; i < count; i++ {
+        offset, _ := strconv.Atoi(lines[2+i][:10])
+        if !bytes.HasPrefix(data[offset:], []byte(strconv.Itoa(i)+" 0 obj\n")) {
+            t.Fatalf("la entrada xref del objeto %d apunta a %d", i, offset)
+        }
+    }
+    
+    var streams []string
+    for _, loc := range pdfStream.FindAllSubmatchIndex(data, -1) {
+        length, _ := strconv.Atoi(string(data[loc[2]:loc[3]]))
+        compressed := data[loc[1] : loc[1]+length]
+        if !bytes.HasPrefix(data[loc[1]+length:], []byte("\nendstream")) {
+            t.Fatal("/Length no coincide con el flujo")
+        }
+        r, err := zlib.NewReader(bytes.NewReader(compressed))
+        if err != nil {
+            t.Fatal(err)
+        }
+        content, err := io.ReadAll(r)
+        if err != nil {
+            t.Fatal(err)
+        }
+        streams = append(streams, string(content))
+    }
+    return streams
+}
+
+func TestPdfString(t *testing.T) {
+    tests := map[string]string{
+        "nonicons:bell": "(nonicons:bell)",
+        "a(b)c\\":       `(a\(b\)c\\)`,
+        "camión":        `(cami\363n)`,
+        "日本\n":          "(???)",
+    }
+    for s, want := range tests {
+        if got := pdfString(s); got != want {
+            t.Errorf("pdfString(%q) = %q, se esperaba %q", s, got, want)
+        }
+    }
+}
+
+func TestVectorPageSize(t *testing.T) {
+    tests := []struct {
+        options       map[string]interface{}
+        width, height float64
+    }{
+        {nil, 48, 24},
+        {map[string]interface{}{"dpi": 144}, 24, 12},
+        {map[string]interface{}{"dpi": 0}, 48, 24},
+        {map[string]interface{}{"dpi": -10}, 48, 24},
+    }
+    for _, tt := range tests {
+        width, height := vectorPageSize(EncodeInput{Width: 48, Height: 24}, tt.options)
+        if width != tt.width || height != tt.height {
+            t.Errorf("%v: %gx%g, se esperaba %gx%g", tt.options, width, height, tt.width, tt.height)
+        }
+    }
+}
+
+func TestPdfEncode(t *testing.T) {
+    tests := []struct {
+        name     string
+        body     string
+        options  map[string]interface{}
+        contains []string
+        objects  []string
+        warnings []string
+    }{
+        {
+            name:     "relleno",
+            body:     `<path fill="#ff0000" d="M2 2h4v4z"/>`,
+            contains: []string{"q\n1 0 0 -1 0 24 cm\n", "2 2 m\n6 2 l\n6 6 l\nh\n1 0 0 rg\nf\nQ\n"},
+            objects:  []string{"/MediaBox [0 0 24 24]", "/Title (prueba:icono)"},
+        },
+        {
+            name:     "escala según dpi",
+            body:     `<path d="M2 2h4v4z"/>`,
+            options:  map[string]interface{}{"dpi": 144},
+            contains: []string{"0.5 0 0 -0.5 0 12 cm\n"},
+            objects:  []string{"/MediaBox [0 0 12 12]"},
+        },
+        {
+            name:     "evenodd con trazo",
+            body:     `<path fill="#00ff00" fill-rule="evenodd" stroke="#0000ff" stroke-width="2" stroke-linecap="round" stroke-linejoin="bevel" d="M0 0h10v10H0z M2 2h4v4H2z"/>`,
+            contains: []string{"2 w\n1 J 2 j\n4 M\n", "0 1 0 rg\n0 0 1 RG\nB*\n"},
+        },
+        {
+            name:     "solo trazo discontinuo",
+            body:     `<path fill="none" stroke="red" stroke-dasharray="3 1 2" stroke-dashoffset="1" d="M2 2L6 6"/>`,
+            contains: []string{"[3 1 2 3 1 2] 1 d\n", "1 0 0 RG\nS\n"},
+        },
+        {
+            name:     "extremo no válido",
+            body:     `<path fill="none" stroke="red" stroke-linecap="flat" d="M2 2L6 6"/>`,
+            contains: []string{"0 J 0 j\n"},
+            warnings: []string{"stroke-linecap no válido: flat, se usa butt"},
+        },
+        {
+            name:     "transparencia con ExtGState",
+            body:     `<path fill="red" fill-opacity="0.5" d="M2 2h4v4z"/>`,
+            contains: []string{"/GS0 gs\n"},
+            objects:  []string{"<< /Type /ExtGState /ca 0.5 /CA 1 >>", "/ExtGState << /GS0 "},
+        },
+        {
+            name:     "cuadráticas elevadas a cúbicas",
+            body:     `<path d="M0 0Q3 3 6 0z"/>`,
+            contains: []string{"0 0 m\n2 2 4 2 6 0 c\n"},
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got, warnings := encodeWith(t, pdfFormat{}, testSvg(tt.body), "", tt.options)
+            streams := readPdf(t, []byte(got))
+            if len(streams) != 1 {
+                t.Fatalf("%d flujos, se esperaba 1", len(streams))
+            }
+            for _, want := range tt.contains {
+                if !strings.Contains(streams[0], want) {
+                    t.Errorf("falta %q en:\n%s", want, streams[0])
+                }
+            }
+            for _, want := range tt.objects {
+                if !strings.Contains(got, want) {
+                    t.Errorf("falta %q en:\n%s", want, got)
+                }
+            }
+            if !reflect.DeepEqual(warnings, tt.warnings) {
+                t.Errorf("avisos %q, se esperaban %q", warnings, tt.warnings)
+            }
+        })
+    }
+}
+
+// Con iconos grandes cada icono y color ocupa su propia página
+func TestPdfCatalog(t *testing.T) {
+    e := newTestExporter(t, Config{PdfCatalog: PdfCatalogConfig{Enabled: true, IconSize: 400, PageSize: "letter"}})
+    entries, _ := e.collectIconEntries()
+    results := e.exportPdfCatalogs(entries, []string{"red", "#00ff00"})
+    if len(results) != 2 {
+        t.Fatalf("%d catálogos, se esperaban 2", len(results))
+    }
+    
+    tests := []struct {
+        collection string
+        labels     []string
+    }{
+        {"nonicons", []string{"(nonicons \\(1\\))", "(nonicons \\(2\\))", "(bell red)", "(bell #00ff00)"}},
+        {"devicon", []string{"(devicon \\(1\\))", "(angular red)"}},
+    }
+    for i, tt := range tests {
+        t.Run(tt.collection, func(t *testing.T) {
+            result := results[i]
+            if result.Collection != tt.collection || result.Error != "" || !strings.HasSuffix(result.Path, tt.collection+"-catalog.pdf") {
+                t.Fatalf("resultado inesperado: %+v", result)
+            }
+            data, err := os.ReadFile(result.Path)
+            if err != nil {
+                t.Fatal(err)
+            }
+            streams := readPdf(t, data)
+            if len(streams) != 2 || !bytes.Contains(data, []byte("/Type /Pages /Kids [")) || !bytes.Contains(data, []byte("/Count 2")) {
+                t.Fatalf("%d páginas, se esperaban 2", len(streams))
+            }
+            if !bytes.Contains(data, []byte("/MediaBox [0 0 612 792]")) || !bytes.Contains(data, []byte("/BaseFont /Helvetica-Bold")) {
+                t.Fatal("faltan el tamaño de página o las fuentes")
+            }
+            content := strings.Join(streams, "")
+            for _, label := range tt.labels {
+                if !strings.Contains(content, label+" Tj") {
+                    t.Errorf("falta la etiqueta %s en:\n%s", label, content)
+                }
+            }
+        })
+    }
+}
+
+func TestPdfCatalogConfigErrors(t *testing.T) {
+    for name, cfg := range map[string]PdfCatalogConfig{
+        "pageSize": {PageSize: "a3"},
+        "pequeño":  {IconSize: 4},
+        "grande":   {IconSize: 500},
+    } {
+        t.Run(name, func(t *testing.T) {
+            if _, err := NewIconExporter(Config{Collections: []string{"nonicons"}, PdfCatalog: cfg}); err == nil {
+                t.Fatal("se esperaba un error")
+            }
+        })
+    }
+}
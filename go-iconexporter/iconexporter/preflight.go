@@ -0,0 +1,179 @@
+// iconexporter/preflight.go
+package iconexporter
+
+import (
+    "fmt"
+    "sort"
+    "strings"
+)
+
+// PreflightConfig controla el análisis previo de cada icono antes de
+// rasterizarlo. oksvg ignora o dibuja mal algunas características de SVG; el
+// análisis las detecta y, según Mode, las notifica en los resultados raster:
+//   - warn (por defecto): se exporta y se añaden avisos
+//   - fail: no se exporta y el resultado queda con error
+//   - skip: no se exporta y el resultado se marca como omitido
+//   - off: sin análisis
+type PreflightConfig struct {
+    Mode string `json:"mode"`
+}
+
+// Elementos que oksvg no dibuja
+var unsupportedSvgElements = map[string]string{
+    "mask":          "las máscaras se ignoran",
+    "clipPath":      "los recortes se ignoran",
+    "filter":        "los filtros se ignoran",
+    "pattern":       "los patrones se ignoran",
+    "marker":        "los marcadores se ignoran",
+    "image":         "las imágenes incrustadas se ignoran",
+    "text":          "el texto no se dibuja",
+    "textPath":      "el texto no se dibuja",
+    "foreignObject": "el contenido externo no se dibuja",
+    "symbol":        "los símbolos no se dibujan",
+}
+
+// Propiedades que oksvg no aplica
+var unsupportedSvgProperties = map[string]string{
+    "mask":           "la máscara se ignora",
+    "clip-path":      "el recorte se ignora",
+    "filter":         "el filtro se ignora",
+    "mix-blend-mode": "el modo de fusión se ignora",
+    "paint-order":    "el orden de pintado se ignora",
+    "vector-effect":  "vector-effect se ignora",
+}
+
+// preflightConfig completa la configuración con valores por defecto y la valida
+func (e *IconExporter) preflightConfig() (PreflightConfig, error) {
+    cfg := e.config.Preflight
+    if cfg.Mode == "" {
+        cfg.Mode = "warn"
+    }
+    switch cfg.Mode {
+    case "warn", "fail", "skip", "off":
+    default:
+        return cfg, fmt.Errorf("modo de preflight no válido: %s (admitidos: warn, fail, skip, off)", cfg.Mode)
+    }
+    return cfg, nil
+}
+
+// preflightSvg devuelve, ordenadas y sin repetir, las características del SVG
+// que el rasterizador no dibuja correctamente
+func preflightSvg(data []byte) []string {
+    root, err := parseSvgDocument(data)
+    if err != nil {
+        return []string{err.Error()}
+    }
+    
+    // Identificadores de patrones, para detectar fill/stroke que los usan
+    patterns := map[string]bool{}
+    root.walk(func(n *svgNode) bool {
+        if n.Kind == svgElementNode && n.Name == "pattern" {
+            patterns[n.attrValue("id")] = true
+        }
+        return true
+    })
+    
+    found := map[string]bool{}
+    root.walk(func(n *svgNode) bool {
+        if n.Kind != svgElementNode {
+            return false
+        }
+        if reason, ok := unsupportedSvgElements[n.Name]; ok {
+            found[fmt.Sprintf("<%s>: %s", n.Name, reason)] = true
+            return false
+        }
+        
+        switch n.Name {
+        case "style":
+            found["<style>: las hojas de estilo se ignoran"] = true
+        case "use":
+            found["<use>: solo se aplican x e y; transform, width, height y los estilos propios se ignoran"] = true
+        case "linearGradient", "radialGradient":
+            if _, ok := n.attr("xlink:href"); ok {
+                found[fmt.Sprintf("<%s> con xlink:href: la herencia de degradados no está soportada", n.Name)] = true
+            } else if _, ok := n.attr("href"); ok {
+                found[fmt.Sprintf("<%s> con href: la herencia de degradados no está soportada", n.Name)] = true
+            }
+            // Las coordenadas en porcentaje solo se interpretan en objectBoundingBox
+            if n.attrValue("gradientUnits") == "userSpaceOnUse" {
+                for _, name := range []string{"x1", "y1", "x2", "y2", "cx", "cy", "r", "fx", "fy"} {
+                    if strings.HasSuffix(n.attrValue(name), "%") {
+                        found[fmt.Sprintf("<%s> con userSpaceOnUse y coordenadas en porcentaje", n.Name)] = true
+                    }
+                }
+            }
+        }
+        
+        if _, ok := n.attr("class"); ok {
+            found[fmt.Sprintf("class en <%s>: las reglas CSS se ignoran", n.Name)] = true
+        }
+        for name, reason := range unsupportedSvgProperties {
+            if value, ok := n.property(name); ok && value != "none" && value != "normal" {
+                found[fmt.Sprintf("%s en <%s>: %s", name, n.Name, reason)] = true
+            }
+        }
+        if display, _ := n.property("display"); display == "none" {
+            found[fmt.Sprintf("display:none en <%s>: el elemento se dibuja igualmente", n.Name)] = true
+        }
+        if visibility, _ := n.property("visibility"); visibility == "hidden" || visibility == "collapse" {
+            found[fmt.Sprintf("visibility:%s en <%s>: el elemento se dibuja igualmente", visibility, n.Name)] = true
+        }
+        for _, name := range []string{"fill", "stroke"} {
+            value, _ := n.property(name)
+            if id, ok := strings.CutPrefix(value, "url(#"); ok && patterns[strings.TrimSuffix(id, ")")] {
+                found[fmt.Sprintf("%s con patrón en <%s>: los patrones se ignoran", name, n.Name)] = true
+            }
+        }
+        return true
+    })
+    
+    issues := make([]string, 0, len(found))
+    for issue := range found {
+        issues = append(issues, issue)
+    }
+    sort.Strings(issues)
+    return issues
+}
+
+// applyPreflight aplica el resultado del análisis a un resultado raster según
+// el modo; devuelve false si el archivo no debe exportarse
+func applyPreflight(mode string, issues []string, result *ExportResult) bool {
+    if len(issues) == 0 || mode == "off" {
+        return true
+    }
+    switch mode {
+    case "fail":
+        result.Error = "el rasterizador no soporta: " + strings.Join(issues, "; ")
+        return false
+    case "skip":
+        result.Skipped = true
+        result.Warnings = append(result.Warnings, issues...)
+        return false
+    default:
+        result.Warnings = append(result.Warnings, issues...)
+        return true
+    }
+}
+
+// rasterPreflight prepara el análisis de un SVG para los archivos raster que
+// se generan a partir de él. El análisis se hace una sola vez, al primer uso;
+// la función devuelta aplica el modo al resultado, informa si el archivo se
+// omite o falla y devuelve false en ese caso.
+func (e *IconExporter) rasterPreflight(svg []byte) func(result *ExportResult) bool {
+    cfg, _ := e.preflightConfig() // validada en validateConfig
+    var issues []string
+    analyzed := false
+    return func(result *ExportResult) bool {
+        if cfg.Mode == "off" {
+            return true
+        }
+        if !analyzed {
+            issues, analyzed = preflightSvg(svg), true
+        }
+        if applyPreflight(cfg.Mode, issues, result) {
+            return true
+        }
+        reportSkippedOrFailed(*result)
+        return false
+    }
+}
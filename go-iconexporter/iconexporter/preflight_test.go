@@ -0,0 +1,215 @@
+// iconexporter/preflight_test.go
+package iconexporter
+
+import (
+    "os"
+    "path/filepath"
+    "reflect"
+    "strings"
+    "testing"
+)
+
+func TestPreflightSvg(t *testing.T) {
+    tests := []struct {
+        name string
+        body string
+        want []string
+    }{
+        {"sin problemas", `<path d="M0 0h24v24z" fill="red"/>`, []string{}},
+        {"máscara", `<mask id="m"/><path d="M0 0h24v24z" mask="url(#m)"/>`, []string{
+            "<mask>: las máscaras se ignoran",
+            "mask en <path>: la máscara se ignora",
+        }},
+        {"estilo", `<style>.a{fill:red}</style><path class="a" d="M0 0h24v24z"/>`, []string{
+            "<style>: las hojas de estilo se ignoran",
+            "class en <path>: las reglas CSS se ignoran",
+        }},
+        {"oculto", `<g style="display:none"><path d="M0 0h1v1z"/></g>`, []string{
+            "display:none en <g>: el elemento se dibuja igualmente",
+        }},
+        {"patrón", `<pattern id="p"/><path d="M0 0h1v1z" fill="url(#p)"/>`, []string{
+            "<pattern>: los patrones se ignoran",
+            "fill con patrón en <path>: los patrones se ignoran",
+        }},
+        {"degradado heredado", `<linearGradient id="a" xlink:href="#b"/>`, []string{
+            "<linearGradient> con xlink:href: la herencia de degradados no está soportada",
+        }},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := preflightSvg(testSvg(tt.body)); !reflect.DeepEqual(got, tt.want) {
+                t.Fatalf("%q, se esperaba %q", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestApplyPreflight(t *testing.T) {
+    issues := []string{"<mask>: las máscaras se ignoran"}
+    tests := []struct {
+        mode     string
+        ok       bool
+        skipped  bool
+        failed   bool
+        warnings int
+    }{
+        {"warn", true, false, false, 1},
+        {"fail", false, false, true, 0},
+        {"skip", false, true, false, 1},
+        {"off", true, false, false, 0},
+    }
+    for _, tt := range tests {
+        t.Run(tt.mode, func(t *testing.T) {
+            var result ExportResult
+            if ok := applyPreflight(tt.mode, issues, &result); ok != tt.ok {
+                t.Fatalf("devuelve %v", ok)
+            }
+            if result.Skipped != tt.skipped || (result.Error != "") != tt.failed || len(result.Warnings) != tt.warnings {
+                t.Fatalf("resultado inesperado: %+v", result)
+            }
+        })
+    }
+    var result ExportResult
+    if !applyPreflight("fail", nil, &result) || result.Error != "" {
+        t.Fatalf("sin problemas no debe fallar: %+v", result)
+    }
+}
+
+// preflightIcon usa clases CSS, que oksvg no aplica
+var preflightIcon = Icon{
+    Body:    `<style>.a{fill:red}</style><path class="a" d="M2 2h20v20H2z"/>`,
+    Width:   24,
+    Height:  24,
+    ViewBox: "0 0 24 24",
+}
+
+// checkPreflightResults comprueba que los resultados raster siguen el modo y
+// que el resto se exporta igualmente
+func checkPreflightResults(t *testing.T, mode string, results []ExportResult, raster func(ExportResult) bool) {
+    t.Helper()
+    rasterCount := 0
+    for _, result := range results {
+        _, statErr := os.Stat(result.Path)
+        if !raster(result) {
+            if result.Skipped || result.Error != "" || statErr != nil {
+                t.Errorf("%s: no debe verse afectado: %+v", result.Path, result)
+            }
+            continue
+        }
+        rasterCount++
+        switch mode {
+        case "warn":
+            if result.Error != "" || len(result.Warnings) == 0 || statErr != nil {
+                t.Errorf("%s: se esperaba exportado con avisos: %+v", result.Path, result)
+            }
+        case "fail":
+            if !strings.Contains(result.Error, "<style>") || statErr == nil {
+                t.Errorf("%s: se esperaba un error sin archivo: %+v", result.Path, result)
+            }
+        case "skip":
+            if !result.Skipped || statErr == nil {
+                t.Errorf("%s: se esperaba omitido sin archivo: %+v", result.Path, result)
+            }
+        }
+    }
+    if rasterCount == 0 {
+        t.Fatal("no hay resultados raster")
+    }
+}
+
+// Todas las rutas que rasterizan aplican el análisis previo
+func TestPreflightRasterPaths(t *testing.T) {
+    data := IconData{Icons: map[string]Icon{"estilo": preflightIcon}}
+    for _, mode := range []string{"warn", "fail", "skip"} {
+        t.Run(mode, func(t *testing.T) {
+            t.Run("variante", func(t *testing.T) {
+                e := newTestExporter(t, Config{OutputFormats: []string{"png", "svg"}, Preflight: PreflightConfig{Mode: mode}})
+                results, err := e.processVariant(data, "x", "estilo", map[string]interface{}{"width": 24, "height": 24, "color": "red"})
+                if err != nil {
+                    t.Fatal(err)
+                }
+                checkPreflightResults(t, mode, results, func(r ExportResult) bool { return r.Format == "png" })
+            })
+            t.Run("webapp", func(t *testing.T) {
+                e := newTestExporter(t, Config{Preflight: PreflightConfig{Mode: mode}})
+                results, err := e.processWebApp(data, "x", "estilo", "red", false)
+                if err != nil {
+                    t.Fatal(err)
+                }
+                checkPreflightResults(t, mode, results, func(r ExportResult) bool { return r.Format == "png" || r.Format == "ico" })
+            })
+            t.Run("xcassets", func(t *testing.T) {
+                e := newTestExporter(t, Config{Preflight: PreflightConfig{Mode: mode}})
+                cfg, err := e.xcassetsConfig()
+                if err != nil {
+                    t.Fatal(err)
+                }
+                cfg.Format = "png"
+                results, err := e.processImageset(data, "x", "estilo", [2]int{24, 24}, "red", "estilo", e.config.OutputDir, cfg)
+                if err != nil {
+                    t.Fatal(err)
+                }
+                checkPreflightResults(t, mode, results, func(r ExportResult) bool { return true })
+            })
+            t.Run("atlas", func(t *testing.T) {
+                e := newTestExporter(t, Config{Preflight: PreflightConfig{Mode: mode}})
+                entries := []iconEntry{{"x", "estilo", preflightIcon}}
+                results := e.exportAtlases(entries, [][2]int{{24, 24}}, []string{"red"})
+                checkPreflightResults(t, mode, results, func(r ExportResult) bool { return r.Format == "atlas" })
+            })
+        })
+    }
+}
+
+// Los iconos de ejemplo no tienen problemas: el modo skip no omite nada
+func TestPreflightAndroidClean(t *testing.T) {
+    e := newTestExporter(t, Config{Preflight: PreflightConfig{Mode: "skip"}})
+    summary, err := e.ExportAndroid("nonicons", "bell", 48, "")
+    if err != nil {
+        t.Fatal(err)
+    }
+    if summary.Skipped != 0 || summary.Errors != 0 || summary.Processed == 0 {
+        t.Fatalf("resumen inesperado: %+v", summary)
+    }
+    if _, err := os.Stat(filepath.Join(e.config.OutputDir, "android", "res", "mipmap-mdpi", "ic_launcher.png")); err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestPreflightConfigErrors(t *testing.T) {
+    if _, err := NewIconExporter(Config{Collections: []string{"nonicons"}, Preflight: PreflightConfig{Mode: "estricto"}}); err == nil {
+        t.Fatal("se esperaba un error")
+    }
+}
+
+// En el atlas solo se omiten los iconos con problemas: la hoja, su JSON y el
+// CSS se escriben con el resto y no mencionan los omitidos
+func TestPreflightAtlasSkipIcon(t *testing.T) {
+    e := newTestExporter(t, Config{Preflight: PreflightConfig{Mode: "skip"}})
+    clean := Icon{Body: `<path d="M2 2h20v20H2z"/>`, Width: 24, Height: 24, ViewBox: "0 0 24 24"}
+    entries := []iconEntry{{"x", "estilo", preflightIcon}, {"x", "limpio", clean}}
+    results := e.exportAtlases(entries, [][2]int{{24, 24}}, []string{"red"})
+    skipped := 0
+    for _, result := range results {
+        if result.Skipped {
+            skipped++
+            if result.Icon != "estilo" {
+                t.Errorf("omitido inesperado: %+v", result)
+            }
+            continue
+        }
+        if result.Error != "" {
+            t.Fatalf("%s: %s", result.Path, result.Error)
+        }
+        data, err := os.ReadFile(result.Path)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if result.Format != "atlas" && (strings.Contains(string(data), "estilo") || !strings.Contains(string(data), "limpio")) {
+            t.Errorf("%s no coincide con la hoja:\n%s", result.Path, data)
+        }
+    }
+    if skipped != 1 {
+        t.Fatalf("%d omitidos, se esperaba 1: %+v", skipped, results)
+    }
+}
@@ -0,0 +1,244 @@
+// iconexporter/quantize.go
+package iconexporter
+
+import (
+    "fmt"
+    "image"
+    "image/color"
+    "sort"
+
+    "golang.org/x/image/draw"
+)
+
+// quantizeOptions controla la reducción de la imagen a una paleta. Se lee de
+// las opciones del formato con parseQuantizeOptions:
+//   - "colors": tamaño máximo de la paleta (2-256), incluida la transparencia
+//   - "palette": lista de colores fijos, p. ej. solo los de la marca
+//   - "dither": true o "floyd-steinberg" para difusión de error
+//   - "alphaThreshold": 0-255; por debajo el píxel es transparente y por encima
+//     opaco. Sin él se conserva el alfa parcial (solo PNG).
+type quantizeOptions struct {
+    Colors         int
+    Palette        color.Palette
+    Dither         bool
+    AlphaThreshold int // -1 conserva el alfa parcial
+}
+
+// ditherOption lee "dither" como booleano (Floyd–Steinberg) o por nombre
+func ditherOption(options map[string]interface{}) string {
+    switch v := options["dither"].(type) {
+    case bool:
+        if v {
+            return "floyd-steinberg"
+        }
+    case string:
+        if v != "" {
+            return v
+        }
+    }
+    return "none"
+}
+
+// parseQuantizeOptions lee y valida las opciones de cuantización. binaryAlpha
+// fuerza la transparencia binaria (GIF, paletas fijas) con umbral 128 por defecto.
+func parseQuantizeOptions(options map[string]interface{}, defaultColors int, binaryAlpha bool) (quantizeOptions, error) {
+    q := quantizeOptions{
+        Colors:         optionInt(options, "colors", defaultColors),
+        AlphaThreshold: optionInt(options, "alphaThreshold", -1),
+    }
+    if q.Colors < 2 || q.Colors > 256 {
+        return q, fmt.Errorf("número de colores fuera de rango (2-256): %d", q.Colors)
+    }
+    
+    switch dither := ditherOption(options); dither {
+    case "none":
+    case "floyd-steinberg":
+        q.Dither = true
+    default:
+        return q, fmt.Errorf("dither no válido para una paleta: %s (admitidos: none, floyd-steinberg)", dither)
+    }
+    
+    var values []string
+    switch v := options["palette"].(type) {
+    case []string:
+        values = v
+    case []interface{}:
+        for _, item := range v {
+            text, _ := item.(string)
+            values = append(values, text)
+        }
+    }
+    if values != nil {
+        for _, value := range values {
+            c, err := parseColor(value)
+            if err != nil {
+                return q, err
+            }
+            r, g, b, _ := c.RGBA()
+            q.Palette = append(q.Palette, color.RGBA{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8), 0xff})
+        }
+        // Se reserva una entrada para la transparencia
+        if len(q.Palette) == 0 || len(q.Palette) > 255 {
+            return q, fmt.Errorf("la paleta fija debe tener entre 1 y 255 colores: %d", len(q.Palette))
+        }
+        binaryAlpha = true
+    }
+    if binaryAlpha && q.AlphaThreshold < 0 {
+        q.AlphaThreshold = 128
+    }
+    if q.AlphaThreshold > 255 {
+        return q, fmt.Errorf("alphaThreshold fuera de rango (0-255): %d", q.AlphaThreshold)
+    }
+    return q, nil
+}
+
+// quantizeImage reduce la imagen a una paleta calculada por corte de la mediana
+// (o a la paleta fija). La transparencia, si la hay, ocupa el índice 0.
+func quantizeImage(img image.Image, q quantizeOptions) *image.Paletted {
+    bounds := img.Bounds()
+    src := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+    draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Src)
+    
+    // Con umbral, cada píxel queda totalmente transparente u opaco
+    transparent := false
+    for i := 0; i < len(src.Pix); i += 4 {
+        a := src.Pix[i+3]
+        if q.AlphaThreshold >= 0 && a > 0 {
+            if int(a) < q.AlphaThreshold {
+                a = 0
+            } else {
+                for c := 0; c < 3; c++ {
+                    src.Pix[i+c] = uint8(uint32(src.Pix[i+c]) * 255 / uint32(a))
+                }
+                a = 0xff
+            }
+            src.Pix[i+3] = a
+        }
+        if a == 0 {
+            copy(src.Pix[i:i+4], []byte{0, 0, 0, 0})
+            transparent = true
+        }
+    }
+    
+    var palette color.Palette
+    if transparent {
+        palette = append(palette, color.RGBA{})
+    }
+    if q.Palette != nil {
+        palette = append(palette, q.Palette...)
+    } else {
+        palette = append(palette, medianCut(src.Pix, q.Colors-len(palette))...)
+    }
+    
+    paletted := image.NewPaletted(src.Bounds(), palette)
+    if q.Dither {
+        draw.FloydSteinberg.Draw(paletted, paletted.Bounds(), src, image.Pt(0, 0))
+    } else {
+        draw.Draw(paletted, paletted.Bounds(), src, image.Pt(0, 0), draw.Src)
+    }
+    return paletted
+}
+
+// colorCount es un color (RGBA premultiplicado) con su número de píxeles
+type colorCount struct {
+    c [4]uint8
+    n int
+}
+
+// medianCut calcula una paleta de hasta n colores para los píxeles no
+// transparentes: divide repetidamente la caja con mayor rango por la mediana
+// ponderada de su canal más amplio y promedia cada caja
+func medianCut(pix []byte, n int) color.Palette {
+    counts := map[[4]uint8]int{}
+    for i := 0; i < len(pix); i += 4 {
+        if pix[i+3] > 0 {
+            counts[[4]uint8{pix[i], pix[i+1], pix[i+2], pix[i+3]}]++
+        }
+    }
+    colors := make([]colorCount, 0, len(counts))
+    for c, count := range counts {
+        colors = append(colors, colorCount{c, count})
+    }
+    // Orden estable para que la paleta no dependa del recorrido del mapa
+    sort.Slice(colors, func(i, j int) bool {
+        a, b := colors[i].c, colors[j].c
+        return uint32(a[0])<<24|uint32(a[1])<<16|uint32(a[2])<<8|uint32(a[3]) <
+            uint32(b[0])<<24|uint32(b[1])<<16|uint32(b[2])<<8|uint32(b[3])
+    })
+    
+    // Canal con más rango de una caja y su amplitud
+    widest := func(box []colorCount) (int, int) {
+        channel, width := 0, -1
+        for ch := 0; ch < 4; ch++ {
+            lo, hi := 255, 0
+            for _, cc := range box {
+                lo, hi = min(lo, int(cc.c[ch])), max(hi, int(cc.c[ch]))
+            }
+            if hi-lo > width {
+                channel, width = ch, hi-lo
+            }
+        }
+        return channel, width
+    }
+    
+    boxes := [][]colorCount{colors}
+    for len(boxes) < n {
+        best, bestWidth := -1, 0
+        for i, box := range boxes {
+            if _, width := widest(box); len(box) > 1 && width > bestWidth {
+                best, bestWidth = i, width
+            }
+        }
+        if best < 0 {
+            break
+        }
+        box := boxes[best]
+        channel, _ := widest(box)
+        sort.SliceStable(box, func(i, j int) bool { return box[i].c[channel] < box[j].c[channel] })
+        
+        total := 0
+        for _, cc := range box {
+            total += cc.n
+        }
+        split, acc := 1, 0
+        for i, cc := range box[:len(box)-1] {
+            acc += cc.n
+            if acc*2 >= total {
+                split = i + 1
+                break
+            }
+        }
+        boxes[best] = box[:split]
+        boxes = append(boxes, box[split:])
+    }
+    
+    palette := make(color.Palette, 0, len(boxes))
+    for _, box := range boxes {
+        if len(box) == 0 {
+            continue
+        }
+        var sum [4]int
+        total := 0
+        for _, cc := range box {
+            for ch := 0; ch < 4; ch++ {
+                sum[ch] += int(cc.c[ch]) * cc.n
+            }
+            total += cc.n
+        }
+        palette = append(palette, color.RGBA{
+            uint8((sum[0] + total/2) / total), uint8((sum[1] + total/2) / total),
+            uint8((sum[2] + total/2) / total), uint8((sum[3] + total/2) / total),
+        })
+    }
+    return palette
+}
+
+// indexBits devuelve los bits por índice (1, 2, 4 u 8) para una paleta
+func indexBits(colors int) int {
+    for _, bits := range []int{1, 2, 4} {
+        if colors <= 1<<bits {
+            return bits
+        }
+    }
+    return 8
+}
@@ -0,0 +1,202 @@
+// iconexporter/quantize_test.go
+package iconexporter
+
+import (
+    "image"
+    "image/color"
+    "reflect"
+    "testing"
+)
+
+func TestParseQuantizeOptions(t *testing.T) {
+    red, blue := color.RGBA{255, 0, 0, 255}, color.RGBA{0, 0, 255, 255}
+    tests := []struct {
+        name        string
+        options     map[string]interface{}
+        binaryAlpha bool
+        want        quantizeOptions
+    }{
+        {"por defecto", nil, false, quantizeOptions{Colors: 256, AlphaThreshold: -1}},
+        {"transparencia binaria", nil, true, quantizeOptions{Colors: 256, AlphaThreshold: 128}},
+        {"dither booleano", map[string]interface{}{"dither": true, "colors": 16}, false, quantizeOptions{Colors: 16, Dither: true, AlphaThreshold: -1}},
+        {"dither por nombre", map[string]interface{}{"dither": "floyd-steinberg"}, false, quantizeOptions{Colors: 256, Dither: true, AlphaThreshold: -1}},
+        {"umbral propio", map[string]interface{}{"alphaThreshold": 10}, true, quantizeOptions{Colors: 256, AlphaThreshold: 10}},
+        {
+            name:    "paleta fija de JSON",
+            options: map[string]interface{}{"palette": []interface{}{"#ff0000", "blue"}},
+            want:    quantizeOptions{Colors: 256, Palette: color.Palette{red, blue}, AlphaThreshold: 128},
+        },
+        {
+            name:    "paleta fija de Go",
+            options: map[string]interface{}{"palette": []string{"red"}, "alphaThreshold": 0},
+            want:    quantizeOptions{Colors: 256, Palette: color.Palette{red}, AlphaThreshold: 0},
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got, err := parseQuantizeOptions(tt.options, 256, tt.binaryAlpha)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if !reflect.DeepEqual(got, tt.want) {
+                t.Fatalf("%+v, se esperaba %+v", got, tt.want)
+            }
+        })
+    }
+    
+    for name, options := range map[string]map[string]interface{}{
+        "pocos colores":   {"colors": 1},
+        "muchos colores":  {"colors": 257},
+        "dither ordered":  {"dither": "ordered"},
+        "paleta vacía":    {"palette": []string{}},
+        "color no válido": {"palette": []string{"#ff00"}},
+        "umbral":          {"alphaThreshold": 256},
+    } {
+        if _, err := parseQuantizeOptions(options, 256, false); err == nil {
+            t.Errorf("%s: se esperaba un error", name)
+        }
+    }
+}
+
+func TestIndexBits(t *testing.T) {
+    tests := map[int]int{1: 1, 2: 1, 3: 2, 4: 2, 5: 4, 16: 4, 17: 8, 256: 8}
+    for colors, want := range tests {
+        if got := indexBits(colors); got != want {
+            t.Errorf("indexBits(%d) = %d, se esperaba %d", colors, got, want)
+        }
+    }
+}
+
+// paletteColors devuelve los colores de la imagen como NRGBA
+func paletteColors(p *image.Paletted) []color.NRGBA {
+    colors := make([]color.NRGBA, len(p.Pix))
+    for i, index := range p.Pix {
+        colors[i] = color.NRGBAModel.Convert(p.Palette[index]).(color.NRGBA)
+    }
+    return colors
+}
+
+func TestQuantizeImage(t *testing.T) {
+    // Cuatro colores, uno transparente y otro semitransparente
+    img := image.NewNRGBA(image.Rect(0, 0, 4, 1))
+    img.SetNRGBA(0, 0, color.NRGBA{255, 0, 0, 255})
+    img.SetNRGBA(1, 0, color.NRGBA{0, 0, 255, 255})
+    img.SetNRGBA(2, 0, color.NRGBA{0, 255, 0, 0})
+    img.SetNRGBA(3, 0, color.NRGBA{255, 255, 0, 200})
+    
+    tests := []struct {
+        name    string
+        q       quantizeOptions
+        palette int
+        want    []color.NRGBA
+    }{
+        {
+            name:    "colores exactos con alfa parcial",
+            q:       quantizeOptions{Colors: 8, AlphaThreshold: -1},
+            palette: 4,
+            want:    []color.NRGBA{{255, 0, 0, 255}, {0, 0, 255, 255}, {}, {255, 255, 0, 200}},
+        },
+        {
+            name:    "umbral alto vuelve transparente el semitransparente",
+            q:       quantizeOptions{Colors: 8, AlphaThreshold: 201},
+            palette: 3,
+            want:    []color.NRGBA{{255, 0, 0, 255}, {0, 0, 255, 255}, {}, {}},
+        },
+        {
+            name:    "umbral bajo lo vuelve opaco sin oscurecerlo",
+            q:       quantizeOptions{Colors: 8, AlphaThreshold: 128},
+            palette: 4,
+            want:    []color.NRGBA{{255, 0, 0, 255}, {0, 0, 255, 255}, {}, {255, 255, 0, 255}},
+        },
+        {
+            name:    "paleta fija al color más cercano",
+            q:       quantizeOptions{Colors: 256, Palette: color.Palette{color.RGBA{200, 0, 0, 255}, color.RGBA{0, 0, 200, 255}}, AlphaThreshold: 128},
+            palette: 3,
+            want:    []color.NRGBA{{200, 0, 0, 255}, {0, 0, 200, 255}, {}, {200, 0, 0, 255}},
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            paletted := quantizeImage(img, tt.q)
+            if len(paletted.Palette) != tt.palette {
+                t.Fatalf("%d colores en la paleta, se esperaban %d", len(paletted.Palette), tt.palette)
+            }
+            // La transparencia ocupa el índice 0
+            if _, _, _, a := paletted.Palette[0].RGBA(); a != 0 {
+                t.Fatalf("el índice 0 es %v, se esperaba transparente", paletted.Palette[0])
+            }
+            if got := paletteColors(paletted); !reflect.DeepEqual(got, tt.want) {
+                t.Fatalf("%v, se esperaba %v", got, tt.want)
+            }
+        })
+    }
+    
+    // Sin píxeles transparentes no se reserva el índice 0
+    opaque := testImage(4, 4, patternSolid)
+    if paletted := quantizeImage(opaque, quantizeOptions{Colors: 4, AlphaThreshold: -1}); len(paletted.Palette) != 1 {
+        t.Fatalf("paleta %v, se esperaba un solo color", paletted.Palette)
+    }
+}
+
+func TestQuantizeImageLimitsColors(t *testing.T) {
+    img := testImage(32, 32, patternNoise)
+    for _, colors := range []int{2, 16, 256} {
+        paletted := quantizeImage(img, quantizeOptions{Colors: colors, AlphaThreshold: -1})
+        if len(paletted.Palette) > colors {
+            t.Errorf("%d colores: la paleta tiene %d", colors, len(paletted.Palette))
+        }
+        // La paleta no depende del orden de recorrido del mapa de colores
+        again := quantizeImage(img, quantizeOptions{Colors: colors, AlphaThreshold: -1})
+        if !reflect.DeepEqual(paletted.Palette, again.Palette) || !reflect.DeepEqual(paletted.Pix, again.Pix) {
+            t.Errorf("%d colores: resultado no determinista", colors)
+        }
+    }
+}
+
+// Con dos grupos de colores la mediana separa los grupos y promedia cada uno
+func TestMedianCut(t *testing.T) {
+    pix := []byte{
+        10, 10, 10, 255, 20, 20, 20, 255, 30, 30, 30, 255,
+        200, 0, 0, 255, 220, 0, 0, 255,
+        0, 0, 0, 0,
+    }
+    got := medianCut(pix, 2)
+    want := color.Palette{color.RGBA{20, 20, 20, 255}, color.RGBA{210, 0, 0, 255}}
+    if !reflect.DeepEqual(got, want) {
+        t.Fatalf("%v, se esperaba %v", got, want)
+    }
+    if got := medianCut(pix, 16); len(got) != 5 {
+        t.Fatalf("%d colores, se esperaban los 5 distintos", len(got))
+    }
+    if got := medianCut([]byte{0, 0, 0, 0}, 4); len(got) != 0 {
+        t.Fatalf("paleta %v para una imagen transparente", got)
+    }
+}
+
+// La difusión de error reproduce un gris medio mezclando blanco y negro
+func TestQuantizeImageDither(t *testing.T) {
+    gray := image.NewNRGBA(image.Rect(0, 0, 16, 16))
+    for i := range gray.Pix {
+        gray.Pix[i] = 128
+        if i%4 == 3 {
+            gray.Pix[i] = 255
+        }
+    }
+    palette := color.Palette{color.RGBA{0, 0, 0, 255}, color.RGBA{255, 255, 255, 255}}
+    white := func(p *image.Paletted) int {
+        n := 0
+        for _, c := range paletteColors(p) {
+            if c.R == 255 {
+                n++
+            }
+        }
+        return n
+    }
+    
+    if n := white(quantizeImage(gray, quantizeOptions{Colors: 2, Palette: palette, AlphaThreshold: 128})); n != 0 && n != 256 {
+        t.Fatalf("sin tramado %d píxeles blancos, se esperaba un solo color", n)
+    }
+    if n := white(quantizeImage(gray, quantizeOptions{Colors: 2, Palette: palette, Dither: true, AlphaThreshold: 128})); n < 118 || n > 138 {
+        t.Fatalf("con tramado %d píxeles blancos, se esperaban unos 128", n)
+    }
+}
@@ -0,0 +1,239 @@
+// iconexporter/raster.go
+package iconexporter
+
+import (
+    "bytes"
+    "encoding/binary"
+    "fmt"
+    "hash/crc32"
+    "image"
+    "image/color"
+    "image/gif"
+    "image/jpeg"
+    "image/png"
+    "io"
+
+    "github.com/disintegration/imaging"
+    "github.com/srwiley/oksvg"
+    "golang.org/x/image/bmp"
+    "golang.org/x/image/draw"
+)
+
+// rasterEncoder codifica una imagen ya rasterizada con sus opciones de formato
+type rasterEncoder func(w io.Writer, img image.Image, options map[string]interface{}) error
+
+func init() {
+    registerRasterFormat("png", encodePNG)
+    registerRasterFormat("jpeg", encodeJPEG)
+    registerRasterFormat("gif", encodeGIF)
+    registerRasterFormat("bmp", encodeBMP)
+}
+
+// registerRasterFormat registra un codificador raster integrado
+func registerRasterFormat(format string, encoder rasterEncoder) {
+    mustRegisterFormat(rasterFormat{name: format, encode: encoder})
+}
+
+// parseColor interpreta un color SVG (nombre, #hex o rgb())
+func parseColor(value string) (color.Color, error) {
+    c, err := oksvg.ParseSVGColor(value)
+    if err != nil || c == nil {
+        return nil, fmt.Errorf("color no válido: %q", value)
+    }
+    return c, nil
+}
+
+// flattenImage compone la imagen sobre un fondo opaco
+func flattenImage(img image.Image, background string) (*image.NRGBA, error) {
+    bg, err := parseColor(background)
+    if err != nil {
+        return nil, err
+    }
+    bounds := img.Bounds()
+    canvas := imaging.New(bounds.Dx(), bounds.Dy(), bg)
+    return imaging.Overlay(canvas, img, image.Pt(0, 0), 1.0), nil
+}
+
+// encodePNG admite "compression": default, none, speed o best, y "dpi" para
+// añadir el chunk pHYs con la resolución. Con "colors" o "palette" escribe un
+// PNG indexado (PNG8) con las opciones de cuantización de quantize.go.
+func encodePNG(w io.Writer, img image.Image, options map[string]interface{}) error {
+    levels := map[string]png.CompressionLevel{
+        "default": png.DefaultCompression,
+        "none":    png.NoCompression,
+        "speed":   png.BestSpeed,
+        "best":    png.BestCompression,
+    }
+    compression, _ := options["compression"].(string)
+    if compression == "" {
+        compression = "default"
+    }
+    level, ok := levels[compression]
+    if !ok {
+        return fmt.Errorf("compresión PNG no válida: %s", compression)
+    }
+    encoder := png.Encoder{CompressionLevel: level}
+    
+    _, indexed := options["colors"]
+    if _, fixed := options["palette"]; indexed || fixed {
+        q, err := parseQuantizeOptions(options, 256, false)
+        if err != nil {
+            return err
+        }
+        img = quantizeImage(img, q)
+    }
+    
+    dpi := optionInt(options, "dpi", 0)
+    if dpi <= 0 {
+        return encoder.Encode(w, img)
+    }
+    var buf bytes.Buffer
+    if err := encoder.Encode(&buf, img); err != nil {
+        return err
+    }
+    return writePNGWithDPI(w, buf.Bytes(), dpi)
+}
+
+// writePNGWithDPI inserta un chunk pHYs tras IHDR (firma de 8 bytes + IHDR de 25)
+func writePNGWithDPI(w io.Writer, data []byte, dpi int) error {
+    const ihdrEnd = 8 + 25
+    if len(data) < ihdrEnd {
+        return fmt.Errorf("PNG no válido")
+    }
+    
+    // pHYs guarda píxeles por metro en ambos ejes; unidad 1 = metro
+    pixelsPerMeter := uint32(float64(dpi)/0.0254 + 0.5)
+    chunk := make([]byte, 0, 21)
+    chunk = binary.BigEndian.AppendUint32(chunk, 9)
+    chunk = append(chunk, "pHYs"...)
+    chunk = binary.BigEndian.AppendUint32(chunk, pixelsPerMeter)
+    chunk = binary.BigEndian.AppendUint32(chunk, pixelsPerMeter)
+    chunk = append(chunk, 1)
+    chunk = binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+    
+    for _, part := range [][]byte{data[:ihdrEnd], chunk, data[ihdrEnd:]} {
+        if _, err := w.Write(part); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// encodeJPEG admite "quality" (1-100) y "background", ya que JPEG no tiene transparencia
+func encodeJPEG(w io.Writer, img image.Image, options map[string]interface{}) error {
+    quality := optionInt(options, "quality", 95)
+    if quality < 1 || quality > 100 {
+        return fmt.Errorf("calidad JPEG fuera de rango (1-100): %d", quality)
+    }
+    background, _ := options["background"].(string)
+    if background == "" {
+        background = "white"
+    }
+    flat, err := flattenImage(img, background)
+    if err != nil {
+        return err
+    }
+    return jpeg.Encode(w, flat, &jpeg.Options{Quality: quality})
+}
+
+// encodeGIF admite las opciones de cuantización (quantize.go): "colors"
+// (2-256), "palette", "dither" y "alphaThreshold" (0-255, por defecto 128) para
+// decidir qué píxeles quedan transparentes
+func encodeGIF(w io.Writer, img image.Image, options map[string]interface{}) error {
+    q, err := parseQuantizeOptions(options, 256, true)
+    if err != nil {
+        return err
+    }
+    paletted := quantizeImage(img, q)
+    return gif.Encode(w, paletted, &gif.Options{NumColors: len(paletted.Palette)})
+}
+
+// encodeBMP admite "bits" (32, 24 o 1), "alpha" (32 bits con transparencia) y
+// "background" cuando no hay canal alfa. Con 1 bit escribe un BMP monocromo con
+// las opciones de umbral y tramado de los formatos embebidos.
+func encodeBMP(w io.Writer, img image.Image, options map[string]interface{}) error {
+    bits := optionInt(options, "bits", 32)
+    switch bits {
+    case 1:
+        return encodeMonoBMP(w, img, options)
+    case 24, 32:
+    default:
+        return fmt.Errorf("profundidad BMP no válida: %d (admitidas: 1, 24, 32)", bits)
+    }
+    if bits == 32 && optionBool(options, "alpha", true) {
+        return bmp.Encode(w, imaging.Clone(img))
+    }
+    background, _ := options["background"].(string)
+    if background == "" {
+        background = "white"
+    }
+    flat, err := flattenImage(img, background)
+    if err != nil {
+        return err
+    }
+    rgba := image.NewRGBA(flat.Bounds())
+    draw.Draw(rgba, rgba.Bounds(), flat, image.Pt(0, 0), draw.Src)
+    return bmp.Encode(w, rgba)
+}
+
+// encodeMonoBMP escribe un BMP de 1 bit con paleta de dos colores: "background"
+// (blanco) para los píxeles apagados y "foreground" (negro) para los encendidos
+func encodeMonoBMP(w io.Writer, img image.Image, options map[string]interface{}) error {
+    bitmap, err := inkLevels(img, options)
+    if err != nil {
+        return err
+    }
+    values, err := quantizeLevels(bitmap, 1, options)
+    if err != nil {
+        return err
+    }
+    palette := make([]byte, 0, 8)
+    for _, key := range []string{"background", "foreground"} {
+        value, _ := options[key].(string)
+        if value == "" {
+            value = map[string]string{"background": "white", "foreground": "black"}[key]
+        }
+        c, err := parseColor(value)
+        if err != nil {
+            return err
+        }
+        r, g, b, _ := c.RGBA()
+        palette = append(palette, uint8(b>>8), uint8(g>>8), uint8(r>>8), 0)
+    }
+    
+    // Las filas van de abajo arriba y se rellenan hasta múltiplos de 4 bytes
+    rows := packBits(values, bitmap.Width, bitmap.Height, 1, true)
+    stride := (bitmap.Width + 7) / 8
+    padded := (stride + 3) &^ 3
+    pixels := make([]byte, padded*bitmap.Height)
+    for y := 0; y < bitmap.Height; y++ {
+        copy(pixels[(bitmap.Height-1-y)*padded:], rows[y*stride:(y+1)*stride])
+    }
+    
+    const headerSize = 14 + 40
+    offset := headerSize + len(palette)
+    header := make([]byte, 0, headerSize)
+    header = append(header, 'B', 'M')
+    header = binary.LittleEndian.AppendUint32(header, uint32(offset+len(pixels)))
+    header = binary.LittleEndian.AppendUint32(header, 0)
+    header = binary.LittleEndian.AppendUint32(header, uint32(offset))
+    header = binary.LittleEndian.AppendUint32(header, 40)
+    header = binary.LittleEndian.AppendUint32(header, uint32(bitmap.Width))
+    header = binary.LittleEndian.AppendUint32(header, uint32(bitmap.Height))
+    header = binary.LittleEndian.AppendUint16(header, 1) // planos
+    header = binary.LittleEndian.AppendUint16(header, 1) // bits por píxel
+    header = binary.LittleEndian.AppendUint32(header, 0) // sin compresión
+    header = binary.LittleEndian.AppendUint32(header, uint32(len(pixels)))
+    pixelsPerMeter := uint32(float64(optionInt(options, "dpi", 72))/0.0254 + 0.5)
+    header = binary.LittleEndian.AppendUint32(header, pixelsPerMeter)
+    header = binary.LittleEndian.AppendUint32(header, pixelsPerMeter)
+    header = binary.LittleEndian.AppendUint32(header, 2) // colores de la paleta
+    header = binary.LittleEndian.AppendUint32(header, 2)
+    
+    for _, part := range [][]byte{header, palette, pixels} {
+        if _, err := w.Write(part); err != nil {
+            return err
+        }
+    }
+    return nil
+}
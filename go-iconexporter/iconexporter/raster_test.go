@@ -0,0 +1,176 @@
+// iconexporter/raster_test.go
+package iconexporter
+
+import (
+    "bytes"
+    "encoding/binary"
+    "image"
+    "image/color"
+    "image/gif"
+    "image/jpeg"
+    "image/png"
+    "testing"
+
+    "golang.org/x/image/bmp"
+)
+
+func TestEncodePNGRoundTrip(t *testing.T) {
+    tests := []struct {
+        name    string
+        options map[string]interface{}
+    }{
+        {"por defecto", nil},
+        {"sin compresión", map[string]interface{}{"compression": "none"}},
+        {"máxima compresión", map[string]interface{}{"compression": "best"}},
+        {"con dpi", map[string]interface{}{"dpi": 144}},
+    }
+    img := testImage(19, 11, patternNoise)
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            var buf bytes.Buffer
+            if err := encodePNG(&buf, img, tt.options); err != nil {
+                t.Fatal(err)
+            }
+            decoded, err := png.Decode(bytes.NewReader(buf.Bytes()))
+            if err != nil {
+                t.Fatal(err)
+            }
+            sameNRGBA(t, img, decoded)
+            
+            // pHYs va justo después de IHDR, con píxeles por metro
+            hasPhys := bytes.Contains(buf.Bytes(), []byte("pHYs"))
+            if wantPhys := tt.options["dpi"] != nil; hasPhys != wantPhys {
+                t.Fatalf("chunk pHYs presente: %v, se esperaba %v", hasPhys, wantPhys)
+            }
+            if hasPhys && !bytes.Contains(buf.Bytes(), []byte{0, 0, 0x16, 0x25, 0, 0, 0x16, 0x25, 1}) {
+                t.Fatal("pHYs no contiene 5669 píxeles por metro (144 DPI)")
+            }
+        })
+    }
+    
+    if err := encodePNG(&bytes.Buffer{}, img, map[string]interface{}{"compression": "zip"}); err == nil {
+        t.Fatal("se esperaba un error con una compresión desconocida")
+    }
+}
+
+func TestEncodeJPEG(t *testing.T) {
+    img := image.NewNRGBA(image.Rect(0, 0, 8, 8)) // transparente
+    tests := []struct {
+        name    string
+        options map[string]interface{}
+        want    color.RGBA
+        wantErr bool
+    }{
+        {"fondo blanco por defecto", nil, color.RGBA{255, 255, 255, 255}, false},
+        {"fondo elegido", map[string]interface{}{"background": "#000000"}, color.RGBA{0, 0, 0, 255}, false},
+        {"calidad cero", map[string]interface{}{"quality": 0}, color.RGBA{}, true},
+        {"calidad mayor de 100", map[string]interface{}{"quality": 101}, color.RGBA{}, true},
+        {"fondo no válido", map[string]interface{}{"background": "nocolor"}, color.RGBA{}, true},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            var buf bytes.Buffer
+            err := encodeJPEG(&buf, img, tt.options)
+            if tt.wantErr {
+                if err == nil {
+                    t.Fatal("se esperaba un error")
+                }
+                return
+            }
+            if err != nil {
+                t.Fatal(err)
+            }
+            decoded, err := jpeg.Decode(&buf)
+            if err != nil {
+                t.Fatal(err)
+            }
+            r, g, b, _ := decoded.At(4, 4).RGBA()
+            if got := (color.RGBA{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8), 255}); got != tt.want {
+                t.Fatalf("color %v, se esperaba %v", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestEncodeGIF(t *testing.T) {
+    img := testImage(16, 16, patternFewColors)
+    img.SetNRGBA(0, 0, color.NRGBA{})
+    tests := []struct {
+        name      string
+        options   map[string]interface{}
+        maxColors int
+    }{
+        {"por defecto", nil, 256},
+        {"16 colores", map[string]interface{}{"colors": 16.0}, 16},
+        {"16 colores con tramado", map[string]interface{}{"colors": 16, "dither": true}, 16},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            var buf bytes.Buffer
+            if err := encodeGIF(&buf, img, tt.options); err != nil {
+                t.Fatal(err)
+            }
+            decoded, err := gif.Decode(&buf)
+            if err != nil {
+                t.Fatal(err)
+            }
+            paletted := decoded.(*image.Paletted)
+            if len(paletted.Palette) > tt.maxColors {
+                t.Fatalf("%d colores, máximo %d", len(paletted.Palette), tt.maxColors)
+            }
+            if _, _, _, a := paletted.At(0, 0).RGBA(); a != 0 {
+                t.Fatal("el píxel transparente debe seguir siéndolo")
+            }
+        })
+    }
+}
+
+func TestEncodeBMP(t *testing.T) {
+    img := testImage(5, 3, patternSolid)
+    img.SetNRGBA(1, 1, color.NRGBA{10, 20, 30, 255})
+    img.SetNRGBA(2, 1, color.NRGBA{0, 0, 0, 0})
+    tests := []struct {
+        name    string
+        options map[string]interface{}
+        bits    uint16
+        want    color.NRGBA // píxel transparente (2,1) tras escribirlo
+    }{
+        {"32 bits con alfa", nil, 32, color.NRGBA{}},
+        {"24 bits sobre blanco", map[string]interface{}{"bits": 24}, 24, color.NRGBA{255, 255, 255, 255}},
+        {"32 bits sin alfa sobre rojo", map[string]interface{}{"alpha": false, "background": "red"}, 24, color.NRGBA{255, 0, 0, 255}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            var buf bytes.Buffer
+            if err := encodeBMP(&buf, img, tt.options); err != nil {
+                t.Fatal(err)
+            }
+            data := buf.Bytes()
+            if bits := binary.LittleEndian.Uint16(data[28:]); bits != tt.bits {
+                t.Fatalf("%d bits por píxel, se esperaban %d", bits, tt.bits)
+            }
+            decoded, err := bmp.Decode(bytes.NewReader(data))
+            if err != nil {
+                t.Fatal(err)
+            }
+            if got := color.NRGBAModel.Convert(decoded.At(1, 1)).(color.NRGBA); got != (color.NRGBA{10, 20, 30, 255}) {
+                t.Fatalf("píxel opaco %v", got)
+            }
+            
+            // x/image/bmp ignora el alfa con cabecera de 40 bytes: se lee el byte BGRA
+            if tt.want.A == 0 {
+                if alpha := data[54+5*4+2*4+3]; alpha != 0 {
+                    t.Fatalf("alfa %d en el píxel transparente", alpha)
+                }
+                return
+            }
+            if got := color.NRGBAModel.Convert(decoded.At(2, 1)).(color.NRGBA); got != tt.want {
+                t.Fatalf("píxel transparente %v, se esperaba %v", got, tt.want)
+            }
+        })
+    }
+    
+    if err := encodeBMP(&bytes.Buffer{}, img, map[string]interface{}{"bits": 8}); err == nil {
+        t.Fatal("se esperaba un error con 8 bits")
+    }
+}
@@ -0,0 +1,354 @@
+// iconexporter/sanitize.go
+package iconexporter
+
+import (
+    "fmt"
+    "regexp"
+    "sort"
+    "strconv"
+    "strings"
+)
+
+// SanitizeConfig limpia Icon.Body antes de exportar, para colecciones que no
+// son de confianza. Mode admite:
+//   - off (por defecto): el cuerpo se usa tal cual
+//   - permissive: quita <script>, <foreignObject>, atributos on*, URLs
+//     javascript:, referencias externas de <use>, <image> y animaciones, e
+//     @import de CSS y comentarios; los enlaces externos de <a> se conservan
+//   - strict: además quita cualquier referencia externa (href y url() de CSS)
+//     y los elementos que no son de dibujo SVG
+type SanitizeConfig struct {
+    Mode string `json:"mode"`
+}
+
+// SanitizeReport enumera lo que el saneado quitó de un icono
+type SanitizeReport struct {
+    Collection string   `json:"collection"`
+    Icon       string   `json:"icon"`
+    Removed    []string `json:"removed"`
+}
+
+// Elementos que se quitan en cualquier modo
+var unsafeSvgElements = map[string]bool{
+    "script": true, "foreignobject": true, "iframe": true, "embed": true, "object": true,
+    "frame": true, "frameset": true, "applet": true, "base": true, "link": true, "meta": true,
+}
+
+// Elementos de dibujo que admite el modo estricto
+var strictSvgElements = map[string]bool{
+    "svg": true, "g": true, "defs": true, "symbol": true, "use": true, "switch": true,
+    "path": true, "rect": true, "circle": true, "ellipse": true, "line": true, "polyline": true, "polygon": true,
+    "text": true, "tspan": true, "textPath": true, "title": true, "desc": true, "metadata": true, "style": true,
+    "linearGradient": true, "radialGradient": true, "stop": true, "pattern": true, "clipPath": true, "mask": true,
+    "marker": true, "image": true, "a": true, "filter": true, "view": true,
+}
+
+// Animaciones, que pueden cambiar atributos después del saneado. En minúsculas:
+// el SVG incrustado en HTML no distingue mayúsculas (<ANIMATE> es <animate>)
+var svgAnimationElements = map[string]bool{"animate": true, "set": true, "animatemotion": true, "animatetransform": true}
+
+var (
+    cssImportPattern  = regexp.MustCompile(`(?i)@import\s*[^;]*;?`)
+    cssURLPattern     = regexp.MustCompile(`(?i)url\(\s*(['"]?)(.*?)(['"]?)\s*\)`)
+    cssEscapePattern  = regexp.MustCompile(`\\(?:([0-9a-fA-F]{1,6})[ \t\n\r\f]?|\r\n|([\s\S]))`)
+    cssCommentPattern = regexp.MustCompile(`/\*[\s\S]*?(?:\*/|$)`)
+)
+
+// sanitizeConfig completa la configuración con valores por defecto y la valida
+func (e *IconExporter) sanitizeConfig() (SanitizeConfig, error) {
+    cfg := e.config.Sanitize
+    if cfg.Mode == "" {
+        cfg.Mode = "off"
+    }
+    switch cfg.Mode {
+    case "off", "permissive", "strict":
+    default:
+        return cfg, fmt.Errorf("modo de saneado no válido: %s (admitidos: off, permissive, strict)", cfg.Mode)
+    }
+    return cfg, nil
+}
+
+// localName devuelve el nombre sin prefijo de espacio de nombres
+func localName(name string) string {
+    if colon := strings.LastIndexByte(name, ':'); colon >= 0 {
+        return name[colon+1:]
+    }
+    return name
+}
+
+// normalizedURL quita espacios y controles como hacen los navegadores al
+// interpretar el esquema ("java\tscript:" equivale a "javascript:")
+func normalizedURL(value string) string {
+    return strings.ToLower(strings.Map(func(r rune) rune {
+        if r <= ' ' || r == 0x7f {
+            return -1
+        }
+        return r
+    }, value))
+}
+
+// isScriptURL indica si la URL ejecuta código al usarse
+func isScriptURL(value string) bool {
+    url := normalizedURL(value)
+    return strings.HasPrefix(url, "javascript:") || strings.HasPrefix(url, "vbscript:") ||
+        strings.HasPrefix(url, "data:text/html") || strings.HasPrefix(url, "data:image/svg+xml") ||
+        strings.HasPrefix(url, "data:application/")
+}
+
+// isExternalURL indica si la URL apunta fuera del propio documento; las imágenes
+// raster incrustadas con data: no se consideran externas
+func isExternalURL(value string) bool {
+    url := normalizedURL(value)
+    return url != "" && !strings.HasPrefix(url, "#") && !strings.HasPrefix(url, "data:image/png") &&
+        !strings.HasPrefix(url, "data:image/jpeg") && !strings.HasPrefix(url, "data:image/gif") &&
+        !strings.HasPrefix(url, "data:image/webp")
+}
+
+// attrFold devuelve el valor de un atributo sin distinguir mayúsculas, como lo
+// lee el parser HTML (ATTRIBUTENAME equivale a attributeName)
+func attrFold(n *svgNode, name string) string {
+    for _, a := range n.Attrs {
+        if strings.EqualFold(a.Name, name) {
+            return a.Value
+        }
+    }
+    return ""
+}
+
+// svgSanitizer recorre el árbol y anota lo que quita
+type svgSanitizer struct {
+    strict  bool
+    removed []string
+    seen    map[string]bool
+}
+
+func (s *svgSanitizer) note(format string, args ...interface{}) {
+    message := fmt.Sprintf(format, args...)
+    if !s.seen[message] {
+        s.seen[message] = true
+        s.removed = append(s.removed, message)
+    }
+}
+
+// sanitizeSvgBody limpia un cuerpo de icono y devuelve el resultado y lo quitado
+func sanitizeSvgBody(body string, strict bool) (string, []string, error) {
+    root, err := parseSvgDocument([]byte(`<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink">` + body + `</svg>`))
+    if err != nil {
+        return "", nil, err
+    }
+    s := &svgSanitizer{strict: strict, seen: map[string]bool{}}
+    s.children(root)
+    
+    var b strings.Builder
+    for _, child := range root.Children {
+        b.WriteString(child.String())
+    }
+    return b.String(), s.removed, nil
+}
+
+// children limpia los hijos de un nodo, quitando los elementos no permitidos
+func (s *svgSanitizer) children(n *svgNode) {
+    kept := n.Children[:0]
+    for _, child := range n.Children {
+        switch child.Kind {
+        case svgRawNode:
+            s.note("directiva %s", strings.Fields(child.Data)[0])
+            continue
+        case svgCommentNode:
+            // Un comentario como <!--><img onerror=…>--> se cierra antes de
+            // tiempo en HTML y deja pasar el marcado que contiene
+            s.note("comentario")
+            continue
+        case svgTextNode:
+            if n.Name == "style" {
+                child.Data = s.css(child.Data, "<style>")
+            }
+        case svgElementNode:
+            if !s.element(child) {
+                continue
+            }
+            s.children(child)
+        }
+        kept = append(kept, child)
+    }
+    n.Children = kept
+}
+
+// element limpia los atributos del elemento; devuelve false si debe quitarse
+func (s *svgSanitizer) element(n *svgNode) bool {
+    name := localName(n.Name)
+    switch {
+    case unsafeSvgElements[strings.ToLower(name)]:
+        s.note("<%s>", n.Name)
+        return false
+    case svgAnimationElements[strings.ToLower(name)]:
+        // Una animación de href u on* permitiría reintroducir lo que se quita
+        attributeName := attrFold(n, "attributeName")
+        target := strings.ToLower(localName(attributeName))
+        if target == "href" || strings.HasPrefix(target, "on") || s.strict {
+            s.note("<%s> de %s", n.Name, attributeName)
+            return false
+        }
+    case s.strict && (name != n.Name || !strictSvgElements[name]):
+        s.note("<%s> no permitido en modo estricto", n.Name)
+        return false
+    }
+    
+    attrs := n.Attrs[:0]
+    for _, a := range n.Attrs {
+        attrName := strings.ToLower(localName(a.Name))
+        switch {
+        case strings.HasPrefix(attrName, "on"):
+            s.note("%s en <%s>", a.Name, n.Name)
+            continue
+        case attrName == "href" || attrName == "src" || attrName == "action" || attrName == "formaction":
+            if isScriptURL(a.Value) {
+                s.note("%s con URL ejecutable en <%s>", a.Name, n.Name)
+                continue
+            }
+            // Los enlaces solo navegan; el resto carga el recurso al dibujar
+            if isExternalURL(a.Value) && (s.strict || name != "a") {
+                s.note("%s externo en <%s>: %s", a.Name, n.Name, a.Value)
+                continue
+            }
+        case attrName == "style":
+            a.Value = s.css(a.Value, fmt.Sprintf("style de <%s>", n.Name))
+        case strings.Contains(strings.ToLower(decodeCSS(a.Value)), "url("):
+            if value := s.css(a.Value, fmt.Sprintf("%s de <%s>", a.Name, n.Name)); value != a.Value {
+                continue
+            }
+        }
+        attrs = append(attrs, a)
+    }
+    n.Attrs = attrs
+    return true
+}
+
+// css quita @import y las url() ejecutables, y en modo estricto también las
+// externas; where describe dónde estaba el CSS para el informe. Si tras eso
+// quedan reglas ocultas con escapes o comentarios (@\69mport, u\72l(), el CSS
+// se quita entero: reescribirlo sin escapes podría romper selectores válidos
+func (s *svgSanitizer) css(value, where string) string {
+    value = cssImportPattern.ReplaceAllStringFunc(value, func(rule string) string {
+        s.note("@import en %s", where)
+        return ""
+    })
+    value = cssURLPattern.ReplaceAllStringFunc(value, func(match string) string {
+        url := cssURLPattern.FindStringSubmatch(match)[2]
+        if s.unsafeURL(url) {
+            if isScriptURL(url) {
+                s.note("url() ejecutable en %s", where)
+            } else {
+                s.note("url() externa en %s: %s", where, url)
+            }
+            return "none"
+        }
+        return match
+    })
+    
+    decoded := decodeCSS(value)
+    if decoded == value {
+        return value
+    }
+    hidden := cssImportPattern.MatchString(decoded)
+    for _, match := range cssURLPattern.FindAllStringSubmatch(decoded, -1) {
+        hidden = hidden || s.unsafeURL(match[2])
+    }
+    if hidden {
+        s.note("CSS con escapes en %s", where)
+        return ""
+    }
+    return value
+}
+
+// unsafeURL indica si una url() de CSS debe quitarse en el modo actual
+func (s *svgSanitizer) unsafeURL(url string) bool {
+    return isScriptURL(url) || (s.strict && isExternalURL(url))
+}
+
+// decodeCSS resuelve los escapes de CSS y quita los comentarios, para buscar
+// las reglas tal como las interpreta el navegador
+func decodeCSS(value string) string {
+    value = cssCommentPattern.ReplaceAllString(value, "")
+    return cssEscapePattern.ReplaceAllStringFunc(value, func(escape string) string {
+        match := cssEscapePattern.FindStringSubmatch(escape)
+        switch {
+        case match[1] != "":
+            code, _ := strconv.ParseUint(match[1], 16, 32)
+            if code == 0 || code > 0x10FFFF || (code >= 0xD800 && code <= 0xDFFF) {
+                return "\uFFFD"
+            }
+            return string(rune(code))
+        case match[2] != "" && match[2] != "\n" && match[2] != "\r" && match[2] != "\f":
+            return match[2]
+        }
+        // Barra seguida de salto de línea: continuación dentro de una cadena
+        return ""
+    })
+}
+
+// sanitizeCollection limpia los iconos que se van a exportar de una colección.
+// Los cuerpos que no se pueden analizar se descartan, porque no es posible
+// comprobar qué contienen.
+func (e *IconExporter) sanitizeCollection(collection string, iconData IconData) IconData {
+    cfg, _ := e.sanitizeConfig() // validada en validateConfig
+    if cfg.Mode == "off" {
+        return iconData
+    }
+    
+    for _, iconName := range e.getIconsToProcess(iconData) {
+        icon, exists := iconData.Icons[iconName]
+        if !exists {
+            continue
+        }
+        body, removed, err := sanitizeSvgBody(icon.Body, cfg.Mode == "strict")
+        if err != nil {
+            fmt.Printf("❌ Icono '%s' descartado: no se pudo sanear: %v\n", iconName, err)
+            delete(iconData.Icons, iconName)
+            continue
+        }
+        icon.Body = body
+        iconData.Icons[iconName] = icon
+        if len(removed) > 0 {
+            e.recordSanitized(SanitizeReport{collection, iconName, removed})
+        }
+    }
+    return iconData
+}
+
+// recordSanitized guarda el informe de un icono y lo muestra la primera vez
+func (e *IconExporter) recordSanitized(report SanitizeReport) {
+    e.mu.Lock()
+    defer e.mu.Unlock()
+    
+    key := report.Collection + "/" + report.Icon
+    if e.sanitized == nil {
+        e.sanitized = map[string]SanitizeReport{}
+    }
+    if _, exists := e.sanitized[key]; exists {
+        return
+    }
+    e.sanitized[key] = report
+    for _, item := range report.Removed {
+        fmt.Printf("🧹 %s: eliminado %s\n", report.Icon, item)
+    }
+}
+
+// sanitizeReports devuelve, ordenados, los informes de saneado de los iconos
+// cargados por este exportador
+func (e *IconExporter) sanitizeReports() []SanitizeReport {
+    e.mu.Lock()
+    defer e.mu.Unlock()
+    
+    var reports []SanitizeReport
+    for _, report := range e.sanitized {
+        reports = append(reports, report)
+    }
+    sort.Slice(reports, func(i, j int) bool {
+        if reports[i].Collection != reports[j].Collection {
+            return reports[i].Collection < reports[j].Collection
+        }
+        return reports[i].Icon < reports[j].Icon
+    })
+    return reports
+}
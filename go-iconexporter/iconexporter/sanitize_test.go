@@ -0,0 +1,227 @@
+// iconexporter/sanitize_test.go
+package iconexporter
+
+import (
+    "reflect"
+    "strings"
+    "testing"
+)
+
+func TestIsScriptURL(t *testing.T) {
+    tests := []struct {
+        url  string
+        want bool
+    }{
+        {"javascript:alert(1)", true},
+        {"JavaScript:alert(1)", true},
+        {"java\tscript:alert(1)", true},
+        {" \njavascript:alert(1)", true},
+        {"vbscript:msgbox", true},
+        {"data:text/html,<script>", true},
+        {"data:image/svg+xml;base64,AA", true},
+        {"data:image/png;base64,AA", false},
+        {"#local", false},
+        {"https://example.com", false},
+    }
+    for _, tt := range tests {
+        if got := isScriptURL(tt.url); got != tt.want {
+            t.Errorf("isScriptURL(%q) = %v, se esperaba %v", tt.url, got, tt.want)
+        }
+    }
+}
+
+func TestIsExternalURL(t *testing.T) {
+    tests := []struct {
+        url  string
+        want bool
+    }{
+        {"#local", false},
+        {"", false},
+        {"data:image/png;base64,AA", false},
+        {"data:image/webp;base64,AA", false},
+        {"other.svg#i", true},
+        {"https://example.com/x.png", true},
+        {"//example.com/x.png", true},
+    }
+    for _, tt := range tests {
+        if got := isExternalURL(tt.url); got != tt.want {
+            t.Errorf("isExternalURL(%q) = %v, se esperaba %v", tt.url, got, tt.want)
+        }
+    }
+}
+
+func TestSanitizeSvgBody(t *testing.T) {
+    tests := []struct {
+        name    string
+        body    string
+        strict  bool
+        want    string
+        removed []string
+    }{
+        {
+            name:    "script",
+            body:    `<script>alert(1)</script><path d="M0 0h4v4z"/>`,
+            want:    `<path d="M0 0h4v4z"/>`,
+            removed: []string{"<script>"},
+        },
+        {
+            name:    "eventos sin distinguir mayúsculas",
+            body:    `<path d="M0 0h4v4z" onclick="x()" ONLOAD="y()"/>`,
+            want:    `<path d="M0 0h4v4z"/>`,
+            removed: []string{"onclick en <path>", "ONLOAD en <path>"},
+        },
+        {
+            name:    "URL ejecutable con tabulador",
+            body:    `<a xlink:href="java&#9;script:alert(1)"><rect width="1" height="1"/></a>`,
+            want:    `<a><rect width="1" height="1"/></a>`,
+            removed: []string{"xlink:href con URL ejecutable en <a>"},
+        },
+        {
+            name: "enlace externo permisivo",
+            body: `<a href="https://example.com"><circle r="2"/></a>`,
+            want: `<a href="https://example.com"><circle r="2"/></a>`,
+        },
+        {
+            name:    "enlace externo estricto",
+            body:    `<a href="https://example.com"><circle r="2"/></a>`,
+            strict:  true,
+            want:    `<a><circle r="2"/></a>`,
+            removed: []string{"href externo en <a>: https://example.com"},
+        },
+        {
+            name:    "use externo",
+            body:    `<use href="other.svg#i"/><use href="#local"/>`,
+            want:    `<use/><use href="#local"/>`,
+            removed: []string{"href externo en <use>: other.svg#i"},
+        },
+        {
+            name:    "foreignObject",
+            body:    `<foreignObject><div xmlns="http://www.w3.org/1999/xhtml">x</div></foreignObject><circle r="2"/>`,
+            want:    `<circle r="2"/>`,
+            removed: []string{"<foreignObject>"},
+        },
+        {
+            name:    "@import y url() ejecutable",
+            body:    `<style>@import url(http://evil/x.css); .a{fill:red}</style><path style="fill:url(javascript:1);stroke:red" d="M0 0"/>`,
+            want:    `<style> .a{fill:red}</style><path style="fill:none;stroke:red" d="M0 0"/>`,
+            removed: []string{"@import en <style>", "url() ejecutable en style de <path>"},
+        },
+        {
+            name:    "@import y url() con escapes",
+            body:    `<style>@\69mport url(http://evil/x.css);</style><path style="fill:u\72l(javascript:alert(1))" d="M0 0"/><path fill="url(/**/'javascript:1')" d="M0 0"/>`,
+            want:    `<style></style><path style="" d="M0 0"/><path d="M0 0"/>`,
+            removed: []string{"CSS con escapes en <style>", "CSS con escapes en style de <path>", "CSS con escapes en fill de <path>"},
+        },
+        {
+            name: "escapes inocuos",
+            body: `<style>#a\:b { fill: red } /* nota */</style>`,
+            want: `<style>#a\:b { fill: red } /* nota */</style>`,
+        },
+        {
+            // En HTML <!--> cierra el comentario y el <img> se ejecuta
+            name:    "comentarios",
+            body:    `<!--><img src=x onerror=alert(1)>--><path d="M0 0"/><!-- editor -->`,
+            want:    `<path d="M0 0"/>`,
+            removed: []string{"comentario"},
+        },
+        {
+            name: "animación inocua",
+            body: `<animate attributeName="opacity" values="0;1"/>`,
+            want: `<animate attributeName="opacity" values="0;1"/>`,
+        },
+        {
+            name:    "animación de href",
+            body:    `<a href="#"><animate attributeName="href" values="javascript:alert(1)"/></a>`,
+            want:    `<a href="#"/>`,
+            removed: []string{"<animate> de href"},
+        },
+        {
+            // El parser HTML normaliza los nombres: sigue siendo una animación
+            name:    "animación en mayúsculas",
+            body:    `<a href="#"><ANIMATE ATTRIBUTENAME="href" values="javascript:alert(1)"/></a>`,
+            want:    `<a href="#"/>`,
+            removed: []string{"<ANIMATE> de href"},
+        },
+        {
+            name:    "set en mayúsculas",
+            body:    `<a href="#"><SET attributeName="href" to="javascript:alert(1)"/></a>`,
+            want:    `<a href="#"/>`,
+            removed: []string{"<SET> de href"},
+        },
+        {
+            name:    "animateTransform sin distinguir mayúsculas",
+            body:    `<g><ANIMATETRANSFORM attributeName="onbegin" to="alert(1)"/></g>`,
+            want:    `<g/>`,
+            removed: []string{"<ANIMATETRANSFORM> de onbegin"},
+        },
+        {
+            name:    "set de xlink:href",
+            body:    `<a><set attributeName="xlink:href" to="javascript:1"/></a>`,
+            want:    `<a/>`,
+            removed: []string{"<set> de xlink:href"},
+        },
+        {
+            name:    "animaciones en modo estricto",
+            body:    `<animate attributeName="opacity" values="0;1"/><circle r="2"/>`,
+            strict:  true,
+            want:    `<circle r="2"/>`,
+            removed: []string{"<animate> de opacity"},
+        },
+        {
+            name:    "elementos de otro espacio de nombres en modo estricto",
+            body:    `<html:iframe src="x"/><custom/><circle r="2"/>`,
+            strict:  true,
+            want:    `<circle r="2"/>`,
+            removed: []string{"<html:iframe>", "<custom> no permitido en modo estricto"},
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got, removed, err := sanitizeSvgBody(tt.body, tt.strict)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if got != tt.want {
+                t.Errorf("%s\nse esperaba\n%s", got, tt.want)
+            }
+            if len(removed) != 0 || len(tt.removed) != 0 {
+                if !reflect.DeepEqual(removed, tt.removed) {
+                    t.Errorf("quitado %q, se esperaba %q", removed, tt.removed)
+                }
+            }
+        })
+    }
+}
+
+func TestSanitizeSvgBodyInvalid(t *testing.T) {
+    if _, _, err := sanitizeSvgBody(`<path d="x`, false); err == nil {
+        t.Fatal("se esperaba un error")
+    }
+}
+
+// Los iconos que no se pueden analizar se descartan y el resto se informa
+func TestSanitizeCollection(t *testing.T) {
+    e := newTestExporter(t, Config{Collections: []string{"x"}, Sanitize: SanitizeConfig{Mode: "permissive"}})
+    data := e.sanitizeCollection("x", IconData{Icons: map[string]Icon{
+        "script": {Body: `<script>alert(1)</script><path d="M0 0h4v4z"/>`},
+        "limpio": {Body: `<path d="M0 0h4v4z"/>`},
+        "roto":   {Body: `<g d="`},
+    }})
+    if _, exists := data.Icons["roto"]; exists || len(data.Icons) != 2 {
+        t.Fatalf("iconos inesperados: %v", data.Icons)
+    }
+    if strings.Contains(data.Icons["script"].Body, "script") {
+        t.Fatalf("no se saneó: %s", data.Icons["script"].Body)
+    }
+    reports := e.sanitizeReports()
+    want := []SanitizeReport{{"x", "script", []string{"<script>"}}}
+    if !reflect.DeepEqual(reports, want) {
+        t.Fatalf("informes %+v, se esperaban %+v", reports, want)
+    }
+}
+
+func TestSanitizeConfigErrors(t *testing.T) {
+    if _, err := NewIconExporter(Config{Collections: []string{"nonicons"}, Sanitize: SanitizeConfig{Mode: "paranoico"}}); err == nil {
+        t.Fatal("se esperaba un error")
+    }
+}
@@ -0,0 +1,176 @@
+// iconexporter/sprite.go
+package iconexporter
+
+import (
+    "encoding/json"
+    "fmt"
+    "html"
+    "io"
+    "path/filepath"
+    "regexp"
+    "sort"
+    "strings"
+)
+
+// SpriteConfig configura el sprite SVG con un <symbol> por icono exportado
+type SpriteConfig struct {
+    Enabled   bool   `json:"enabled"`
+    FileName  string `json:"fileName"`
+    IDPattern string `json:"idPattern"`
+    IDList    string `json:"idList"`
+}
+
+// iconEntry es un icono que se añade a los archivos agrupados (sprite, atlas,
+// fuente, componentes)
+type iconEntry struct {
+    Collection string
+    Name       string
+    Icon       Icon
+}
+
+// collectIconEntries reúne los iconos de todas las colecciones en orden estable;
+// devuelve también cuántos iconos pedidos no existen
+func (e *IconExporter) collectIconEntries() ([]iconEntry, int) {
+    var entries []iconEntry
+    missing := 0
+    for _, collection := range e.config.Collections {
+        iconData, err := e.loadCollectionData(collection)
+        if err != nil {
+            fmt.Printf("❌ Error cargando colección %s: %v\n", collection, err)
+            continue
+        }
+        // Copia: getIconsToProcess puede devolver Config.IconsToExport
+        icons := append([]string(nil), e.getIconsToProcess(iconData)...)
+        sort.Strings(icons)
+        for _, iconName := range icons {
+            icon, exists := iconData.Icons[iconName]
+            if !exists {
+                fmt.Printf("⚠️ Icono '%s' no encontrado en %s\n", iconName, collection)
+                missing++
+                continue
+            }
+            entries = append(entries, iconEntry{collection, iconName, icon})
+        }
+    }
+    return entries, missing
+}
+
+// Caracteres no válidos en un id XML
+var invalidSpriteIDChars = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// spriteConfig completa la configuración con valores por defecto
+func (e *IconExporter) spriteConfig() (SpriteConfig, error) {
+    cfg := e.config.Sprite
+    if cfg.FileName == "" {
+        cfg.FileName = "sprite.svg"
+    }
+    if cfg.IDPattern == "" {
+        cfg.IDPattern = "{collection}-{icon}"
+    }
+    switch cfg.IDList {
+    case "", "json", "ts":
+    default:
+        return cfg, fmt.Errorf("idList no válido para el sprite: %s (admitidos: json, ts)", cfg.IDList)
+    }
+    return cfg, nil
+}
+
+// spriteID genera el id del <symbol>; el color se añade si hay varios
+func (e *IconExporter) spriteID(pattern, collection, iconName, col string, multipleColors bool) string {
+    id := strings.ReplaceAll(pattern, "{collection}", collection)
+    id = strings.ReplaceAll(id, "{icon}", iconName)
+    cleanColor := strings.ReplaceAll(col, "#", "")
+    if strings.Contains(id, "{color}") {
+        id = strings.ReplaceAll(id, "{color}", cleanColor)
+    } else if multipleColors {
+        id += "-" + cleanColor
+    }
+    
+    id = invalidSpriteIDChars.ReplaceAllString(id, "-")
+    id = e.applyCase(id, e.config.FileNaming.Case)
+    
+    // Un id no puede empezar por número, guion o punto
+    if id == "" || !(id[0] == '_' || (id[0]|0x20 >= 'a' && id[0]|0x20 <= 'z')) {
+        id = "_" + id
+    }
+    return id
+}
+
+// exportSprite escribe el sprite con todos los iconos y colores y, si se pide,
+// la lista de ids en JSON o TypeScript
+func (e *IconExporter) exportSprite(entries []iconEntry, colors []string) []ExportResult {
+    cfg, _ := e.spriteConfig() // validada en validateConfig
+    
+    type symbol struct {
+        id, viewBox, body string
+    }
+    var symbols []symbol
+    seen := map[string]int{}
+    for _, entry := range entries {
+        for _, col := range colors {
+            id := e.spriteID(cfg.IDPattern, entry.Collection, entry.Name, col, len(colors) > 1)
+            if seen[id]++; seen[id] > 1 {
+                id = fmt.Sprintf("%s-%d", id, seen[id])
+            }
+            body := entry.Icon.Body
+            // ":" no puede aparecer en el id del símbolo, así que el prefijo
+            // de "a" nunca coincide con el de otro símbolo como "a-b"
+            if e.config.IDPrefix.Enabled {
+                body = prefixSvgIds(body, id+":")
+            }
+            symbols = append(symbols, symbol{id, entry.Icon.ViewBox, e.applySvgColor(body, col)})
+        }
+    }
+    sort.Slice(symbols, func(i, j int) bool { return symbols[i].id < symbols[j].id })
+    
+    var results []ExportResult
+    save := func(fileName, format string, write func(w io.Writer) error) {
+        filePath := filepath.Join(e.config.OutputDir, fileName)
+        result := ExportResult{Format: format, Path: filePath}
+        if err := writeFile(filePath, write); err != nil {
+            fmt.Printf("❌ Error al guardar %s: %v\n", filePath, err)
+            result.Error = err.Error()
+        } else {
+            fmt.Printf("✅ Exportado: %s (%d símbolos)\n", filePath, len(symbols))
+        }
+        results = append(results, result)
+    }
+    
+    // Oculto si se inserta en el HTML, sin usar display:none (rompe los degradados)
+    save(cfg.FileName, "sprite", func(w io.Writer) error {
+        var b strings.Builder
+        b.WriteString(`<svg xmlns="http://www.w3.org/2000/svg" aria-hidden="true" style="position:absolute;width:0;height:0;overflow:hidden">` + "\n")
+        for _, s := range symbols {
+            fmt.Fprintf(&b, "  <symbol id=\"%s\" viewBox=\"%s\">%s</symbol>\n",
+                html.EscapeString(s.id), html.EscapeString(s.viewBox), s.body)
+        }
+        b.WriteString("</svg>\n")
+        _, err := io.WriteString(w, b.String())
+        return err
+    })
+    
+    ids := make([]string, len(symbols))
+    for i, s := range symbols {
+        ids[i] = s.id
+    }
+    baseName := strings.TrimSuffix(cfg.FileName, filepath.Ext(cfg.FileName))
+    switch cfg.IDList {
+    case "json":
+        save(baseName+".json", "json", func(w io.Writer) error {
+            encoder := json.NewEncoder(w)
+            encoder.SetIndent("", "  ")
+            return encoder.Encode(ids)
+        })
+    case "ts":
+        save(baseName+".ts", "ts", func(w io.Writer) error {
+            quoted, err := json.MarshalIndent(ids, "", "  ")
+            if err != nil {
+                return err
+            }
+            _, err = fmt.Fprintf(w, "export const iconIds = %s as const;\n\nexport type IconId = (typeof iconIds)[number];\n", quoted)
+            return err
+        })
+    }
+    
+    return results
+}
@@ -0,0 +1,130 @@
+// iconexporter/sprite_test.go
+package iconexporter
+
+import (
+    "encoding/json"
+    "os"
+    "path/filepath"
+    "reflect"
+    "regexp"
+    "strings"
+    "testing"
+)
+
+func TestSpriteID(t *testing.T) {
+    tests := []struct {
+        name     string
+        pattern  string
+        icon     string
+        color    string
+        multiple bool
+        caseType string
+        want     string
+    }{
+        {"patrón por defecto", "{collection}-{icon}", "bell", "red", false, "kebab", "nonicons-bell"},
+        {"color añadido", "{collection}-{icon}", "bell", "#FF5733", true, "kebab", "nonicons-bell-ff5733"},
+        {"color en el patrón", "{icon}_{color}", "bell", "#00ff00", false, "snake", "bell_00ff00"},
+        {"caracteres no válidos", "{icon}", "bell/alt fill", "red", false, "kebab", "bell-alt-fill"},
+        {"empieza por número", "{icon}", "2fa", "red", false, "kebab", "_2fa"},
+        {"camel", "{collection}-{icon}", "bell-dot", "red", false, "camel", "noniconsBellDot"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            e := newTestExporter(t, Config{FileNaming: FileNamingConfig{Case: tt.caseType}})
+            if got := e.spriteID(tt.pattern, "nonicons", tt.icon, tt.color, tt.multiple); got != tt.want {
+                t.Fatalf("%q, se esperaba %q", got, tt.want)
+            }
+        })
+    }
+}
+
+var spriteSymbolPattern = regexp.MustCompile(`<symbol id="([^"]+)" viewBox="([^"]+)">(.*?)</symbol>`)
+
+func TestExportSprite(t *testing.T) {
+    tests := []struct {
+        name   string
+        sprite SpriteConfig
+        colors []string
+        ids    []string
+        idList string // archivo con la lista de ids
+    }{
+        {
+            name:   "un color",
+            sprite: SpriteConfig{Enabled: true},
+            ids:    []string{"devicon-angular", "nonicons-bell"},
+        },
+        {
+            name:   "dos colores y lista JSON",
+            sprite: SpriteConfig{Enabled: true, FileName: "icons.svg", IDList: "json"},
+            colors: []string{"red", "#00ff00"},
+            ids:    []string{"devicon-angular-00ff00", "devicon-angular-red", "nonicons-bell-00ff00", "nonicons-bell-red"},
+            idList: "icons.json",
+        },
+        {
+            name:   "ids repetidos y lista TypeScript",
+            sprite: SpriteConfig{Enabled: true, IDPattern: "icon", IDList: "ts"},
+            ids:    []string{"icon", "icon-2"},
+            idList: "sprite.ts",
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            e := newTestExporter(t, Config{OutputFormats: []string{"svg"}, Sprite: tt.sprite, FileNaming: FileNamingConfig{Case: "kebab"}})
+            summary, err := e.ExportWithVariants(nil, tt.colors)
+            if err != nil || summary.Errors != 0 {
+                t.Fatalf("%v, %+v", err, summary)
+            }
+            
+            fileName := tt.sprite.FileName
+            if fileName == "" {
+                fileName = "sprite.svg"
+            }
+            data, err := os.ReadFile(filepath.Join(e.config.OutputDir, fileName))
+            if err != nil {
+                t.Fatal(err)
+            }
+            if _, err := parseSvgDocument(data); err != nil {
+                t.Fatalf("sprite no válido: %v", err)
+            }
+            var ids []string
+            for _, m := range spriteSymbolPattern.FindAllStringSubmatch(string(data), -1) {
+                ids = append(ids, m[1])
+                if m[2] != "0 0 24 24" || !strings.Contains(m[3], "<path") {
+                    t.Fatalf("símbolo inesperado: %s", m[0])
+                }
+                if color := m[1][strings.LastIndexByte(m[1], '-')+1:]; color == "00ff00" && !strings.Contains(m[3], `fill="#00ff00"`) {
+                    t.Fatalf("%s sin su color: %s", m[1], m[3])
+                }
+            }
+            if !reflect.DeepEqual(ids, tt.ids) {
+                t.Fatalf("ids %v, se esperaban %v", ids, tt.ids)
+            }
+            
+            if tt.idList == "" {
+                return
+            }
+            list, err := os.ReadFile(filepath.Join(e.config.OutputDir, tt.idList))
+            if err != nil {
+                t.Fatal(err)
+            }
+            text := string(list)
+            if strings.HasSuffix(tt.idList, ".ts") {
+                if !strings.HasPrefix(text, "export const iconIds = ") || !strings.Contains(text, "export type IconId") {
+                    t.Fatalf("lista TypeScript inesperada:\n%s", text)
+                }
+                text = strings.TrimPrefix(text[:strings.Index(text, " as const")], "export const iconIds = ")
+            }
+            var listed []string
+            if err := json.Unmarshal([]byte(text), &listed); err != nil || !reflect.DeepEqual(listed, tt.ids) {
+                t.Fatalf("lista %v (%v), se esperaba %v", listed, err, tt.ids)
+            }
+        })
+    }
+}
+
+func TestSpriteConfigInvalidIDList(t *testing.T) {
+    _, err := NewIconExporter(Config{Collections: []string{"nonicons"}, Sprite: SpriteConfig{IDList: "yaml"}})
+    if err == nil || !strings.Contains(err.Error(), "idList") {
+        t.Fatalf("error inesperado: %v", err)
+    }
+}
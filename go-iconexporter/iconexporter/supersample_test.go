@@ -0,0 +1,236 @@
+// iconexporter/supersample_test.go
+package iconexporter
+
+import (
+    "bytes"
+    "image"
+    "image/color"
+    "math"
+    "testing"
+
+    "github.com/disintegration/imaging"
+    "github.com/srwiley/oksvg"
+)
+
+func TestRasterConfig(t *testing.T) {
+    e := newTestExporter(t, Config{})
+    cfg, err := e.rasterConfig()
+    if err != nil {
+        t.Fatal(err)
+    }
+    if want := (RasterConfig{Supersample: 1, MaxSize: 256, Filter: "box"}); cfg != want {
+        t.Fatalf("%+v, se esperaba %+v", cfg, want)
+    }
+    
+    for name, raster := range map[string]RasterConfig{
+        "supersample negativo": {Supersample: -1},
+        "supersample grande":   {Supersample: 9},
+        "filtro":               {Filter: "bicubic"},
+    } {
+        if _, err := NewIconExporter(Config{Collections: []string{"nonicons"}, Raster: raster}); err == nil {
+            t.Errorf("%s: se esperaba un error", name)
+        }
+    }
+}
+
+func TestResampleWeights(t *testing.T) {
+    tests := []struct {
+        name         string
+        src, dst     int
+        filter       imaging.ResampleFilter
+        firstIndices []int
+        firstWeight  float64
+    }{
+        {"box 4:1", 16, 4, imaging.Box, []int{0, 1, 2, 3}, 0.25},
+        {"box 2:1", 8, 4, imaging.Box, []int{0, 1}, 0.5},
+        {"nearest sin reducción", 4, 4, imaging.NearestNeighbor, []int{0}, 1},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            indices, weights := resampleWeights(tt.src, tt.dst, tt.filter)
+            if len(indices) != tt.dst {
+                t.Fatalf("%d píxeles de destino, se esperaban %d", len(indices), tt.dst)
+            }
+            for d := range weights {
+                sum := 0.0
+                for i, w := range weights[d] {
+                    sum += w
+                    if s := indices[d][i]; s < 0 || s >= tt.src {
+                        t.Fatalf("índice %d fuera del origen", s)
+                    }
+                }
+                if math.Abs(sum-1) > 1e-9 {
+                    t.Fatalf("los pesos del píxel %d suman %g", d, sum)
+                }
+            }
+            if len(indices[0]) != len(tt.firstIndices) {
+                t.Fatalf("índices %v, se esperaban %v", indices[0], tt.firstIndices)
+            }
+            for i, s := range tt.firstIndices {
+                if indices[0][i] != s || math.Abs(weights[0][i]-tt.firstWeight) > 1e-9 {
+                    t.Fatalf("índices %v con pesos %v, se esperaban %v con %g", indices[0], weights[0], tt.firstIndices, tt.firstWeight)
+                }
+            }
+        })
+    }
+    
+    // Los filtros con soporte amplio repiten el borde en lugar de salirse
+    indices, _ := resampleWeights(16, 4, imaging.Lanczos)
+    if indices[0][0] != 0 || indices[3][len(indices[3])-1] != 15 {
+        t.Fatalf("índices de borde %v y %v", indices[0], indices[3])
+    }
+}
+
+func TestResampleLinear(t *testing.T) {
+    tests := []struct {
+        name string
+        src  func() *image.NRGBA
+        want color.NRGBA
+    }{
+        {
+            name: "color uniforme",
+            src: func() *image.NRGBA {
+                return imaging.New(4, 4, color.NRGBA{255, 87, 51, 255})
+            },
+            want: color.NRGBA{255, 87, 51, 255},
+        },
+        {
+            // El promedio en luz lineal de blanco y negro es más claro que 128
+            name: "damero en luz lineal",
+            src: func() *image.NRGBA {
+                img := imaging.New(2, 2, color.NRGBA{0, 0, 0, 255})
+                img.SetNRGBA(0, 0, color.NRGBA{255, 255, 255, 255})
+                img.SetNRGBA(1, 1, color.NRGBA{255, 255, 255, 255})
+                return img
+            },
+            want: color.NRGBA{188, 188, 188, 255},
+        },
+        {
+            // Con alfa premultiplicado el borde no se oscurece
+            name: "borde semitransparente",
+            src: func() *image.NRGBA {
+                img := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+                img.SetNRGBA(0, 0, color.NRGBA{255, 0, 0, 255})
+                img.SetNRGBA(1, 1, color.NRGBA{255, 0, 0, 255})
+                return img
+            },
+            want: color.NRGBA{255, 0, 0, 128},
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            src := tt.src()
+            size := src.Bounds().Dx() / 2
+            dst := resampleLinear(src, size, size, imaging.Box)
+            if dst.Bounds().Dx() != size || dst.Bounds().Dy() != size {
+                t.Fatalf("tamaño %v", dst.Bounds())
+            }
+            if got := dst.NRGBAAt(0, 0); got != tt.want {
+                t.Fatalf("%v, se esperaba %v", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestRasterTarget(t *testing.T) {
+    icon, err := oksvg.ReadIconStream(bytes.NewReader(testSvg(`<path d="M0 0h24v24z"/>`)))
+    if err != nil {
+        t.Fatal(err)
+    }
+    tests := []struct {
+        name          string
+        width, height int
+        snap          bool
+        want          [4]float64
+    }{
+        {"sin ajuste", 30, 30, false, [4]float64{0, 0, 30, 30}},
+        {"escala entera centrada", 30, 30, true, [4]float64{3, 3, 24, 24}},
+        {"escala 2", 50, 50, true, [4]float64{1, 1, 48, 48}},
+        {"reducción sin redondear la escala", 16, 16, true, [4]float64{0, 0, 16, 16}},
+        {"rectangular", 64, 40, true, [4]float64{20, 8, 24, 24}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            x, y, w, h := rasterTarget(icon, tt.width, tt.height, tt.snap)
+            if got := [4]float64{x, y, w, h}; got != tt.want {
+                t.Fatalf("%v, se esperaba %v", got, tt.want)
+            }
+        })
+    }
+}
+
+// Un rectángulo alineado a la rejilla del viewBox queda nítido con PixelSnap
+func TestRasterizePixelSnap(t *testing.T) {
+    svg := testSvg(`<path d="M4 4h16v16H4z"/>`)
+    partial := func(img *image.NRGBA) int {
+        n := 0
+        for i := 3; i < len(img.Pix); i += 4 {
+            if img.Pix[i] != 0 && img.Pix[i] != 255 {
+                n++
+            }
+        }
+        return n
+    }
+    plain, err := newTestExporter(t, Config{}).rasterizeSvg(svg, 28, 28)
+    if err != nil {
+        t.Fatal(err)
+    }
+    snapped, err := newTestExporter(t, Config{Raster: RasterConfig{PixelSnap: true}}).rasterizeSvg(svg, 28, 28)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if partial(plain) == 0 {
+        t.Fatal("sin ajuste se esperaban bordes antialias")
+    }
+    if n := partial(snapped); n != 0 {
+        t.Fatalf("%d píxeles parciales con PixelSnap", n)
+    }
+    if snapped.NRGBAAt(6, 6).A != 255 || snapped.NRGBAAt(5, 5).A != 0 || snapped.NRGBAAt(21, 21).A != 255 || snapped.NRGBAAt(22, 22).A != 0 {
+        t.Fatal("el rectángulo no ocupa los píxeles 6-21")
+    }
+}
+
+func TestRasterizeSupersample(t *testing.T) {
+    svg := testSvg(`<path d="M2 3L21 5L12 22Z"/><circle cx="17" cy="16" r="4.3"/>`)
+    render := func(raster RasterConfig, size int) *image.NRGBA {
+        t.Helper()
+        img, err := newTestExporter(t, Config{Raster: raster}).rasterizeSvg(svg, size, size)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if img.Bounds().Dx() != size || img.Bounds().Dy() != size {
+            t.Fatalf("tamaño %v, se esperaban %d px", img.Bounds(), size)
+        }
+        return img
+    }
+    base := render(RasterConfig{}, 16)
+    
+    // Dibujar a 4x y reducir con box equivale a reducir el dibujo a 64 px
+    big := render(RasterConfig{}, 64)
+    if diff := pixelDiff(render(RasterConfig{Supersample: 4}, 16), imaging.Resize(big, 16, 16, imaging.Box), 1); diff != 0 {
+        t.Fatalf("%d píxeles distintos de la reducción manual", diff)
+    }
+    
+    tests := []struct {
+        name    string
+        raster  RasterConfig
+        maxDiff int // píxeles que pueden cambiar respecto al dibujo directo
+    }{
+        {"box", RasterConfig{Supersample: 4}, 60},
+        {"gamma", RasterConfig{Supersample: 4, Gamma: true}, 60},
+        {"lanczos", RasterConfig{Supersample: 4, Filter: "lanczos"}, 60},
+        {"por encima de maxSize", RasterConfig{Supersample: 4, MaxSize: 8}, 0},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            img := render(tt.raster, 16)
+            // Solo cambian los bordes: el interior y el fondo siguen casi iguales
+            if img.NRGBAAt(10, 8).A < 250 || img.NRGBAAt(0, 15).A != 0 {
+                t.Fatalf("interior %v, fondo %v", img.NRGBAAt(10, 8), img.NRGBAAt(0, 15))
+            }
+            if diff := pixelDiff(img, base, 40); diff > tt.maxDiff {
+                t.Fatalf("%d píxeles difieren del dibujo directo", diff)
+            }
+        })
+    }
+}
@@ -0,0 +1,363 @@
+// iconexporter/svgdoc.go
+package iconexporter
+
+import (
+    "bytes"
+    "encoding/xml"
+    "fmt"
+    "image/color"
+    "io"
+    "strconv"
+    "strings"
+)
+
+// Tipos de nodo del árbol SVG
+const (
+    svgElementNode = iota
+    svgTextNode
+    svgCommentNode
+    svgRawNode // instrucciones de proceso y directivas, se conservan tal cual
+)
+
+// svgAttr es un atributo con su nombre cualificado (p. ej. "xlink:href")
+type svgAttr struct {
+    Name  string
+    Value string
+}
+
+// svgNode es un nodo del árbol SVG; conserva el orden de atributos e hijos para
+// poder volver a serializarlo sin cambios
+type svgNode struct {
+    Kind     int
+    Name     string
+    Attrs    []svgAttr
+    Children []*svgNode
+    Data     string
+}
+
+// Propiedades de presentación que se heredan de los elementos contenedores
+var inheritedSvgProperties = map[string]bool{
+    "fill":              true,
+    "fill-opacity":      true,
+    "fill-rule":         true,
+    "stroke":            true,
+    "stroke-width":      true,
+    "stroke-opacity":    true,
+    "stroke-linecap":    true,
+    "stroke-linejoin":   true,
+    "stroke-miterlimit": true,
+    "stroke-dasharray":  true,
+    "stroke-dashoffset": true,
+    "color":             true,
+    "visibility":        true,
+}
+
+// svgStyle contiene las propiedades heredadas ya resueltas para un elemento
+type svgStyle map[string]string
+
+// svgPaint es el resultado de resolver fill o stroke
+type svgPaint struct {
+    None  bool
+    URL   string // referencia url(#id), p. ej. a un degradado
+    Color color.NRGBA
+}
+
+// qualifiedName une prefijo y nombre local
+func qualifiedName(name xml.Name) string {
+    if name.Space == "" {
+        return name.Local
+    }
+    return name.Space + ":" + name.Local
+}
+
+// parseSvgDocument analiza el SVG y devuelve el elemento raíz
+func parseSvgDocument(data []byte) (*svgNode, error) {
+    decoder := xml.NewDecoder(bytes.NewReader(data))
+    decoder.Strict = false
+    decoder.Entity = xml.HTMLEntity
+    
+    document := &svgNode{Kind: svgElementNode}
+    stack := []*svgNode{document}
+    for {
+        token, err := decoder.RawToken()
+        if err == io.EOF {
+            break
+        }
+        if err != nil {
+            return nil, fmt.Errorf("error parsing SVG: %w", err)
+        }
+        parent := stack[len(stack)-1]
+        
+        switch t := token.(type) {
+        case xml.StartElement:
+            node := &svgNode{Kind: svgElementNode, Name: qualifiedName(t.Name)}
+            for _, attr := range t.Attr {
+                node.Attrs = append(node.Attrs, svgAttr{qualifiedName(attr.Name), attr.Value})
+            }
+            parent.Children = append(parent.Children, node)
+            stack = append(stack, node)
+        case xml.EndElement:
+            if len(stack) == 1 {
+                return nil, fmt.Errorf("error parsing SVG: cierre inesperado </%s>", qualifiedName(t.Name))
+            }
+            stack = stack[:len(stack)-1]
+        case xml.CharData:
+            parent.Children = append(parent.Children, &svgNode{Kind: svgTextNode, Data: string(t)})
+        case xml.Comment:
+            parent.Children = append(parent.Children, &svgNode{Kind: svgCommentNode, Data: string(t)})
+        case xml.ProcInst:
+            parent.Children = append(parent.Children, &svgNode{Kind: svgRawNode, Data: fmt.Sprintf("<?%s %s?>", t.Target, t.Inst)})
+        case xml.Directive:
+            parent.Children = append(parent.Children, &svgNode{Kind: svgRawNode, Data: fmt.Sprintf("<!%s>", t)})
+        }
+    }
+    
+    for _, child := range document.Children {
+        if child.Kind == svgElementNode && child.Name == "svg" {
+            return child, nil
+        }
+    }
+    return nil, fmt.Errorf("error parsing SVG: falta el elemento <svg>")
+}
+
+// attr devuelve el valor de un atributo y si existe
+func (n *svgNode) attr(name string) (string, bool) {
+    for _, a := range n.Attrs {
+        if a.Name == name {
+            return a.Value, true
+        }
+    }
+    return "", false
+}
+
+// attrValue devuelve el valor del atributo o una cadena vacía
+func (n *svgNode) attrValue(name string) string {
+    value, _ := n.attr(name)
+    return value
+}
+
+// setAttr cambia o añade un atributo conservando su posición
+func (n *svgNode) setAttr(name, value string) {
+    for i := range n.Attrs {
+        if n.Attrs[i].Name == name {
+            n.Attrs[i].Value = value
+            return
+        }
+    }
+    n.Attrs = append(n.Attrs, svgAttr{name, value})
+}
+
+// removeAttr elimina un atributo si existe
+func (n *svgNode) removeAttr(name string) {
+    for i := range n.Attrs {
+        if n.Attrs[i].Name == name {
+            n.Attrs = append(n.Attrs[:i], n.Attrs[i+1:]...)
+            return
+        }
+    }
+}
+
+// elements devuelve los hijos que son elementos
+func (n *svgNode) elements() []*svgNode {
+    var result []*svgNode
+    for _, child := range n.Children {
+        if child.Kind == svgElementNode {
+            result = append(result, child)
+        }
+    }
+    return result
+}
+
+// walk recorre el subárbol en profundidad; si visit devuelve false no se
+// visitan los hijos de ese nodo
+func (n *svgNode) walk(visit func(node *svgNode) bool) {
+    if !visit(n) {
+        return
+    }
+    for _, child := range n.Children {
+        child.walk(visit)
+    }
+}
+
+// String serializa el nodo y sus hijos
+func (n *svgNode) String() string {
+    var b strings.Builder
+    n.writeTo(&b)
+    return b.String()
+}
+
+// Escapado mínimo: xml.EscapeText también convierte saltos de línea y
+// tabuladores en &#xA; y &#x9;, y el texto dejaría de quedar como estaba
+var (
+    svgTextEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+    svgAttrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+)
+
+func (n *svgNode) writeTo(b *strings.Builder) {
+    switch n.Kind {
+    case svgTextNode:
+        b.WriteString(svgTextEscaper.Replace(n.Data))
+        return
+    case svgCommentNode:
+        b.WriteString("<!--" + n.Data + "-->")
+        return
+    case svgRawNode:
+        b.WriteString(n.Data)
+        return
+    }
+    
+    b.WriteString("<" + n.Name)
+    for _, a := range n.Attrs {
+        b.WriteString(" " + a.Name + `="`)
+        b.WriteString(svgAttrEscaper.Replace(a.Value))
+        b.WriteString(`"`)
+    }
+    if len(n.Children) == 0 {
+        b.WriteString("/>")
+        return
+    }
+    b.WriteString(">")
+    for _, child := range n.Children {
+        child.writeTo(b)
+    }
+    b.WriteString("</" + n.Name + ">")
+}
+
+// parseStyleAttribute separa las declaraciones de un atributo style
+func parseStyleAttribute(style string) []svgAttr {
+    var declarations []svgAttr
+    for _, declaration := range strings.Split(style, ";") {
+        colon := strings.IndexByte(declaration, ':')
+        if colon < 0 {
+            continue
+        }
+        name := strings.TrimSpace(declaration[:colon])
+        value := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(declaration[colon+1:]), "!important"))
+        if name != "" {
+            declarations = append(declarations, svgAttr{name, value})
+        }
+    }
+    return declarations
+}
+
+// property devuelve una propiedad de presentación del propio elemento; el
+// atributo style tiene prioridad sobre el atributo de presentación
+func (n *svgNode) property(name string) (string, bool) {
+    for _, declaration := range parseStyleAttribute(n.attrValue("style")) {
+        if declaration.Name == name {
+            return declaration.Value, true
+        }
+    }
+    value, ok := n.attr(name)
+    return strings.TrimSpace(value), ok
+}
+
+// inherit calcula el estilo heredado por el elemento
+func (s svgStyle) inherit(n *svgNode) svgStyle {
+    style := make(svgStyle, len(s)+4)
+    for k, v := range s {
+        style[k] = v
+    }
+    for name := range inheritedSvgProperties {
+        if value, ok := n.property(name); ok && value != "inherit" {
+            style[name] = value
+        }
+    }
+    return style
+}
+
+// value devuelve una propiedad o su valor inicial según SVG
+func (s svgStyle) value(name string) string {
+    if v, ok := s[name]; ok {
+        return v
+    }
+    switch name {
+    case "fill":
+        return "black"
+    case "stroke":
+        return "none"
+    case "stroke-width", "fill-opacity", "stroke-opacity":
+        return "1"
+    case "fill-rule":
+        return "nonzero"
+    case "stroke-linecap":
+        return "butt"
+    case "stroke-linejoin":
+        return "miter"
+    case "stroke-miterlimit":
+        return "4"
+    }
+    return ""
+}
+
+// number devuelve una propiedad numérica (admite porcentajes en opacidades)
+func (s svgStyle) number(name string) float64 {
+    value := s.value(name)
+    if strings.HasSuffix(value, "%") {
+        v, _ := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+        return v / 100
+    }
+    v, _ := parseLength(value)
+    return v
+}
+
+// paint resuelve fill o stroke; currentColor usa la propiedad color o, en su
+// defecto, el color de la variante
+func (s svgStyle) paint(name, variantColor string) (svgPaint, error) {
+    value := s.value(name)
+    switch {
+    case value == "none" || value == "transparent":
+        return svgPaint{None: true}, nil
+    case strings.HasPrefix(value, "url("):
+        return svgPaint{URL: strings.Trim(strings.TrimSuffix(strings.TrimPrefix(value, "url("), ")"), ` "'`)}, nil
+    case value == "currentColor":
+        value = s.value("color")
+        if value == "" || value == "currentColor" {
+            value = variantColor
+        }
+    }
+    c, err := parseColor(value)
+    if err != nil {
+        return svgPaint{}, err
+    }
+    return svgPaint{Color: color.NRGBAModel.Convert(c).(color.NRGBA)}, nil
+}
+
+// elementOpacity devuelve la opacidad propia (no heredada) del elemento
+func elementOpacity(n *svgNode) float64 {
+    value, ok := n.property("opacity")
+    if !ok {
+        return 1
+    }
+    return svgStyle{"opacity": value}.number("opacity")
+}
+
+// elementTransform devuelve la transformación propia del elemento
+func elementTransform(n *svgNode) (affine, error) {
+    value, ok := n.attr("transform")
+    if !ok {
+        return identityAffine, nil
+    }
+    return parseTransform(value)
+}
+
+// isHidden indica si el elemento no se dibuja (display:none o visibility)
+func isHidden(n *svgNode, style svgStyle) bool {
+    if display, _ := n.property("display"); display == "none" {
+        return true
+    }
+    return style.value("visibility") == "hidden" || style.value("visibility") == "collapse"
+}
+
+// documentViewBox devuelve el viewBox del documento, o su tamaño si no lo tiene
+func documentViewBox(root *svgNode) ([4]float64, error) {
+    if viewBox, ok := root.attr("viewBox"); ok {
+        return parseViewBox(viewBox)
+    }
+    width, okW := parseLength(root.attrValue("width"))
+    height, okH := parseLength(root.attrValue("height"))
+    if !okW || !okH {
+        return [4]float64{}, fmt.Errorf("el SVG no tiene viewBox ni tamaño")
+    }
+    return [4]float64{0, 0, width, height}, nil
+}
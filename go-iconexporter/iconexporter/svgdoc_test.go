@@ -0,0 +1,22 @@
+// iconexporter/svgdoc_test.go
+package iconexporter
+
+import "testing"
+
+// Serializar un documento analizado lo deja como estaba, con sus saltos de
+// línea y tabuladores, y solo escapa lo imprescindible
+func TestSvgDocumentRoundTrip(t *testing.T) {
+    tests := []string{
+        "<svg viewBox=\"0 0 24 24\">\n\t<g>\n\t\t<path d=\"M0 0h4v4z\"/>\n\t</g>\n</svg>",
+        "<svg><style>\n\tg &gt; .a { font-family: 'x' }\n</style><text title=\"a &lt; b &amp; &quot;c&quot; 'd'\">x &amp; y</text></svg>",
+    }
+    for _, svg := range tests {
+        root, err := parseSvgDocument([]byte(svg))
+        if err != nil {
+            t.Fatal(err)
+        }
+        if got := root.String(); got != svg {
+            t.Errorf("%q\nse esperaba\n%q", got, svg)
+        }
+    }
+}
@@ -0,0 +1,160 @@
+// iconexporter/svgflatten.go
+package iconexporter
+
+import (
+    "fmt"
+    "strings"
+)
+
+// flatShape es una forma del icono con las transformaciones ya aplicadas a las
+// coordenadas y el estilo resuelto; es la base de los formatos vectoriales que
+// no admiten grupos ni herencia de estilos (fuentes, código, PDF)
+type flatShape struct {
+    Element       string
+    Segments      []pathSegment
+    Fill          svgPaint
+    Stroke        svgPaint
+    FillOpacity   float64
+    StrokeOpacity float64
+    StrokeWidth   float64
+    EvenOdd       bool
+    LineCap       string
+    LineJoin      string
+    Style         svgStyle
+}
+
+// Valores de stroke-linecap y stroke-linejoin que entienden todos los formatos;
+// miter-clip y arcs se dibujan como miter
+var (
+    svgLineCaps  = map[string]string{"butt": "butt", "round": "round", "square": "square"}
+    svgLineJoins = map[string]string{"miter": "miter", "miter-clip": "miter", "arcs": "miter", "round": "round", "bevel": "bevel"}
+)
+
+// lineStyle devuelve stroke-linecap y stroke-linejoin normalizados; los valores
+// no válidos usan butt y miter con un aviso
+func (s svgStyle) lineStyle(warn func(format string, args ...interface{})) (string, string) {
+    value := s.value("stroke-linecap")
+    lineCap, ok := svgLineCaps[strings.ToLower(value)]
+    if !ok {
+        warn("stroke-linecap no válido: %s, se usa butt", value)
+        lineCap = "butt"
+    }
+    value = s.value("stroke-linejoin")
+    lineJoin, ok := svgLineJoins[strings.ToLower(value)]
+    if !ok {
+        warn("stroke-linejoin no válido: %s, se usa miter", value)
+        lineJoin = "miter"
+    }
+    return lineCap, lineJoin
+}
+
+// flattenSvg analiza el SVG y devuelve sus formas visibles en orden de pintado
+// junto con el viewBox. Lo que no se puede representar se notifica con warn
+// (una vez por mensaje) y se omite.
+func flattenSvg(data []byte, variantColor string, warn func(message string)) ([]flatShape, [4]float64, error) {
+    root, err := parseSvgDocument(data)
+    if err != nil {
+        return nil, [4]float64{}, err
+    }
+    box, err := documentViewBox(root)
+    if err != nil {
+        return nil, box, err
+    }
+    
+    warned := map[string]bool{}
+    notify := func(format string, args ...interface{}) {
+        message := fmt.Sprintf(format, args...)
+        if !warned[message] && warn != nil {
+            warned[message] = true
+            warn(message)
+        }
+    }
+    
+    var shapes []flatShape
+    var visit func(n *svgNode, parentStyle svgStyle, m affine, opacity float64)
+    visit = func(n *svgNode, parentStyle svgStyle, m affine, opacity float64) {
+        switch n.Name {
+        case "title", "desc", "metadata", "defs":
+            return
+        case "g", "a", "svg", "path", "rect", "circle", "ellipse", "line", "polyline", "polygon":
+        case "style":
+            notify("hoja de estilos <style> no soportada, se ignoran sus reglas")
+            return
+        default:
+            notify("elemento <%s> no soportado, se omite", n.Name)
+            return
+        }
+        
+        style := parentStyle.inherit(n)
+        if isHidden(n, style) {
+            return
+        }
+        for _, attr := range []string{"clip-path", "mask", "filter"} {
+            if value, ok := n.property(attr); ok && value != "none" {
+                notify("atributo %s no soportado, se ignora", attr)
+            }
+        }
+        
+        transform, err := elementTransform(n)
+        if err != nil {
+            notify("%v", err)
+            transform = identityAffine
+        }
+        m = m.multiply(transform)
+        opacity *= elementOpacity(n)
+        
+        if !isShapeElement(n.Name) {
+            for _, child := range n.elements() {
+                visit(child, style, m, opacity)
+            }
+            return
+        }
+        
+        segments, err := shapeToPath(n)
+        if err != nil {
+            notify("geometría no válida en <%s>: %v", n.Name, err)
+            return
+        }
+        if len(segments) == 0 {
+            return
+        }
+        
+        shape := flatShape{
+            Element:       n.Name,
+            Segments:      transformPath(segments, m),
+            FillOpacity:   style.number("fill-opacity") * opacity,
+            StrokeOpacity: style.number("stroke-opacity") * opacity,
+            StrokeWidth:   style.number("stroke-width") * m.scaleFactor(),
+            EvenOdd:       style.value("fill-rule") == "evenodd",
+            Style:         style,
+        }
+        for _, p := range []struct {
+            name  string
+            paint *svgPaint
+        }{{"fill", &shape.Fill}, {"stroke", &shape.Stroke}} {
+            paint, err := style.paint(p.name, variantColor)
+            switch {
+            case err != nil:
+                notify("%s: %v, se omite", p.name, err)
+                paint = svgPaint{None: true}
+            case paint.URL != "":
+                notify("%s con referencia %s (degradado o patrón) no soportado, se omite", p.name, paint.URL)
+                paint = svgPaint{None: true}
+            }
+            *p.paint = paint
+        }
+        if shape.Fill.None && shape.Stroke.None {
+            return
+        }
+        if !shape.Stroke.None {
+            shape.LineCap, shape.LineJoin = style.lineStyle(notify)
+        }
+        shapes = append(shapes, shape)
+    }
+    
+    rootStyle := svgStyle{}.inherit(root)
+    for _, child := range root.elements() {
+        visit(child, rootStyle, identityAffine, elementOpacity(root))
+    }
+    return shapes, box, nil
+}
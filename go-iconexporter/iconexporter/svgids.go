@@ -0,0 +1,166 @@
+// iconexporter/svgids.go
+package iconexporter
+
+import (
+    "fmt"
+    "regexp"
+    "strings"
+)
+
+// IDPrefixConfig antepone a los id de cada icono un prefijo propio, para que
+// los degradados, máscaras y recortes de varios iconos insertados en la misma
+// página (o en el mismo sprite) no choquen. Pattern admite {collection} e
+// {icon}; en el sprite se usa el id del <symbol>, que ya distingue colores.
+type IDPrefixConfig struct {
+    Enabled bool   `json:"enabled"`
+    Pattern string `json:"pattern"`
+}
+
+var (
+    // Referencias url(#id) en atributos, style y <style>
+    svgURLRefPattern     = regexp.MustCompile(`url\(\s*['"]?#[^'")\s]+`)
+    // Preludios de reglas CSS (el selector hasta la llave), donde #id es un id
+    // y no un color
+    cssPreludePattern    = regexp.MustCompile(`[^{};]+\{`)
+    cssIDSelectorPattern = regexp.MustCompile(`#(-?[_A-Za-z][\w-]*)`)
+)
+
+// idPrefixConfig completa la configuración con valores por defecto y la valida
+func (e *IconExporter) idPrefixConfig() (IDPrefixConfig, error) {
+    cfg := e.config.IDPrefix
+    if cfg.Pattern == "" {
+        cfg.Pattern = "{collection}-{icon}-"
+    }
+    if !strings.Contains(cfg.Pattern, "{icon}") {
+        return cfg, fmt.Errorf("el patrón de prefijo de ids debe incluir {icon}: %s", cfg.Pattern)
+    }
+    return cfg, nil
+}
+
+// svgIDPrefix devuelve el prefijo de ids del icono, o "" si está desactivado
+func (e *IconExporter) svgIDPrefix(collection, iconName string) string {
+    cfg, _ := e.idPrefixConfig() // validada en validateConfig
+    if !cfg.Enabled {
+        return ""
+    }
+    prefix := strings.ReplaceAll(cfg.Pattern, "{collection}", collection)
+    prefix = strings.ReplaceAll(prefix, "{icon}", iconName)
+    prefix = invalidSpriteIDChars.ReplaceAllString(prefix, "-")
+    
+    // Un id no puede empezar por número, guion o punto
+    if !(prefix[0] == '_' || (prefix[0]|0x20 >= 'a' && prefix[0]|0x20 <= 'z')) {
+        prefix = "_" + prefix
+    }
+    return prefix
+}
+
+// prefixSvgIds antepone el prefijo a los id del cuerpo y a sus referencias:
+// url(#id), href="#id", aria-*, los selectores #id de <style> y los valores
+// begin/end de SMIL como "id.click". Las referencias a ids que no están en el
+// cuerpo no se tocan; si el cuerpo no se puede analizar se devuelve sin cambios.
+func prefixSvgIds(body, prefix string) string {
+    if prefix == "" {
+        return body
+    }
+    root, err := parseSvgDocument([]byte(`<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink">` + body + `</svg>`))
+    if err != nil {
+        return body
+    }
+    
+    ids := map[string]bool{}
+    root.walk(func(n *svgNode) bool {
+        if id := strings.TrimSpace(n.attrValue("id")); n.Kind == svgElementNode && id != "" {
+            ids[id] = true
+        }
+        return true
+    })
+    if len(ids) == 0 {
+        return body
+    }
+    
+    root.walk(func(n *svgNode) bool {
+        if n.Kind == svgTextNode {
+            return false
+        }
+        for i, a := range n.Attrs {
+            switch a.Name {
+            case "id":
+                if id := strings.TrimSpace(a.Value); ids[id] {
+                    a.Value = prefix + id
+                }
+            case "href", "xlink:href":
+                if id, ok := strings.CutPrefix(strings.TrimSpace(a.Value), "#"); ok && ids[id] {
+                    a.Value = "#" + prefix + id
+                }
+            case "aria-labelledby", "aria-describedby":
+                refs := strings.Fields(a.Value)
+                for j, id := range refs {
+                    if ids[id] {
+                        refs[j] = prefix + id
+                    }
+                }
+                a.Value = strings.Join(refs, " ")
+            case "begin", "end":
+                a.Value = prefixSmilTimes(a.Value, prefix, ids)
+            default:
+                a.Value = prefixURLRefs(a.Value, prefix, ids)
+            }
+            n.Attrs[i] = a
+        }
+        if n.Name == "style" {
+            for _, child := range n.Children {
+                if child.Kind == svgTextNode {
+                    child.Data = prefixCSSIds(child.Data, prefix, ids)
+                }
+            }
+        }
+        return true
+    })
+    
+    var b strings.Builder
+    for _, child := range root.Children {
+        b.WriteString(child.String())
+    }
+    return b.String()
+}
+
+// prefixURLRefs reescribe las referencias url(#id) de un valor
+func prefixURLRefs(value, prefix string, ids map[string]bool) string {
+    return svgURLRefPattern.ReplaceAllStringFunc(value, func(match string) string {
+        hash := strings.IndexByte(match, '#')
+        if id := match[hash+1:]; ids[id] {
+            return match[:hash+1] + prefix + id
+        }
+        return match
+    })
+}
+
+// prefixCSSIds reescribe los selectores #id y las url(#id) de una hoja de
+// estilos. Los caracteres del prefijo que no valen en un identificador CSS
+// (como ":") se escapan.
+func prefixCSSIds(css, prefix string, ids map[string]bool) string {
+    escaped := strings.ReplaceAll(prefix, ":", `\:`)
+    css = cssPreludePattern.ReplaceAllStringFunc(css, func(prelude string) string {
+        return cssIDSelectorPattern.ReplaceAllStringFunc(prelude, func(match string) string {
+            if id := match[1:]; ids[id] {
+                return "#" + escaped + id
+            }
+            return match
+        })
+    })
+    return prefixURLRefs(css, prefix, ids)
+}
+
+// prefixSmilTimes reescribe los valores de begin/end que dependen de otro
+// elemento ("id.begin", "id.end+1s", "id.click"); el resto se conserva
+func prefixSmilTimes(value, prefix string, ids map[string]bool) string {
+    times := strings.Split(value, ";")
+    for i, item := range times {
+        trimmed := strings.TrimLeft(item, " \t\n\r")
+        dot := strings.IndexByte(trimmed, '.')
+        if dot > 0 && ids[trimmed[:dot]] {
+            times[i] = item[:len(item)-len(trimmed)] + prefix + trimmed
+        }
+    }
+    return strings.Join(times, ";")
+}
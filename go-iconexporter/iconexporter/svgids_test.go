@@ -0,0 +1,150 @@
+// iconexporter/svgids_test.go
+package iconexporter
+
+import (
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+func TestPrefixSvgIds(t *testing.T) {
+    tests := []struct {
+        name   string
+        body   string
+        prefix string
+        want   string
+    }{
+        {
+            name:   "sin prefijo",
+            body:   `<path id='a' d="M0 0"/>`,
+            prefix: "",
+            want:   `<path id='a' d="M0 0"/>`,
+        },
+        {
+            name:   "sin ids",
+            body:   `<path d="M0 0" fill="url(#externo)"/>`,
+            prefix: "p-",
+            want:   `<path d="M0 0" fill="url(#externo)"/>`,
+        },
+        {
+            name:   "url y href",
+            body:   `<linearGradient id="a"/><linearGradient id='b' xlink:href="#a"/><path fill="url(#b)" style="stroke:url( '#a' )" clip-path="url(#otro)"/>`,
+            prefix: "p-",
+            want:   `<linearGradient id="p-a"/><linearGradient id="p-b" xlink:href="#p-a"/><path fill="url(#p-b)" style="stroke:url( '#p-a' )" clip-path="url(#otro)"/>`,
+        },
+        {
+            name:   "aria y atributos parecidos",
+            body:   `<title id="t">x</title><path data-id="t" aria-labelledby="t otro"/><use href="#falta"/>`,
+            prefix: "p-",
+            want:   `<title id="p-t">x</title><path data-id="t" aria-labelledby="p-t otro"/><use href="#falta"/>`,
+        },
+        {
+            name:   "selectores de <style>",
+            body:   `<style>#a, g > #b.c { fill: #a00; stroke: url(#b) } #otro { fill: #fff }</style><path id="a"/><path id="b"/>`,
+            prefix: "p-",
+            want:   `<style>#p-a, g &gt; #p-b.c { fill: #a00; stroke: url(#p-b) } #otro { fill: #fff }</style><path id="p-a"/><path id="p-b"/>`,
+        },
+        {
+            name:   "selectores con el separador del sprite",
+            body:   `<style>@media (min-width: 10px) { #a { fill: red } }</style><path id="a"/>`,
+            prefix: "s:",
+            want:   `<style>@media (min-width: 10px) { #s\:a { fill: red } }</style><path id="s:a"/>`,
+        },
+        {
+            name:   "SMIL begin y end",
+            body:   `<animate id="a" begin="0s;b.end+1s" end="a.click; otro.end"/><animate id="b" begin="a.begin" end="indefinite"/>`,
+            prefix: "p-",
+            want:   `<animate id="p-a" begin="0s;p-b.end+1s" end="p-a.click; otro.end"/><animate id="p-b" begin="p-a.begin" end="indefinite"/>`,
+        },
+        {
+            name:   "no se puede analizar",
+            body:   `<path id="a"`,
+            prefix: "p-",
+            want:   `<path id="a"`,
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := prefixSvgIds(tt.body, tt.prefix); got != tt.want {
+                t.Fatalf("%s\nse esperaba\n%s", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestSvgIDPrefix(t *testing.T) {
+    tests := []struct {
+        pattern    string
+        collection string
+        icon       string
+        want       string
+    }{
+        {"", "nonicons", "bell", "nonicons-bell-"},
+        {"", "my coll", "bell", "my-coll-bell-"},
+        {"{icon}_", "x", "9bell", "_9bell_"},
+        {"{icon}-", "x", "-bell", "_-bell-"},
+    }
+    for _, tt := range tests {
+        e := newTestExporter(t, Config{IDPrefix: IDPrefixConfig{Enabled: true, Pattern: tt.pattern}})
+        if got := e.svgIDPrefix(tt.collection, tt.icon); got != tt.want {
+            t.Errorf("svgIDPrefix(%q, %q) con %q = %q, se esperaba %q", tt.collection, tt.icon, tt.pattern, got, tt.want)
+        }
+    }
+    if got := newTestExporter(t, Config{}).svgIDPrefix("x", "y"); got != "" {
+        t.Errorf("desactivado: %q", got)
+    }
+}
+
+func TestIDPrefixConfigErrors(t *testing.T) {
+    if _, err := NewIconExporter(Config{Collections: []string{"nonicons"}, IDPrefix: IDPrefixConfig{Pattern: "{collection}"}}); err == nil {
+        t.Fatal("se esperaba un error")
+    }
+}
+
+// El degradado sigue aplicándose tras reescribir los ids
+func TestPrefixSvgIdsRaster(t *testing.T) {
+    e := newTestExporter(t, Config{IDPrefix: IDPrefixConfig{Enabled: true}})
+    icon := Icon{
+        Body:    `<defs><linearGradient id="a"><stop offset="0" stop-color="#ff0000"/><stop offset="1" stop-color="#ff0000"/></linearGradient></defs><path d="M0 0h24v24H0z" fill="url(#a)"/>`,
+        Width:   24,
+        Height:  24,
+        ViewBox: "0 0 24 24",
+    }
+    svg := e.prepareSvgBuffer(icon, 24, 24, "#000000", e.svgIDPrefix("c", "i"))
+    if !strings.Contains(string(svg), `id="c-i-a"`) || !strings.Contains(string(svg), `url(#c-i-a)`) {
+        t.Fatalf("ids sin prefijo: %s", svg)
+    }
+    img, err := e.rasterizeSvg(svg, 24, 24)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if c := img.NRGBAAt(12, 12); c.R < 250 || c.G > 5 || c.A < 250 {
+        t.Fatalf("color %v, se esperaba rojo", c)
+    }
+}
+
+// En el sprite el prefijo es el id del símbolo seguido de ":", que no puede
+// aparecer en los ids: "a" con el id "b-c" y "a-b" con el id "c" no chocan
+func TestSpriteIDPrefixCollision(t *testing.T) {
+    e := newTestExporter(t, Config{IDPrefix: IDPrefixConfig{Enabled: true}, Sprite: SpriteConfig{Enabled: true}})
+    entries := []iconEntry{
+        {"x", "a", Icon{Body: `<mask id="b-c"/><path mask="url(#b-c)" d="M0 0"/>`, ViewBox: "0 0 24 24"}},
+        {"x", "a-b", Icon{Body: `<mask id="c"/><path mask="url(#c)" d="M0 0"/>`, ViewBox: "0 0 24 24"}},
+    }
+    for _, result := range e.exportSprite(entries, []string{"#000000"}) {
+        if result.Error != "" {
+            t.Fatal(result.Error)
+        }
+    }
+    data, err := os.ReadFile(filepath.Join(e.config.OutputDir, "sprite.svg"))
+    if err != nil {
+        t.Fatal(err)
+    }
+    sprite := string(data)
+    for _, want := range []string{`id="x-a:b-c"`, `url(#x-a:b-c)`, `id="x-a-b:c"`, `url(#x-a-b:c)`} {
+        if !strings.Contains(sprite, want) {
+            t.Errorf("falta %q en:\n%s", want, sprite)
+        }
+    }
+}
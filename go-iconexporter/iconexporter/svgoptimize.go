@@ -0,0 +1,391 @@
+// iconexporter/svgoptimize.go
+package iconexporter
+
+import (
+    "math"
+    "regexp"
+    "strconv"
+    "strings"
+)
+
+// SvgSavings es la reducción de tamaño lograda por el optimizador SVG
+type SvgSavings struct {
+    OriginalBytes  int     `json:"originalBytes"`
+    OptimizedBytes int     `json:"optimizedBytes"`
+    Percent        float64 `json:"percent"` // porcentaje ahorrado, con un decimal
+}
+
+// newSvgSavings calcula el ahorro entre el tamaño original y el optimizado
+func newSvgSavings(original, optimized int) *SvgSavings {
+    savings := &SvgSavings{OriginalBytes: original, OptimizedBytes: optimized}
+    if original > 0 {
+        savings.Percent = math.Round(float64(original-optimized)*1000/float64(original)) / 10
+    }
+    return savings
+}
+
+// Espacios de nombres de editores que no afectan al dibujo
+var editorNamespaces = []string{"sodipodi", "inkscape", "sketch", "serif", "dc", "cc", "rdf"}
+
+// Atributos numéricos simples que se redondean
+var numericSvgAttributes = map[string]bool{
+    "x": true, "y": true, "width": true, "height": true,
+    "cx": true, "cy": true, "r": true, "rx": true, "ry": true, "fx": true, "fy": true,
+    "x1": true, "y1": true, "x2": true, "y2": true,
+    "opacity": true, "fill-opacity": true, "stroke-opacity": true, "stop-opacity": true,
+    "stroke-width": true, "stroke-miterlimit": true, "stroke-dashoffset": true, "offset": true,
+}
+
+// Atributos con listas de números (o funciones con números) que se redondean
+var numberListSvgAttributes = map[string]bool{
+    "viewBox": true, "points": true, "transform": true, "gradientTransform": true,
+    "patternTransform": true, "stroke-dasharray": true,
+}
+
+// Valores iniciales de las propiedades de presentación; las heredables solo se
+// quitan si ningún antecesor las cambia
+var defaultSvgProperties = map[string]string{
+    "fill":              "black",
+    "fill-opacity":      "1",
+    "fill-rule":         "nonzero",
+    "stroke":            "none",
+    "stroke-width":      "1",
+    "stroke-opacity":    "1",
+    "stroke-linecap":    "butt",
+    "stroke-linejoin":   "miter",
+    "stroke-miterlimit": "4",
+    "stroke-dasharray":  "none",
+    "opacity":           "1",
+    "visibility":        "visible",
+    "display":           "inline",
+}
+
+// Posiciones que valen 0 por defecto según el elemento
+var defaultZeroAttributes = map[string][]string{
+    "rect":    {"x", "y"},
+    "circle":  {"cx", "cy"},
+    "ellipse": {"cx", "cy"},
+    "line":    {"x1", "y1", "x2", "y2"},
+    "use":     {"x", "y"},
+    "image":   {"x", "y"},
+}
+
+// Elementos cuyo texto forma parte del contenido
+var svgTextContainers = map[string]bool{"text": true, "tspan": true, "textPath": true, "style": true, "title": true, "desc": true}
+
+var svgNumberPattern = regexp.MustCompile(`[-+]?(\d+\.?\d*|\.\d+)([eE][-+]?\d+)?`)
+
+// optimizeSvg reescribe el SVG con menos bytes sin cambiar su dibujo: quita
+// metadatos, comentarios y atributos por defecto, deshace grupos innecesarios,
+// redondea los números a la precisión indicada (-1 = sin redondear) y acorta
+// los trayectos con comandos relativos, H/V y comandos implícitos
+func optimizeSvg(data []byte, precision int) ([]byte, error) {
+    root, err := parseSvgDocument(data)
+    if err != nil {
+        return nil, err
+    }
+    removeSvgMetadata(root)
+    removeDefaultAttributes(root, nil)
+    roundSvgNumbers(root, precision)
+    collapseSvgGroups(root)
+    
+    // xmlns:xlink sobra si ya no queda ningún atributo xlink:
+    usesXlink := false
+    root.walk(func(n *svgNode) bool {
+        for _, a := range n.Attrs {
+            usesXlink = usesXlink || strings.HasPrefix(a.Name, "xlink:")
+        }
+        return true
+    })
+    if !usesXlink {
+        root.removeAttr("xmlns:xlink")
+    }
+    root.removeAttr("version")
+    return []byte(root.String()), nil
+}
+
+// isEditorName indica si el nombre pertenece a un espacio de nombres de editor
+func isEditorName(name string) bool {
+    for _, prefix := range editorNamespaces {
+        if strings.HasPrefix(name, prefix+":") || name == "xmlns:"+prefix {
+            return true
+        }
+    }
+    return false
+}
+
+// removeSvgMetadata quita comentarios, directivas, <metadata>, elementos y
+// atributos de editores y el texto que solo es espacio entre elementos
+func removeSvgMetadata(n *svgNode) {
+    attrs := n.Attrs[:0]
+    for _, a := range n.Attrs {
+        if !isEditorName(a.Name) {
+            attrs = append(attrs, a)
+        }
+    }
+    n.Attrs = attrs
+    
+    children := n.Children[:0]
+    for _, child := range n.Children {
+        switch child.Kind {
+        case svgCommentNode, svgRawNode:
+            continue
+        case svgTextNode:
+            if !svgTextContainers[n.Name] && strings.TrimSpace(child.Data) == "" {
+                continue
+            }
+        case svgElementNode:
+            if child.Name == "metadata" || isEditorName(child.Name) {
+                continue
+            }
+            removeSvgMetadata(child)
+        }
+        children = append(children, child)
+    }
+    n.Children = children
+}
+
+// removeDefaultAttributes quita los atributos que repiten el valor inicial
+func removeDefaultAttributes(n *svgNode, inherited svgStyle) {
+    for name, initial := range defaultSvgProperties {
+        value, ok := n.attr(name)
+        if !ok || strings.TrimSpace(value) != initial {
+            continue
+        }
+        if inheritedSvgProperties[name] && inherited.value(name) != initial {
+            continue
+        }
+        n.removeAttr(name)
+    }
+    for _, name := range defaultZeroAttributes[n.Name] {
+        if v, ok := parseLength(n.attrValue(name)); ok && v == 0 {
+            n.removeAttr(name)
+        }
+    }
+    if value, ok := n.attr("style"); ok && strings.TrimSpace(value) == "" {
+        n.removeAttr("style")
+    }
+    
+    style := inherited.inherit(n)
+    for _, child := range n.elements() {
+        removeDefaultAttributes(child, style)
+    }
+}
+
+// shortNumber escribe el número con la precisión indicada y sin el cero inicial
+func shortNumber(v float64, precision int) string {
+    s := formatNumber(v, precision)
+    if strings.HasPrefix(s, "0.") {
+        return s[1:]
+    }
+    if strings.HasPrefix(s, "-0.") {
+        return "-" + s[2:]
+    }
+    return s
+}
+
+// roundSvgNumbers redondea atributos numéricos y acorta los trayectos
+func roundSvgNumbers(root *svgNode, precision int) {
+    root.walk(func(n *svgNode) bool {
+        if n.Kind != svgElementNode {
+            return false
+        }
+        for i, a := range n.Attrs {
+            switch {
+            case a.Name == "d" && n.Name == "path":
+                if segments, err := parsePathData(a.Value); err == nil {
+                    // S y T se reescriben como C y Q; si así queda más largo se conserva
+                    if d := shortPathData(segments, precision); len(d) <= len(a.Value) {
+                        n.Attrs[i].Value = d
+                    }
+                }
+            case numericSvgAttributes[a.Name]:
+                if v, err := strconv.ParseFloat(strings.TrimSpace(a.Value), 64); err == nil {
+                    n.Attrs[i].Value = shortNumber(v, precision)
+                }
+            case numberListSvgAttributes[a.Name]:
+                n.Attrs[i].Value = svgNumberPattern.ReplaceAllStringFunc(a.Value, func(s string) string {
+                    v, _ := strconv.ParseFloat(s, 64)
+                    return shortNumber(v, precision)
+                })
+            }
+        }
+        return true
+    })
+}
+
+// shortPathData serializa el trayecto eligiendo en cada segmento la forma más
+// corta entre absoluta y relativa. Las coordenadas relativas se calculan desde
+// la posición ya redondeada para que el error no se acumule.
+func shortPathData(segments []pathSegment, precision int) string {
+    round := func(v float64) float64 {
+        if precision < 0 {
+            return v
+        }
+        scale := math.Pow(10, float64(precision))
+        return math.Round(v*scale) / scale
+    }
+    
+    var b strings.Builder
+    var last byte   // último comando escrito, para omitir los repetidos
+    var prev string  // último número escrito, para decidir el separador
+    var x, y, startX, startY float64
+    
+    // join escribe números sin separador cuando el signo o el punto bastan
+    join := func(out *strings.Builder, numbers []string, after string) {
+        for _, s := range numbers {
+            if after != "" && !strings.HasPrefix(s, "-") &&
+                !(strings.HasPrefix(s, ".") && strings.ContainsAny(after, ".e")) {
+                out.WriteByte(' ')
+            }
+            out.WriteString(s)
+            after = s
+        }
+    }
+    
+    // variant escribe una forma del segmento y devuelve el texto y el último número
+    variant := func(cmd byte, args []float64) (string, string) {
+        numbers := make([]string, len(args))
+        for i, v := range args {
+            numbers[i] = shortNumber(v, precision)
+        }
+        var out strings.Builder
+        after := prev
+        if cmd != last || cmd == 'M' || cmd == 'm' {
+            out.WriteByte(cmd)
+            after = ""
+        }
+        join(&out, numbers, after)
+        if len(numbers) == 0 {
+            return out.String(), prev
+        }
+        return out.String(), numbers[len(numbers)-1]
+    }
+    
+    for _, seg := range segments {
+        a := seg.Args
+        var absCmd, relCmd byte
+        var absArgs, relArgs []float64
+        var absX, absY, relX, relY float64 // posición tras cada forma
+        
+        switch seg.Cmd {
+        case 'M', 'L':
+            dx, dy := round(a[0]-x), round(a[1]-y)
+            absCmd, relCmd = seg.Cmd, seg.Cmd|0x20
+            absArgs, relArgs = []float64{round(a[0]), round(a[1])}, []float64{dx, dy}
+            absX, absY, relX, relY = round(a[0]), round(a[1]), x+dx, y+dy
+            if seg.Cmd == 'L' && dy == 0 {
+                absCmd, relCmd = 'H', 'h'
+                absArgs, relArgs = absArgs[:1], relArgs[:1]
+                absY, relY = y, y
+            } else if seg.Cmd == 'L' && dx == 0 {
+                absCmd, relCmd = 'V', 'v'
+                absArgs, relArgs = absArgs[1:], relArgs[1:]
+                absX, relX = x, x
+            }
+        case 'C', 'Q':
+            absCmd, relCmd = seg.Cmd, seg.Cmd|0x20
+            for i := 0; i < len(a); i += 2 {
+                absArgs = append(absArgs, round(a[i]), round(a[i+1]))
+                relArgs = append(relArgs, round(a[i]-x), round(a[i+1]-y))
+            }
+            n := len(a)
+            absX, absY, relX, relY = absArgs[n-2], absArgs[n-1], x+relArgs[n-2], y+relArgs[n-1]
+        case 'A':
+            absCmd, relCmd = 'A', 'a'
+            head := []float64{round(a[0]), round(a[1]), round(a[2]), a[3], a[4]}
+            dx, dy := round(a[5]-x), round(a[6]-y)
+            absArgs = append(append([]float64{}, head...), round(a[5]), round(a[6]))
+            relArgs = append(append([]float64{}, head...), dx, dy)
+            absX, absY, relX, relY = round(a[5]), round(a[6]), x+dx, y+dy
+        case 'Z':
+            if last == 'z' {
+                continue
+            }
+            b.WriteByte('z')
+            last, prev = 'z', ""
+            x, y = startX, startY
+            continue
+        }
+        
+        absText, absLast := variant(absCmd, absArgs)
+        relText, relLast := variant(relCmd, relArgs)
+        if len(absText) < len(relText) {
+            b.WriteString(absText)
+            last, prev, x, y = absCmd, absLast, absX, absY
+        } else {
+            b.WriteString(relText)
+            last, prev, x, y = relCmd, relLast, relX, relY
+        }
+        
+        // Tras M/m los pares implícitos serían L/l; se fuerza a repetir el comando
+        if seg.Cmd == 'M' {
+            last = 0
+            startX, startY = x, y
+        }
+    }
+    return b.String()
+}
+
+// collapseSvgGroups quita grupos vacíos y sin atributos, y pasa los atributos
+// de un grupo con un solo hijo a ese hijo
+func collapseSvgGroups(n *svgNode) {
+    var children []*svgNode
+    for _, child := range n.Children {
+        if child.Kind != svgElementNode {
+            children = append(children, child)
+            continue
+        }
+        collapseSvgGroups(child)
+        if child.Name != "g" && child.Name != "defs" {
+            children = append(children, child)
+            continue
+        }
+        
+        if len(child.Children) == 0 {
+            continue
+        }
+        if child.Name == "g" && mergeGroupIntoChild(child) {
+            children = append(children, child.Children...)
+            continue
+        }
+        children = append(children, child)
+    }
+    n.Children = children
+}
+
+// mergeGroupIntoChild pasa los atributos del grupo a su hijo cuando es posible
+// y devuelve si el grupo puede sustituirse por sus hijos
+func mergeGroupIntoChild(g *svgNode) bool {
+    if len(g.Attrs) == 0 {
+        return true
+    }
+    elements := g.elements()
+    if len(elements) != 1 || len(g.Children) != 1 {
+        return false
+    }
+    child := elements[0]
+    if _, ok := child.attr("id"); ok {
+        return false
+    }
+    for _, a := range g.Attrs {
+        movable := a.Name == "transform" || a.Name == "opacity" || inheritedSvgProperties[a.Name] ||
+            defaultSvgProperties[a.Name] != ""
+        if !movable {
+            return false
+        }
+        if _, ok := child.property(a.Name); ok && a.Name != "transform" {
+            return false
+        }
+    }
+    for _, a := range g.Attrs {
+        if a.Name == "transform" {
+            if own, ok := child.attr("transform"); ok {
+                child.setAttr("transform", a.Value+" "+own)
+                continue
+            }
+        }
+        child.setAttr(a.Name, a.Value)
+    }
+    return true
+}
@@ -0,0 +1,231 @@
+// iconexporter/svgoptimize_test.go
+package iconexporter
+
+import (
+    "math"
+    "os"
+    "strings"
+    "testing"
+)
+
+func TestShortNumber(t *testing.T) {
+    tests := []struct {
+        value     float64
+        precision int
+        want      string
+    }{
+        {0.5, 3, ".5"},
+        {-0.25, 3, "-.25"},
+        {2.00001, 3, "2"},
+        {10.123456, 3, "10.123"},
+        {10.123456, 0, "10"},
+        {-0.0001, 3, "0"},
+    }
+    for _, tt := range tests {
+        if got := shortNumber(tt.value, tt.precision); got != tt.want {
+            t.Errorf("shortNumber(%v, %d) = %q, se esperaba %q", tt.value, tt.precision, got, tt.want)
+        }
+    }
+}
+
+func TestShortPathData(t *testing.T) {
+    tests := []struct {
+        name      string
+        d         string
+        precision int
+        want      string
+    }{
+        {"horizontal y vertical", "M2 2L10 2L10 10Z", 3, "m2 2h8v8z"},
+        {"redondeo", "M2.00001 2.00001L10.123456 2.00001", 3, "m2 2h8.123"},
+        {"arco", "M20 20A2 2 0 1 0 21.5 21.5", 3, "m20 20a2 2 0 1 0 1.5 1.5"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            segments, err := parsePathData(tt.d)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if got := shortPathData(segments, tt.precision); got != tt.want {
+                t.Fatalf("%q, se esperaba %q", got, tt.want)
+            }
+        })
+    }
+}
+
+// Las coordenadas relativas parten de la posición redondeada: el error no se
+// acumula a lo largo del trayecto
+func TestShortPathDataNoDrift(t *testing.T) {
+    var b strings.Builder
+    b.WriteString("M0 0")
+    for i := 1; i <= 50; i++ {
+        b.WriteString(" L" + formatNumber(float64(i)*0.3337, 4) + " " + formatNumber(float64(i%3)*0.1111, 4))
+    }
+    original, err := parsePathData(b.String())
+    if err != nil {
+        t.Fatal(err)
+    }
+    short, err := parsePathData(shortPathData(original, 2))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(short) != len(original) {
+        t.Fatalf("%d segmentos, se esperaban %d", len(short), len(original))
+    }
+    for i := range original {
+        a, b := original[i].Args, short[i].Args
+        for j := range a {
+            if math.Abs(a[j]-b[j]) > 0.005+1e-9 {
+                t.Fatalf("segmento %d: %v, se esperaba %v", i, b, a)
+            }
+        }
+    }
+}
+
+// optimizeSvg reduce el tamaño sin cambiar el dibujo: se rasterizan el original
+// y el optimizado y se comparan píxel a píxel
+func TestOptimizeSvg(t *testing.T) {
+    tests := []struct {
+        name    string
+        svg     string
+        removed []string
+        kept    []string
+    }{
+        {
+            name: "metadatos de editor",
+            svg: `<?xml version="1.0"?><!-- Inkscape --><svg xmlns="http://www.w3.org/2000/svg" xmlns:inkscape="http://www.inkscape.org/namespaces/inkscape" version="1.1" viewBox="0 0 24 24" width="24" height="24" inkscape:version="1.3">` +
+                `<metadata><title>x</title></metadata><path d="M2 2h20v20H2z" inkscape:label="fondo"/></svg>`,
+            removed: []string{"<!--", "<metadata", "inkscape", "version="},
+            kept:    []string{"viewBox"},
+        },
+        {
+            name: "grupos y atributos por defecto",
+            svg: string(testSvg(`<g><g fill="#ff0000" transform="translate(0.5 0.5)"><path d="M 2 2 L 10 2 L 10 10 Z" fill-rule="nonzero" opacity="1"/></g></g><g/>` +
+                `<rect x="0" y="0" width="4" height="4" fill="black" stroke="none"/>`)),
+            removed: []string{"<g", "fill-rule", "opacity", `x="0"`, `fill="black"`, `stroke="none"`},
+            kept:    []string{`transform="translate(.5 .5)"`, `fill="#ff0000"`},
+        },
+        {
+            name:    "precisión",
+            svg:     string(testSvg(`<path d="M 2.00001 2.00001 L 10.123456 2.00001 L 10.123456 10.5 C 10.5 12.5 8.333333 14.25 4.0 14.0 Z"/><circle cx="20.00004" cy="10" r="1.50000"/>`)),
+            removed: []string{"10.123456", "8.333333", "2.00001", "20.00004", "1.50000"},
+            kept:    []string{`r="1.5"`},
+        },
+        {
+            name:    "herencia",
+            svg:     string(testSvg(`<g fill="blue"><rect x="18" y="1" width="4" height="4" fill="black"/><circle cx="20" cy="10" r="1.5"/></g>`)),
+            kept:    []string{`fill="black"`, `fill="blue"`},
+        },
+        {
+            name:    "xlink sin usar",
+            svg:     string(testSvg(`<path d="M20 20a2 2 0 1 0 1.5 1.5"/>`)),
+            removed: []string{"xmlns:xlink"},
+        },
+    }
+    e := newTestExporter(t, Config{})
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            optimized, err := optimizeSvg([]byte(tt.svg), 3)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if len(optimized) >= len(tt.svg) {
+                t.Errorf("%d bytes, el original tiene %d", len(optimized), len(tt.svg))
+            }
+            for _, s := range tt.removed {
+                if strings.Contains(string(optimized), s) {
+                    t.Errorf("sobra %q en %s", s, optimized)
+                }
+            }
+            for _, s := range tt.kept {
+                if !strings.Contains(string(optimized), s) {
+                    t.Errorf("falta %q en %s", s, optimized)
+                }
+            }
+            
+            before, err := e.rasterizeSvg([]byte(tt.svg), 96, 96)
+            if err != nil {
+                t.Fatal(err)
+            }
+            after, err := e.rasterizeSvg(optimized, 96, 96)
+            if err != nil {
+                t.Fatal(err)
+            }
+            // oksvg trunca las coordenadas a 1/64 de unidad: al sumar las
+            // relativas queda 3.999… en vez de 4 y el vértice se mueve 1/16
+            // de píxel a 96 px, lo que en un borde empinado cambia la
+            // cobertura hasta unos 24 niveles
+            if diff := pixelDiff(before, after, 32); diff > 0 {
+                t.Fatalf("%d píxeles distintos tras optimizar:\n%s", diff, optimized)
+            }
+        })
+    }
+}
+
+// Los iconos de ejemplo se dibujan igual tras optimizarlos a 2 decimales
+func TestOptimizeSampleIcons(t *testing.T) {
+    e := newTestExporter(t, Config{})
+    for _, collection := range e.config.Collections {
+        data, err := e.loadCollectionData(collection)
+        if err != nil {
+            t.Fatal(err)
+        }
+        for name, icon := range data.Icons {
+            t.Run(collection+":"+name, func(t *testing.T) {
+                svg := e.prepareSvgBuffer(icon, 96, 96, "#000000", "")
+                optimized, err := optimizeSvg(svg, 2)
+                if err != nil {
+                    t.Fatal(err)
+                }
+                before, err := e.rasterizeSvg(svg, 96, 96)
+                if err != nil {
+                    t.Fatal(err)
+                }
+                after, err := e.rasterizeSvg(optimized, 96, 96)
+                if err != nil {
+                    t.Fatal(err)
+                }
+                if diff := pixelDiff(before, after, 32); diff > 0 {
+                    t.Fatalf("%d píxeles distintos tras optimizar:\n%s", diff, optimized)
+                }
+            })
+        }
+    }
+}
+
+func TestNewSvgSavings(t *testing.T) {
+    savings := newSvgSavings(1000, 667)
+    if savings.Percent != 33.3 {
+        t.Fatalf("%v%%, se esperaba 33.3%%", savings.Percent)
+    }
+    if savings := newSvgSavings(0, 0); savings.Percent != 0 {
+        t.Fatalf("%v%% sin original", savings.Percent)
+    }
+}
+
+// El formato svg con la opción optimize informa del ahorro en el resultado
+func TestExportOptimizedSvg(t *testing.T) {
+    e := newTestExporter(t, Config{
+        OutputFormats: []string{"svg"},
+        FormatOptions: map[string]map[string]interface{}{"svg": {"optimize": true, "precision": float64(1)}},
+    })
+    summary, err := e.ExportWithVariants([][2]int{{24, 24}}, nil)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(summary.Results) != 2 {
+        t.Fatalf("resultados inesperados: %+v", summary.Results)
+    }
+    for _, result := range summary.Results {
+        savings := result.Savings
+        if savings == nil || savings.OptimizedBytes > savings.OriginalBytes {
+            t.Fatalf("%s: ahorro inesperado: %+v", result.Path, savings)
+        }
+        data, err := os.ReadFile(result.Path)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if len(data) != savings.OptimizedBytes {
+            t.Fatalf("%s: %d bytes, el resultado indica %d", result.Path, len(data), savings.OptimizedBytes)
+        }
+    }
+}
@@ -0,0 +1,584 @@
+// iconexporter/svgpath.go
+package iconexporter
+
+import (
+    "fmt"
+    "math"
+    "strconv"
+    "strings"
+)
+
+// pathSegment es un comando de trayecto en coordenadas absolutas.
+// Solo se usan M, L, C, Q, A y Z: H/V se convierten en L, S en C y T en Q.
+type pathSegment struct {
+    Cmd  byte
+    Args []float64
+}
+
+// affine es una matriz [a b c d e f] como la de transform="matrix(...)"
+type affine [6]float64
+
+var identityAffine = affine{1, 0, 0, 1, 0, 0}
+
+// pathScanner lee números y comandos de un atributo d
+type pathScanner struct {
+    s   string
+    pos int
+}
+
+func (p *pathScanner) skipSeparators() {
+    for p.pos < len(p.s) && strings.IndexByte(" \t\r\n,", p.s[p.pos]) >= 0 {
+        p.pos++
+    }
+}
+
+func (p *pathScanner) number() (float64, error) {
+    p.skipSeparators()
+    start := p.pos
+    if p.pos < len(p.s) && (p.s[p.pos] == '+' || p.s[p.pos] == '-') {
+        p.pos++
+    }
+    digits, dot := 0, false
+    for p.pos < len(p.s) {
+        c := p.s[p.pos]
+        if c >= '0' && c <= '9' {
+            digits++
+        } else if c == '.' && !dot {
+            dot = true
+        } else {
+            break
+        }
+        p.pos++
+    }
+    if digits > 0 && p.pos < len(p.s) && (p.s[p.pos] == 'e' || p.s[p.pos] == 'E') {
+        exp := p.pos + 1
+        if exp < len(p.s) && (p.s[exp] == '+' || p.s[exp] == '-') {
+            exp++
+        }
+        if exp < len(p.s) && p.s[exp] >= '0' && p.s[exp] <= '9' {
+            p.pos = exp
+            for p.pos < len(p.s) && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+                p.pos++
+            }
+        }
+    }
+    if digits == 0 {
+        p.pos = start
+        return 0, fmt.Errorf("número esperado en la posición %d de %q", start, p.s)
+    }
+    return strconv.ParseFloat(p.s[start:p.pos], 64)
+}
+
+// flag lee una bandera de arco, que puede ir pegada al siguiente número
+func (p *pathScanner) flag() (float64, error) {
+    p.skipSeparators()
+    if p.pos < len(p.s) && (p.s[p.pos] == '0' || p.s[p.pos] == '1') {
+        p.pos++
+        return float64(p.s[p.pos-1] - '0'), nil
+    }
+    return 0, fmt.Errorf("bandera de arco esperada en la posición %d de %q", p.pos, p.s)
+}
+
+func (p *pathScanner) numbers(n int) ([]float64, error) {
+    values := make([]float64, n)
+    for i := range values {
+        v, err := p.number()
+        if err != nil {
+            return nil, err
+        }
+        values[i] = v
+    }
+    return values, nil
+}
+
+// parsePathData convierte el atributo d en segmentos absolutos normalizados
+func parsePathData(d string) ([]pathSegment, error) {
+    p := &pathScanner{s: d}
+    var segments []pathSegment
+    var cx, cy, startX, startY float64
+    var ctrlX, ctrlY float64
+    var cmd, prev byte
+    
+    for {
+        p.skipSeparators()
+        if p.pos >= len(p.s) {
+            break
+        }
+        if c := p.s[p.pos]; strings.IndexByte("MmLlHhVvCcSsQqTtAaZz", c) >= 0 {
+            cmd = c
+            p.pos++
+        } else if cmd == 0 || cmd == 'Z' || cmd == 'z' {
+            return nil, fmt.Errorf("comando esperado en la posición %d de %q", p.pos, p.s)
+        }
+        
+        rel := cmd >= 'a'
+        offX, offY := 0.0, 0.0
+        if rel {
+            offX, offY = cx, cy
+        }
+        
+        switch cmd | 0x20 {
+        case 'm':
+            v, err := p.numbers(2)
+            if err != nil {
+                return nil, err
+            }
+            cx, cy = v[0]+offX, v[1]+offY
+            startX, startY = cx, cy
+            segments = append(segments, pathSegment{'M', []float64{cx, cy}})
+            // Los pares siguientes son líneas implícitas
+            if rel {
+                cmd = 'l'
+            } else {
+                cmd = 'L'
+            }
+            prev = 'M'
+            continue
+        case 'l':
+            v, err := p.numbers(2)
+            if err != nil {
+                return nil, err
+            }
+            cx, cy = v[0]+offX, v[1]+offY
+            segments = append(segments, pathSegment{'L', []float64{cx, cy}})
+        case 'h':
+            v, err := p.number()
+            if err != nil {
+                return nil, err
+            }
+            cx = v + offX
+            segments = append(segments, pathSegment{'L', []float64{cx, cy}})
+        case 'v':
+            v, err := p.number()
+            if err != nil {
+                return nil, err
+            }
+            cy = v + offY
+            segments = append(segments, pathSegment{'L', []float64{cx, cy}})
+        case 'c', 's':
+            var x1, y1 float64
+            if cmd|0x20 == 'c' {
+                v, err := p.numbers(2)
+                if err != nil {
+                    return nil, err
+                }
+                x1, y1 = v[0]+offX, v[1]+offY
+            } else if prev == 'C' {
+                x1, y1 = 2*cx-ctrlX, 2*cy-ctrlY
+            } else {
+                x1, y1 = cx, cy
+            }
+            v, err := p.numbers(4)
+            if err != nil {
+                return nil, err
+            }
+            ctrlX, ctrlY = v[0]+offX, v[1]+offY
+            cx, cy = v[2]+offX, v[3]+offY
+            segments = append(segments, pathSegment{'C', []float64{x1, y1, ctrlX, ctrlY, cx, cy}})
+            prev = 'C'
+            continue
+        case 'q', 't':
+            if cmd|0x20 == 'q' {
+                v, err := p.numbers(2)
+                if err != nil {
+                    return nil, err
+                }
+                ctrlX, ctrlY = v[0]+offX, v[1]+offY
+            } else if prev == 'Q' {
+                ctrlX, ctrlY = 2*cx-ctrlX, 2*cy-ctrlY
+            } else {
+                ctrlX, ctrlY = cx, cy
+            }
+            v, err := p.numbers(2)
+            if err != nil {
+                return nil, err
+            }
+            cx, cy = v[0]+offX, v[1]+offY
+            segments = append(segments, pathSegment{'Q', []float64{ctrlX, ctrlY, cx, cy}})
+            prev = 'Q'
+            continue
+        case 'a':
+            v, err := p.numbers(3)
+            if err != nil {
+                return nil, err
+            }
+            large, err := p.flag()
+            if err != nil {
+                return nil, err
+            }
+            sweep, err := p.flag()
+            if err != nil {
+                return nil, err
+            }
+            end, err := p.numbers(2)
+            if err != nil {
+                return nil, err
+            }
+            cx, cy = end[0]+offX, end[1]+offY
+            segments = append(segments, pathSegment{'A', []float64{v[0], v[1], v[2], large, sweep, cx, cy}})
+        case 'z':
+            cx, cy = startX, startY
+            segments = append(segments, pathSegment{'Z', nil})
+        }
+        prev = cmd &^ 0x20
+    }
+    
+    if len(segments) > 0 && segments[0].Cmd != 'M' {
+        return nil, fmt.Errorf("el trayecto debe empezar con M: %q", d)
+    }
+    return segments, nil
+}
+
+// formatNumber escribe un número con la precisión indicada (-1 = sin redondear)
+func formatNumber(v float64, precision int) string {
+    if precision >= 0 {
+        scale := math.Pow(10, float64(precision))
+        v = math.Round(v*scale) / scale
+    }
+    if v == 0 {
+        return "0"
+    }
+    return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// formatPathData serializa los segmentos como atributo d absoluto
+func formatPathData(segments []pathSegment, precision int) string {
+    var b strings.Builder
+    for _, seg := range segments {
+        b.WriteByte(seg.Cmd)
+        for i, v := range seg.Args {
+            if i > 0 {
+                b.WriteByte(' ')
+            }
+            b.WriteString(formatNumber(v, precision))
+        }
+    }
+    return b.String()
+}
+
+// parseLength interpreta una longitud en unidades de usuario ("px" opcional)
+func parseLength(value string) (float64, bool) {
+    value = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value), "px"))
+    if value == "" {
+        return 0, false
+    }
+    v, err := strconv.ParseFloat(value, 64)
+    return v, err == nil
+}
+
+// shapeToPath devuelve los segmentos de path y de las formas básicas
+func shapeToPath(n *svgNode) ([]pathSegment, error) {
+    num := func(name string) float64 {
+        v, _ := parseLength(n.attrValue(name))
+        return v
+    }
+    
+    switch n.Name {
+    case "path":
+        return parsePathData(n.attrValue("d"))
+    case "rect":
+        x, y, w, h := num("x"), num("y"), num("width"), num("height")
+        if w <= 0 || h <= 0 {
+            return nil, nil
+        }
+        rx, hasRx := parseLength(n.attrValue("rx"))
+        ry, hasRy := parseLength(n.attrValue("ry"))
+        if !hasRx {
+            rx = ry
+        }
+        if !hasRy {
+            ry = rx
+        }
+        rx, ry = math.Min(math.Max(rx, 0), w/2), math.Min(math.Max(ry, 0), h/2)
+        if rx == 0 || ry == 0 {
+            return []pathSegment{
+                {'M', []float64{x, y}},
+                {'L', []float64{x + w, y}},
+                {'L', []float64{x + w, y + h}},
+                {'L', []float64{x, y + h}},
+                {'Z', nil},
+            }, nil
+        }
+        return []pathSegment{
+            {'M', []float64{x + rx, y}},
+            {'L', []float64{x + w - rx, y}},
+            {'A', []float64{rx, ry, 0, 0, 1, x + w, y + ry}},
+            {'L', []float64{x + w, y + h - ry}},
+            {'A', []float64{rx, ry, 0, 0, 1, x + w - rx, y + h}},
+            {'L', []float64{x + rx, y + h}},
+            {'A', []float64{rx, ry, 0, 0, 1, x, y + h - ry}},
+            {'L', []float64{x, y + ry}},
+            {'A', []float64{rx, ry, 0, 0, 1, x + rx, y}},
+            {'Z', nil},
+        }, nil
+    case "circle", "ellipse":
+        cx, cy := num("cx"), num("cy")
+        rx, ry := num("rx"), num("ry")
+        if n.Name == "circle" {
+            rx, ry = num("r"), num("r")
+        }
+        if rx <= 0 || ry <= 0 {
+            return nil, nil
+        }
+        return []pathSegment{
+            {'M', []float64{cx - rx, cy}},
+            {'A', []float64{rx, ry, 0, 1, 0, cx + rx, cy}},
+            {'A', []float64{rx, ry, 0, 1, 0, cx - rx, cy}},
+            {'Z', nil},
+        }, nil
+    case "line":
+        return []pathSegment{
+            {'M', []float64{num("x1"), num("y1")}},
+            {'L', []float64{num("x2"), num("y2")}},
+        }, nil
+    case "polyline", "polygon":
+        p := &pathScanner{s: n.attrValue("points")}
+        var segments []pathSegment
+        for {
+            p.skipSeparators()
+            if p.pos >= len(p.s) {
+                break
+            }
+            v, err := p.numbers(2)
+            if err != nil {
+                return nil, err
+            }
+            cmd := byte('L')
+            if len(segments) == 0 {
+                cmd = 'M'
+            }
+            segments = append(segments, pathSegment{cmd, v})
+        }
+        if n.Name == "polygon" && len(segments) > 0 {
+            segments = append(segments, pathSegment{'Z', nil})
+        }
+        return segments, nil
+    }
+    return nil, fmt.Errorf("elemento sin geometría: <%s>", n.Name)
+}
+
+// isShapeElement indica si el elemento produce geometría
+func isShapeElement(name string) bool {
+    switch name {
+    case "path", "rect", "circle", "ellipse", "line", "polyline", "polygon":
+        return true
+    }
+    return false
+}
+
+// multiply devuelve m × n (primero se aplica n, después m)
+func (m affine) multiply(n affine) affine {
+    return affine{
+        m[0]*n[0] + m[2]*n[1],
+        m[1]*n[0] + m[3]*n[1],
+        m[0]*n[2] + m[2]*n[3],
+        m[1]*n[2] + m[3]*n[3],
+        m[0]*n[4] + m[2]*n[5] + m[4],
+        m[1]*n[4] + m[3]*n[5] + m[5],
+    }
+}
+
+func (m affine) apply(x, y float64) (float64, float64) {
+    return m[0]*x + m[2]*y + m[4], m[1]*x + m[3]*y + m[5]
+}
+
+func (m affine) isIdentity() bool {
+    return m == identityAffine
+}
+
+// scaleFactor es la escala media, útil para transformar grosores de trazo
+func (m affine) scaleFactor() float64 {
+    return math.Sqrt(math.Abs(m[0]*m[3] - m[1]*m[2]))
+}
+
+// decompose separa la matriz en traslación, rotación (grados) y escala; falla si
+// la matriz tiene sesgo
+func (m affine) decompose() (tx, ty, rotation, sx, sy float64, ok bool) {
+    sx = math.Hypot(m[0], m[1])
+    if sx == 0 {
+        return 0, 0, 0, 0, 0, false
+    }
+    theta := math.Atan2(m[1], m[0])
+    sy = (m[0]*m[3] - m[1]*m[2]) / sx
+    const eps = 1e-9
+    if math.Abs(m[2]+sy*math.Sin(theta)) > eps || math.Abs(m[3]-sy*math.Cos(theta)) > eps {
+        return 0, 0, 0, 0, 0, false
+    }
+    return m[4], m[5], theta * 180 / math.Pi, sx, sy, true
+}
+
+// parseTransform interpreta la lista de transformaciones de un atributo transform
+func parseTransform(value string) (affine, error) {
+    result := identityAffine
+    rest := strings.TrimSpace(value)
+    for rest != "" {
+        open := strings.IndexByte(rest, '(')
+        end := strings.IndexByte(rest, ')')
+        if open < 0 || end < open {
+            return result, fmt.Errorf("transformación no válida: %q", value)
+        }
+        name := strings.TrimSpace(rest[:open])
+        p := &pathScanner{s: rest[open+1 : end]}
+        var args []float64
+        for {
+            p.skipSeparators()
+            if p.pos >= len(p.s) {
+                break
+            }
+            v, err := p.number()
+            if err != nil {
+                return result, fmt.Errorf("transformación no válida: %q", value)
+            }
+            args = append(args, v)
+        }
+        rest = strings.TrimLeft(rest[end+1:], " \t\r\n,")
+        
+        arg := func(i int, fallback float64) float64 {
+            if i < len(args) {
+                return args[i]
+            }
+            return fallback
+        }
+        var m affine
+        switch name {
+        case "matrix":
+            if len(args) != 6 {
+                return result, fmt.Errorf("matrix necesita 6 valores: %q", value)
+            }
+            copy(m[:], args)
+        case "translate":
+            m = affine{1, 0, 0, 1, arg(0, 0), arg(1, 0)}
+        case "scale":
+            m = affine{arg(0, 1), 0, 0, arg(1, arg(0, 1)), 0, 0}
+        case "rotate":
+            rad := arg(0, 0) * math.Pi / 180
+            cos, sin := math.Cos(rad), math.Sin(rad)
+            cx, cy := arg(1, 0), arg(2, 0)
+            m = affine{1, 0, 0, 1, cx, cy}.
+                multiply(affine{cos, sin, -sin, cos, 0, 0}).
+                multiply(affine{1, 0, 0, 1, -cx, -cy})
+        case "skewX":
+            m = affine{1, 0, math.Tan(arg(0, 0) * math.Pi / 180), 1, 0, 0}
+        case "skewY":
+            m = affine{1, math.Tan(arg(0, 0) * math.Pi / 180), 0, 1, 0, 0}
+        default:
+            return result, fmt.Errorf("transformación desconocida: %s", name)
+        }
+        result = result.multiply(m)
+    }
+    return result, nil
+}
+
+// arcToCubics aproxima un arco elíptico SVG con curvas cúbicas (SVG 1.1, F.6.5)
+func arcToCubics(x1, y1 float64, args []float64) [][6]float64 {
+    rx, ry, phi := math.Abs(args[0]), math.Abs(args[1]), args[2]*math.Pi/180
+    large, sweep := args[3] != 0, args[4] != 0
+    x2, y2 := args[5], args[6]
+    if rx == 0 || ry == 0 || (x1 == x2 && y1 == y2) {
+        return [][6]float64{{x1, y1, x2, y2, x2, y2}}
+    }
+    
+    cos, sin := math.Cos(phi), math.Sin(phi)
+    dx, dy := (x1-x2)/2, (y1-y2)/2
+    x1p, y1p := cos*dx+sin*dy, -sin*dx+cos*dy
+    
+    // Corregir radios demasiado pequeños
+    if lambda := x1p*x1p/(rx*rx) + y1p*y1p/(ry*ry); lambda > 1 {
+        s := math.Sqrt(lambda)
+        rx, ry = rx*s, ry*s
+    }
+    
+    num := rx*rx*ry*ry - rx*rx*y1p*y1p - ry*ry*x1p*x1p
+    den := rx*rx*y1p*y1p + ry*ry*x1p*x1p
+    coef := math.Sqrt(math.Max(num/den, 0))
+    if large == sweep {
+        coef = -coef
+    }
+    cxp, cyp := coef*rx*y1p/ry, -coef*ry*x1p/rx
+    cx := cos*cxp - sin*cyp + (x1+x2)/2
+    cy := sin*cxp + cos*cyp + (y1+y2)/2
+    
+    angle := func(ux, uy, vx, vy float64) float64 {
+        return math.Atan2(ux*vy-uy*vx, ux*vx+uy*vy)
+    }
+    theta1 := angle(1, 0, (x1p-cxp)/rx, (y1p-cyp)/ry)
+    delta := angle((x1p-cxp)/rx, (y1p-cyp)/ry, (-x1p-cxp)/rx, (-y1p-cyp)/ry)
+    if !sweep && delta > 0 {
+        delta -= 2 * math.Pi
+    } else if sweep && delta < 0 {
+        delta += 2 * math.Pi
+    }
+    
+    n := int(math.Ceil(math.Abs(delta) / (math.Pi / 2)))
+    step := delta / float64(n)
+    k := 4.0 / 3.0 * math.Tan(step/4)
+    point := func(t float64) (float64, float64) {
+        ex, ey := rx*math.Cos(t), ry*math.Sin(t)
+        return cos*ex - sin*ey + cx, sin*ex + cos*ey + cy
+    }
+    derivative := func(t float64) (float64, float64) {
+        ex, ey := -rx*math.Sin(t), ry*math.Cos(t)
+        return cos*ex - sin*ey, sin*ex + cos*ey
+    }
+    
+    curves := make([][6]float64, 0, n)
+    t := theta1
+    px, py := x1, y1
+    for i := 0; i < n; i++ {
+        t2 := t + step
+        qx, qy := point(t2)
+        if i == n-1 {
+            qx, qy = x2, y2
+        }
+        d1x, d1y := derivative(t)
+        d2x, d2y := derivative(t2)
+        curves = append(curves, [6]float64{px + k*d1x, py + k*d1y, qx - k*d2x, qy - k*d2y, qx, qy})
+        px, py, t = qx, qy, t2
+    }
+    return curves
+}
+
+// pathWithoutArcs sustituye los arcos por curvas cúbicas
+func pathWithoutArcs(segments []pathSegment) []pathSegment {
+    result := make([]pathSegment, 0, len(segments))
+    var cx, cy, startX, startY float64
+    for _, seg := range segments {
+        switch seg.Cmd {
+        case 'A':
+            curves := arcToCubics(cx, cy, seg.Args)
+            for i := range curves {
+                result = append(result, pathSegment{'C', curves[i][:]})
+            }
+        default:
+            result = append(result, seg)
+        }
+        switch seg.Cmd {
+        case 'M':
+            startX, startY = seg.Args[0], seg.Args[1]
+            cx, cy = startX, startY
+        case 'Z':
+            cx, cy = startX, startY
+        default:
+            cx, cy = seg.Args[len(seg.Args)-2], seg.Args[len(seg.Args)-1]
+        }
+    }
+    return result
+}
+
+// transformPath aplica la matriz a todos los puntos; los arcos se convierten antes
+// en curvas cúbicas para admitir cualquier transformación
+func transformPath(segments []pathSegment, m affine) []pathSegment {
+    if m.isIdentity() {
+        return segments
+    }
+    source := pathWithoutArcs(segments)
+    result := make([]pathSegment, len(source))
+    for i, seg := range source {
+        args := make([]float64, len(seg.Args))
+        for j := 0; j+1 < len(seg.Args); j += 2 {
+            args[j], args[j+1] = m.apply(seg.Args[j], seg.Args[j+1])
+        }
+        result[i] = pathSegment{seg.Cmd, args}
+    }
+    return result
+}
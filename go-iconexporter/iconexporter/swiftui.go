@@ -0,0 +1,140 @@
+// iconexporter/swiftui.go
+package iconexporter
+
+import (
+    "fmt"
+    "image/color"
+    "io"
+    "strings"
+)
+
+// swiftUIFormat convierte el SVG preparado en un Shape de SwiftUI. Cada relleno
+// y cada trazo es una capa; el Shape dibuja la silueta completa o una capa y la
+// vista que lo acompaña pinta las capas con sus colores originales.
+type swiftUIFormat struct{}
+
+func init() {
+    mustRegisterFormat(swiftUIFormat{})
+}
+
+func (swiftUIFormat) Name() string      { return "swiftui" }
+func (swiftUIFormat) Extension() string { return "swift" }
+func (swiftUIFormat) Vector() bool      { return true }
+
+// swiftColor escribe un Color de SwiftUI en sRGB
+func swiftColor(c color.NRGBA, opacity float64) string {
+    channel := func(v uint8) string { return formatNumber(float64(v)/255, 3) }
+    return fmt.Sprintf("Color(.sRGB, red: %s, green: %s, blue: %s, opacity: %s)",
+        channel(c.R), channel(c.G), channel(c.B), formatNumber(float64(c.A)/255*opacity, 3))
+}
+
+// swiftPoint escribe un CGPoint
+func swiftPoint(x, y float64, precision int) string {
+    return fmt.Sprintf("CGPoint(x: %s, y: %s)", formatNumber(x, precision), formatNumber(y, precision))
+}
+
+func (swiftUIFormat) Encode(w io.Writer, input EncodeInput, options map[string]interface{}) error {
+    shapes, box, err := flattenSvg(input.SVG, input.Color, input.warn)
+    if err != nil {
+        return err
+    }
+    precision := optionInt(options, "precision", 3)
+    name := codeTypeName(input)
+    
+    // Capas en orden de pintado: relleno y, encima, el trazo de cada forma
+    var colors, cases strings.Builder
+    layers := 0
+    caps := map[string]string{"butt": ".butt", "round": ".round", "square": ".square"}
+    joins := map[string]string{"miter": ".miter", "round": ".round", "bevel": ".bevel"}
+    for _, shape := range shapes {
+        var commands strings.Builder
+        for _, seg := range viewBoxPath(shape.Segments, box) {
+            a := seg.Args
+            switch seg.Cmd {
+            case 'M':
+                fmt.Fprintf(&commands, "            path.move(to: %s)\n", swiftPoint(a[0], a[1], precision))
+            case 'L':
+                fmt.Fprintf(&commands, "            path.addLine(to: %s)\n", swiftPoint(a[0], a[1], precision))
+            case 'C':
+                fmt.Fprintf(&commands, "            path.addCurve(to: %s, control1: %s, control2: %s)\n",
+                    swiftPoint(a[4], a[5], precision), swiftPoint(a[0], a[1], precision), swiftPoint(a[2], a[3], precision))
+            case 'Q':
+                fmt.Fprintf(&commands, "            path.addQuadCurve(to: %s, control: %s)\n",
+                    swiftPoint(a[2], a[3], precision), swiftPoint(a[0], a[1], precision))
+            case 'Z':
+                commands.WriteString("            path.closeSubpath()\n")
+            }
+        }
+        
+        if !shape.Fill.None {
+            fmt.Fprintf(&colors, "        (%s, %t),\n", swiftColor(shape.Fill.Color, shape.FillOpacity), shape.EvenOdd)
+            fmt.Fprintf(&cases, "        case %d:\n%s", layers, commands.String())
+            layers++
+        }
+        if !shape.Stroke.None {
+            if dash := shape.Style.value("stroke-dasharray"); dash != "" && dash != "none" {
+                input.warn("stroke-dasharray no está soportado, se dibuja continuo")
+            }
+            fmt.Fprintf(&colors, "        (%s, false),\n", swiftColor(shape.Stroke.Color, shape.StrokeOpacity))
+            fmt.Fprintf(&cases, "        case %d:\n%s", layers, commands.String())
+            fmt.Fprintf(&cases, "            path = path.strokedPath(StrokeStyle(lineWidth: %s, lineCap: %s, lineJoin: %s, miterLimit: %s))\n",
+                formatNumber(shape.StrokeWidth, precision), caps[shape.LineCap],
+                joins[shape.LineJoin], formatNumber(shape.Style.number("stroke-miterlimit"), 3))
+            layers++
+        }
+    }
+    
+    width, height := formatNumber(box[2], precision), formatNumber(box[3], precision)
+    _, err = fmt.Fprintf(w, `import SwiftUI
+
+struct %[1]s: Shape {
+    /// Capa a dibujar (índice de layers); nil dibuja la silueta completa
+    var layer: Int? = nil
+
+    /// Tamaño por defecto del icono
+    static let size = CGSize(width: %[2]s, height: %[3]s)
+
+    /// Color y regla de relleno (eoFill) de cada capa
+    static let layers: [(color: Color, eoFill: Bool)] = [
+%[4]s    ]
+
+    func path(in rect: CGRect) -> Path {
+        let scale = min(rect.width / %[5]s, rect.height / %[6]s)
+        let transform = CGAffineTransform(
+            translationX: rect.midX - %[5]s * scale / 2,
+            y: rect.midY - %[6]s * scale / 2
+        ).scaledBy(x: scale, y: scale)
+        var result = Path()
+        for index in Self.layers.indices where layer == nil || layer == index {
+            result.addPath(Self.layerPath(index), transform: transform)
+        }
+        return result
+    }
+
+    /// Trayecto de una capa en coordenadas del viewBox
+    private static func layerPath(_ index: Int) -> Path {
+        var path = Path()
+        switch index {
+%[7]s        default:
+            break
+        }
+        return path
+    }
+}
+
+/// Icono con los colores originales
+struct %[1]sView: View {
+    var body: some View {
+        ZStack {
+            ForEach(%[1]s.layers.indices, id: \.self) { index in
+                %[1]s(layer: index)
+                    .fill(%[1]s.layers[index].color, style: FillStyle(eoFill: %[1]s.layers[index].eoFill))
+            }
+        }
+        .aspectRatio(%[5]s / %[6]s, contentMode: .fit)
+    }
+}
+`, name, formatNumber(float64(input.Width), 2), formatNumber(float64(input.Height), 2),
+        colors.String(), width, height, cases.String())
+    return err
+}
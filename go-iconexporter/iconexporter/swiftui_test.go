@@ -0,0 +1,62 @@
+// iconexporter/swiftui_test.go
+package iconexporter
+
+import (
+    "reflect"
+    "strings"
+    "testing"
+)
+
+func TestSwiftUIEncode(t *testing.T) {
+    tests := []struct {
+        name     string
+        svg      []byte
+        contains []string
+        warnings []string
+    }{
+        {
+            name: "capas de relleno y trazo",
+            svg:  testSvg(`<path fill="#00ff00" fill-rule="evenodd" stroke="#0000ff" stroke-opacity="0.5" d="M4 4h10v10H4z"/>`),
+            contains: []string{
+                "(Color(.sRGB, red: 0, green: 1, blue: 0, opacity: 1), true),\n        (Color(.sRGB, red: 0, green: 0, blue: 1, opacity: 0.5), false),",
+                "case 0:\n            path.move(to: CGPoint(x: 4, y: 4))",
+                "case 1:",
+                "path.closeSubpath()",
+            },
+        },
+        {
+            name:     "extremos y uniones",
+            svg:      testSvg(`<path fill="none" stroke="red" stroke-width="3" stroke-linecap="square" stroke-linejoin="round" d="M2 2L6 6"/>`),
+            contains: []string{"StrokeStyle(lineWidth: 3, lineCap: .square, lineJoin: .round, miterLimit: 4)"},
+        },
+        {
+            name:     "valores no válidos usan butt y miter",
+            svg:      testSvg(`<path fill="none" stroke="red" stroke-linecap="flat" stroke-linejoin="sharp" d="M2 2L6 6"/>`),
+            contains: []string{"lineCap: .butt, lineJoin: .miter"},
+            warnings: []string{"stroke-linecap no válido: flat, se usa butt", "stroke-linejoin no válido: sharp, se usa miter"},
+        },
+        {
+            name:     "miter-clip se dibuja como miter",
+            svg:      testSvg(`<path fill="none" stroke="red" stroke-linejoin="miter-clip" d="M2 2L6 6"/>`),
+            contains: []string{"lineJoin: .miter"},
+        },
+        {
+            name:     "viewBox no cuadrado",
+            svg:      []byte(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 32 16"><path d="M0 0h32v16z"/></svg>`),
+            contains: []string{"static let size = CGSize(width: 24, height: 24)", "min(rect.width / 32, rect.height / 16)", ".aspectRatio(32 / 16, contentMode: .fit)"},
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got, warnings := encodeWith(t, swiftUIFormat{}, tt.svg, "nonicons-bell.swift", nil)
+            for _, want := range append([]string{"import SwiftUI\n", "struct nonicons_bell: Shape {", "struct nonicons_bellView: View {"}, tt.contains...) {
+                if !strings.Contains(got, want) {
+                    t.Errorf("falta %q en:\n%s", want, got)
+                }
+            }
+            if !reflect.DeepEqual(warnings, tt.warnings) {
+                t.Errorf("avisos %q, se esperaban %q", warnings, tt.warnings)
+            }
+        })
+    }
+}
@@ -0,0 +1,135 @@
+// iconexporter/tiff.go
+package iconexporter
+
+import (
+    "bytes"
+    "compress/zlib"
+    "encoding/binary"
+    "fmt"
+    "image"
+    "io"
+    "sort"
+
+    "github.com/disintegration/imaging"
+)
+
+// Etiquetas y tipos TIFF utilizados
+const (
+    tiffShort    = 3
+    tiffLong     = 4
+    tiffRational = 5
+    
+    tiffImageWidth      = 256
+    tiffImageLength     = 257
+    tiffBitsPerSample   = 258
+    tiffCompression     = 259
+    tiffPhotometric     = 262
+    tiffStripOffsets    = 273
+    tiffSamplesPerPixel = 277
+    tiffRowsPerStrip    = 278
+    tiffStripByteCounts = 279
+    tiffXResolution     = 282
+    tiffYResolution     = 283
+    tiffPlanarConfig    = 284
+    tiffResolutionUnit  = 296
+    tiffExtraSamples    = 338
+)
+
+type tiffEntry struct {
+    tag, kind uint16
+    count     uint32
+    value     uint32
+}
+
+func init() {
+    registerRasterFormat("tiff", encodeTIFF)
+}
+
+// encodeTIFF escribe un TIFF RGBA de 8 bits (alfa no asociado) en una única tira.
+// Opciones: "dpi" (por defecto 72) y "compression" (none o deflate).
+func encodeTIFF(w io.Writer, img image.Image, options map[string]interface{}) error {
+    dpi := optionInt(options, "dpi", 72)
+    if dpi < 1 {
+        return fmt.Errorf("DPI no válido para TIFF: %d", dpi)
+    }
+    compression, _ := options["compression"].(string)
+    if compression == "" {
+        compression = "deflate"
+    }
+    
+    src := imaging.Clone(img)
+    bounds := src.Bounds()
+    data := src.Pix
+    compressionTag := uint32(1)
+    switch compression {
+    case "none":
+    case "deflate":
+        var buf bytes.Buffer
+        zw := zlib.NewWriter(&buf)
+        if _, err := zw.Write(src.Pix); err != nil {
+            return err
+        }
+        if err := zw.Close(); err != nil {
+            return err
+        }
+        data = buf.Bytes()
+        compressionTag = 8
+    default:
+        return fmt.Errorf("compresión TIFF no válida: %s", compression)
+    }
+    
+    // Distribución: cabecera, datos, valores externos y al final el IFD
+    dataOffset := uint32(8)
+    bitsOffset := dataOffset + uint32(len(data))
+    bitsOffset += bitsOffset & 1
+    xResOffset := bitsOffset + 8
+    yResOffset := xResOffset + 8
+    ifdOffset := yResOffset + 8
+    
+    entries := []tiffEntry{
+        {tiffImageWidth, tiffLong, 1, uint32(bounds.Dx())},
+        {tiffImageLength, tiffLong, 1, uint32(bounds.Dy())},
+        {tiffBitsPerSample, tiffShort, 4, bitsOffset},
+        {tiffCompression, tiffShort, 1, compressionTag},
+        {tiffPhotometric, tiffShort, 1, 2},
+        {tiffStripOffsets, tiffLong, 1, dataOffset},
+        {tiffSamplesPerPixel, tiffShort, 1, 4},
+        {tiffRowsPerStrip, tiffLong, 1, uint32(bounds.Dy())},
+        {tiffStripByteCounts, tiffLong, 1, uint32(len(data))},
+        {tiffXResolution, tiffRational, 1, xResOffset},
+        {tiffYResolution, tiffRational, 1, yResOffset},
+        {tiffPlanarConfig, tiffShort, 1, 1},
+        {tiffResolutionUnit, tiffShort, 1, 2},
+        {tiffExtraSamples, tiffShort, 1, 2},
+    }
+    sort.Slice(entries, func(i, j int) bool { return entries[i].tag < entries[j].tag })
+    
+    var buf bytes.Buffer
+    le := binary.LittleEndian
+    buf.WriteString("II")
+    binary.Write(&buf, le, uint16(42))
+    binary.Write(&buf, le, ifdOffset)
+    buf.Write(data)
+    if len(data)&1 == 1 {
+        buf.WriteByte(0)
+    }
+    binary.Write(&buf, le, [4]uint16{8, 8, 8, 8})
+    binary.Write(&buf, le, [2]uint32{uint32(dpi), 1})
+    binary.Write(&buf, le, [2]uint32{uint32(dpi), 1})
+    
+    binary.Write(&buf, le, uint16(len(entries)))
+    for _, entry := range entries {
+        binary.Write(&buf, le, entry.tag)
+        binary.Write(&buf, le, entry.kind)
+        binary.Write(&buf, le, entry.count)
+        if entry.kind == tiffShort && entry.count == 1 {
+            binary.Write(&buf, le, [2]uint16{uint16(entry.value), 0})
+        } else {
+            binary.Write(&buf, le, entry.value)
+        }
+    }
+    binary.Write(&buf, le, uint32(0))
+    
+    _, err := w.Write(buf.Bytes())
+    return err
+}
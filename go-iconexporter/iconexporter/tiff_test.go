@@ -0,0 +1,57 @@
+// iconexporter/tiff_test.go
+package iconexporter
+
+import (
+    "bytes"
+    "encoding/binary"
+    "testing"
+
+    "golang.org/x/image/tiff"
+)
+
+func TestEncodeTIFFRoundTrip(t *testing.T) {
+    tests := []struct {
+        name    string
+        options map[string]interface{}
+        dpi     uint32
+    }{
+        {"deflate por defecto", nil, 72},
+        {"sin compresión", map[string]interface{}{"compression": "none"}, 72},
+        {"300 dpi desde JSON", map[string]interface{}{"dpi": 300.0}, 300},
+    }
+    for _, pattern := range []int{patternNoise, patternBands} {
+        img := testImage(7, 5, pattern)
+        for _, tt := range tests {
+            t.Run(tt.name, func(t *testing.T) {
+                var buf bytes.Buffer
+                if err := encodeTIFF(&buf, img, tt.options); err != nil {
+                    t.Fatal(err)
+                }
+                decoded, err := tiff.Decode(bytes.NewReader(buf.Bytes()))
+                if err != nil {
+                    t.Fatal(err)
+                }
+                sameNRGBA(t, img, decoded)
+                
+                resolution := binary.LittleEndian.AppendUint32(nil, tt.dpi)
+                resolution = binary.LittleEndian.AppendUint32(resolution, 1)
+                if !bytes.Contains(buf.Bytes(), resolution) {
+                    t.Fatalf("no se encontró la resolución %d/1", tt.dpi)
+                }
+            })
+        }
+    }
+}
+
+func TestEncodeTIFFErrors(t *testing.T) {
+    for name, options := range map[string]map[string]interface{}{
+        "dpi cero":             {"dpi": 0},
+        "compresión no válida": {"compression": "lzw"},
+    } {
+        t.Run(name, func(t *testing.T) {
+            if err := encodeTIFF(&bytes.Buffer{}, testImage(2, 2, patternSolid), options); err == nil {
+                t.Fatal("se esperaba un error")
+            }
+        })
+    }
+}
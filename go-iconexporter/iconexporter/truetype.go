@@ -0,0 +1,705 @@
+// iconexporter/truetype.go
+package iconexporter
+
+import (
+    "encoding/binary"
+    "fmt"
+    "math"
+    "sort"
+    "unicode/utf16"
+)
+
+// ttPoint es un punto de un contorno TrueType; On indica si está sobre la curva
+type ttPoint struct {
+    X, Y int
+    On   bool
+}
+
+// ttGlyph es un glifo simple con sus contornos y su avance horizontal
+type ttGlyph struct {
+    Name     string
+    Contours [][]ttPoint
+    Advance  int
+}
+
+// ttLigature sustituye una secuencia de glifos por otro (característica liga)
+type ttLigature struct {
+    Components []int
+    Glyph      int
+}
+
+// ttFont describe una fuente TrueType mínima; el glifo 0 debe ser .notdef
+type ttFont struct {
+    FamilyName string
+    Version    string
+    UnitsPerEm int
+    Ascent     int
+    Descent    int // negativo, como en hhea
+    Glyphs     []ttGlyph
+    CharMap    map[rune]int
+    Ligatures  []ttLigature
+}
+
+// ttWriter acumula datos big-endian
+type ttWriter []byte
+
+func (w *ttWriter) u8(v int)     { *w = append(*w, byte(v)) }
+func (w *ttWriter) u16(v int)    { *w = binary.BigEndian.AppendUint16(*w, uint16(v)) }
+func (w *ttWriter) u32(v uint32) { *w = binary.BigEndian.AppendUint32(*w, v) }
+func (w *ttWriter) tag(t string) { *w = append(*w, t[:4]...) }
+
+// pad4 completa hasta un múltiplo de 4 bytes
+func (w *ttWriter) pad4() {
+    for len(*w)%4 != 0 {
+        *w = append(*w, 0)
+    }
+}
+
+// sfntChecksum suma los datos como uint32 big-endian (con relleno a 4 bytes)
+func sfntChecksum(data []byte) uint32 {
+    var sum uint32
+    for i := 0; i < len(data); i += 4 {
+        var word [4]byte
+        copy(word[:], data[i:])
+        sum += binary.BigEndian.Uint32(word[:])
+    }
+    return sum
+}
+
+// bounds devuelve la caja del glifo (todo ceros si está vacío)
+func (g ttGlyph) bounds() (xMin, yMin, xMax, yMax int) {
+    first := true
+    for _, contour := range g.Contours {
+        for _, p := range contour {
+            if first {
+                xMin, yMin, xMax, yMax = p.X, p.Y, p.X, p.Y
+                first = false
+                continue
+            }
+            xMin, yMin = min(xMin, p.X), min(yMin, p.Y)
+            xMax, yMax = max(xMax, p.X), max(yMax, p.Y)
+        }
+    }
+    return
+}
+
+// encode escribe el glifo simple; los glifos sin contornos no ocupan espacio en glyf
+func (g ttGlyph) encode() []byte {
+    if len(g.Contours) == 0 {
+        return nil
+    }
+    var w ttWriter
+    xMin, yMin, xMax, yMax := g.bounds()
+    w.u16(len(g.Contours))
+    w.u16(xMin)
+    w.u16(yMin)
+    w.u16(xMax)
+    w.u16(yMax)
+    end := -1
+    for _, contour := range g.Contours {
+        end += len(contour)
+        w.u16(end)
+    }
+    w.u16(0) // sin instrucciones
+    
+    // Coordenadas como deltas de 16 bits, sin compactar
+    for _, contour := range g.Contours {
+        for _, p := range contour {
+            if p.On {
+                w.u8(1)
+            } else {
+                w.u8(0)
+            }
+        }
+    }
+    for axis := 0; axis < 2; axis++ {
+        last := 0
+        for _, contour := range g.Contours {
+            for _, p := range contour {
+                v := p.X
+                if axis == 1 {
+                    v = p.Y
+                }
+                w.u16(v - last)
+                last = v
+            }
+        }
+    }
+    w.pad4()
+    return w
+}
+
+// encode genera el archivo TTF completo
+func (f *ttFont) encode() ([]byte, error) {
+    if len(f.Glyphs) == 0 || len(f.Glyphs) > 0xFFFF {
+        return nil, fmt.Errorf("número de glifos no válido: %d", len(f.Glyphs))
+    }
+    
+    // glyf y loca (formato largo)
+    var glyf, loca ttWriter
+    xMin, yMin, xMax, yMax := 0, 0, 0, 0
+    advanceMax, minLSB, minRSB, maxExtent := 0, 0, 0, 0
+    maxPoints, maxContours := 0, 0
+    first := true
+    var hmtx ttWriter
+    for _, g := range f.Glyphs {
+        loca.u32(uint32(len(glyf)))
+        glyf = append(glyf, g.encode()...)
+        
+        gxMin, gyMin, gxMax, gyMax := g.bounds()
+        hmtx.u16(g.Advance)
+        hmtx.u16(gxMin)
+        advanceMax = max(advanceMax, g.Advance)
+        if len(g.Contours) == 0 {
+            continue
+        }
+        if first {
+            xMin, yMin, xMax, yMax = gxMin, gyMin, gxMax, gyMax
+            minLSB, minRSB, maxExtent = gxMin, g.Advance-gxMax, gxMax
+            first = false
+        }
+        xMin, yMin = min(xMin, gxMin), min(yMin, gyMin)
+        xMax, yMax = max(xMax, gxMax), max(yMax, gyMax)
+        minLSB, minRSB = min(minLSB, gxMin), min(minRSB, g.Advance-gxMax)
+        maxExtent = max(maxExtent, gxMax)
+        points := 0
+        for _, contour := range g.Contours {
+            points += len(contour)
+        }
+        maxPoints = max(maxPoints, points)
+        maxContours = max(maxContours, len(g.Contours))
+    }
+    loca.u32(uint32(len(glyf)))
+    
+    var head ttWriter
+    head.u32(0x00010000)
+    head.u32(0x00010000)
+    head.u32(0) // checkSumAdjustment, se completa al final
+    head.u32(0x5F0F3CF5)
+    head.u16(0x000B)
+    head.u16(f.UnitsPerEm)
+    head.u32(0) // fechas a cero: la salida es reproducible
+    head.u32(0)
+    head.u32(0)
+    head.u32(0)
+    head.u16(xMin)
+    head.u16(yMin)
+    head.u16(xMax)
+    head.u16(yMax)
+    head.u16(0)
+    head.u16(8)
+    head.u16(2)
+    head.u16(1)
+    head.u16(0)
+    
+    var hhea ttWriter
+    hhea.u32(0x00010000)
+    hhea.u16(f.Ascent)
+    hhea.u16(f.Descent)
+    hhea.u16(0)
+    hhea.u16(advanceMax)
+    hhea.u16(minLSB)
+    hhea.u16(minRSB)
+    hhea.u16(maxExtent)
+    hhea.u16(1)
+    hhea.u16(0)
+    hhea.u16(0)
+    for i := 0; i < 4; i++ {
+        hhea.u16(0)
+    }
+    hhea.u16(0)
+    hhea.u16(len(f.Glyphs))
+    
+    var maxp ttWriter
+    maxp.u32(0x00010000)
+    maxp.u16(len(f.Glyphs))
+    maxp.u16(maxPoints)
+    maxp.u16(maxContours)
+    maxp.u16(0)
+    maxp.u16(0)
+    maxp.u16(2)
+    for i := 0; i < 8; i++ {
+        maxp.u16(0)
+    }
+    
+    codes := make([]rune, 0, len(f.CharMap))
+    for code := range f.CharMap {
+        codes = append(codes, code)
+    }
+    sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+    
+    tables := map[string][]byte{
+        "head": head,
+        "hhea": hhea,
+        "maxp": maxp,
+        "hmtx": hmtx,
+        "loca": loca,
+        "glyf": glyf,
+        "cmap": f.cmapTable(codes),
+        "OS/2": f.os2Table(codes, yMin, yMax),
+        "name": f.nameTable(),
+        "post": f.postTable(),
+    }
+    if len(f.Ligatures) > 0 {
+        gsub, err := f.gsubTable()
+        if err != nil {
+            return nil, err
+        }
+        tables["GSUB"] = gsub
+    }
+    return assembleSfnt(tables), nil
+}
+
+// assembleSfnt escribe el directorio de tablas ordenado y calcula checkSumAdjustment
+func assembleSfnt(tables map[string][]byte) []byte {
+    tags := make([]string, 0, len(tables))
+    for tag := range tables {
+        tags = append(tags, tag)
+    }
+    sort.Strings(tags)
+    
+    numTables := len(tags)
+    entrySelector := 0
+    for 1<<(entrySelector+1) <= numTables {
+        entrySelector++
+    }
+    searchRange := 16 << entrySelector
+    
+    var font ttWriter
+    font.u32(0x00010000)
+    font.u16(numTables)
+    font.u16(searchRange)
+    font.u16(entrySelector)
+    font.u16(numTables*16 - searchRange)
+    
+    offset := 12 + 16*numTables
+    headOffset := 0
+    for _, tag := range tags {
+        data := tables[tag]
+        if tag == "head" {
+            headOffset = offset
+        }
+        font.tag(tag)
+        font.u32(sfntChecksum(data))
+        font.u32(uint32(offset))
+        font.u32(uint32(len(data)))
+        offset += (len(data) + 3) &^ 3
+    }
+    for _, tag := range tags {
+        font = append(font, tables[tag]...)
+        font.pad4()
+    }
+    
+    binary.BigEndian.PutUint32(font[headOffset+8:], 0xB1B0AFBA-sfntChecksum(font))
+    return font
+}
+
+// cmapTable genera los subtables de formato 4 (BMP) y, si hace falta, 12
+func (f *ttFont) cmapTable(codes []rune) []byte {
+    type segment struct {
+        start, end rune
+        glyph      int
+    }
+    var segments []segment
+    for _, code := range codes {
+        glyph := f.CharMap[code]
+        if n := len(segments); n > 0 {
+            last := &segments[n-1]
+            if code == last.end+1 && glyph == last.glyph+int(code-last.start) {
+                last.end = code
+                continue
+            }
+        }
+        segments = append(segments, segment{code, code, glyph})
+    }
+    
+    var bmp []segment
+    for _, s := range segments {
+        if s.end <= 0xFFFE {
+            bmp = append(bmp, s)
+        } else if s.start <= 0xFFFE {
+            bmp = append(bmp, segment{s.start, 0xFFFE, s.glyph})
+        }
+    }
+    bmp = append(bmp, segment{0xFFFF, 0xFFFF, 0})
+    
+    segCount := len(bmp)
+    entrySelector := 0
+    for 1<<(entrySelector+1) <= segCount {
+        entrySelector++
+    }
+    searchRange := 2 << entrySelector
+    
+    var format4 ttWriter
+    format4.u16(4)
+    format4.u16(16 + 8*segCount)
+    format4.u16(0)
+    format4.u16(segCount * 2)
+    format4.u16(searchRange)
+    format4.u16(entrySelector)
+    format4.u16(segCount*2 - searchRange)
+    for _, s := range bmp {
+        format4.u16(int(s.end))
+    }
+    format4.u16(0)
+    for _, s := range bmp {
+        format4.u16(int(s.start))
+    }
+    for _, s := range bmp {
+        if s.start == 0xFFFF {
+            format4.u16(1)
+        } else {
+            format4.u16((s.glyph - int(s.start)) & 0xFFFF)
+        }
+    }
+    for range bmp {
+        format4.u16(0)
+    }
+    
+    // Formato 12 solo si hay códigos fuera del BMP (p. ej. plano 15 de uso privado)
+    var format12 ttWriter
+    if len(codes) > 0 && codes[len(codes)-1] > 0xFFFF {
+        format12.u16(12)
+        format12.u16(0)
+        format12.u32(uint32(16 + 12*len(segments)))
+        format12.u32(0)
+        format12.u32(uint32(len(segments)))
+        for _, s := range segments {
+            format12.u32(uint32(s.start))
+            format12.u32(uint32(s.end))
+            format12.u32(uint32(s.glyph))
+        }
+    }
+    
+    var cmap ttWriter
+    numTables := 2
+    if len(format12) > 0 {
+        numTables = 4
+    }
+    cmap.u16(0)
+    cmap.u16(numTables)
+    offset4 := 4 + 8*numTables
+    offset12 := offset4 + len(format4)
+    records := [][3]int{{0, 3, offset4}, {3, 1, offset4}}
+    if len(format12) > 0 {
+        records = [][3]int{{0, 3, offset4}, {0, 4, offset12}, {3, 1, offset4}, {3, 10, offset12}}
+    }
+    for _, r := range records {
+        cmap.u16(r[0])
+        cmap.u16(r[1])
+        cmap.u32(uint32(r[2]))
+    }
+    cmap = append(cmap, format4...)
+    cmap = append(cmap, format12...)
+    return cmap
+}
+
+// os2Table genera la tabla OS/2 versión 4
+func (f *ttFont) os2Table(codes []rune, yMin, yMax int) []byte {
+    upm := f.UnitsPerEm
+    scaled := func(ratio float64) int { return int(math.Round(float64(upm) * ratio)) }
+    
+    totalAdvance, advances := 0, 0
+    for _, g := range f.Glyphs {
+        if g.Advance > 0 {
+            totalAdvance += g.Advance
+            advances++
+        }
+    }
+    avgWidth := 0
+    if advances > 0 {
+        avgWidth = totalAdvance / advances
+    }
+    
+    var unicodeRange [4]uint32
+    firstChar, lastChar := 0xFFFF, 0
+    for _, code := range codes {
+        switch {
+        case code < 0x80:
+            unicodeRange[0] |= 1 // Basic Latin
+        case code >= 0xE000 && code <= 0xF8FF, code >= 0xF0000:
+            unicodeRange[1] |= 1 << (60 - 32) // Private Use Area
+        }
+        firstChar = min(firstChar, int(code))
+        lastChar = max(lastChar, min(int(code), 0xFFFF))
+    }
+    if len(codes) == 0 {
+        firstChar = 0
+    }
+    
+    maxContext := 0
+    for _, lig := range f.Ligatures {
+        maxContext = max(maxContext, len(lig.Components))
+    }
+    
+    var os2 ttWriter
+    os2.u16(4)
+    os2.u16(avgWidth)
+    os2.u16(400)
+    os2.u16(5)
+    os2.u16(0)
+    for _, v := range []float64{0.65, 0.6, 0, 0.075, 0.65, 0.6, 0, 0.35, 0.05, 0.25} {
+        os2.u16(scaled(v))
+    }
+    os2.u16(0)
+    os2 = append(os2, make([]byte, 10)...) // PANOSE
+    for _, r := range unicodeRange {
+        os2.u32(r)
+    }
+    os2.tag("NONE")
+    os2.u16(0x0040) // REGULAR
+    os2.u16(firstChar)
+    os2.u16(lastChar)
+    os2.u16(f.Ascent)
+    os2.u16(f.Descent)
+    os2.u16(0)
+    os2.u16(max(f.Ascent, yMax))
+    os2.u16(max(-f.Descent, -yMin))
+    os2.u32(1)
+    os2.u32(0)
+    os2.u16(scaled(0.5))
+    os2.u16(scaled(0.7))
+    os2.u16(0)
+    os2.u16(32)
+    os2.u16(maxContext)
+    return os2
+}
+
+// nameTable genera los nombres de la familia para Windows (UTF-16BE)
+func (f *ttFont) nameTable() []byte {
+    version := f.Version
+    if version == "" {
+        version = "Version 1.0"
+    }
+    names := []string{
+        1: f.FamilyName,
+        2: "Regular",
+        3: f.FamilyName + ":" + version,
+        4: f.FamilyName,
+        5: version,
+        6: f.FamilyName,
+    }
+    
+    var records, values ttWriter
+    count := 0
+    for id, value := range names {
+        if id == 0 {
+            continue
+        }
+        encoded := utf16.Encode([]rune(value))
+        records.u16(3)
+        records.u16(1)
+        records.u16(0x0409)
+        records.u16(id)
+        records.u16(len(encoded) * 2)
+        records.u16(len(values))
+        for _, unit := range encoded {
+            values.u16(int(unit))
+        }
+        count++
+    }
+    
+    var name ttWriter
+    name.u16(0)
+    name.u16(count)
+    name.u16(6 + 12*count)
+    name = append(name, records...)
+    return append(name, values...)
+}
+
+// postTable genera la tabla post versión 3 (sin nombres de glifos)
+func (f *ttFont) postTable() []byte {
+    var post ttWriter
+    post.u32(0x00030000)
+    post.u32(0)
+    post.u16(-f.UnitsPerEm / 10)
+    post.u16(f.UnitsPerEm / 20)
+    for i := 0; i < 5; i++ {
+        post.u32(0)
+    }
+    return post
+}
+
+// gsubTable genera la característica liga con un lookup de tipo 4
+func (f *ttFont) gsubTable() ([]byte, error) {
+    // Agrupar por primer componente; dentro de cada grupo, las más largas primero
+    sets := map[int][]ttLigature{}
+    for _, lig := range f.Ligatures {
+        if len(lig.Components) == 0 {
+            continue
+        }
+        sets[lig.Components[0]] = append(sets[lig.Components[0]], lig)
+    }
+    firsts := make([]int, 0, len(sets))
+    for glyph, ligs := range sets {
+        firsts = append(firsts, glyph)
+        sort.SliceStable(ligs, func(i, j int) bool {
+            return len(ligs[i].Components) > len(ligs[j].Components)
+        })
+    }
+    sort.Ints(firsts)
+    
+    // LigatureSubst formato 1: cabecera, cobertura y conjuntos de ligaduras
+    var subst, body ttWriter
+    headerSize := 6 + 2*len(firsts)
+    coverageOffset := headerSize
+    var coverage ttWriter
+    coverage.u16(1)
+    coverage.u16(len(firsts))
+    for _, glyph := range firsts {
+        coverage.u16(glyph)
+    }
+    body = append(body, coverage...)
+    
+    subst.u16(1)
+    subst.u16(coverageOffset)
+    subst.u16(len(firsts))
+    for _, glyph := range firsts {
+        setOffset := headerSize + len(body)
+        if setOffset > 0xFFFF {
+            return nil, fmt.Errorf("demasiadas ligaduras para una tabla GSUB")
+        }
+        subst.u16(setOffset)
+        
+        ligs := sets[glyph]
+        var set ttWriter
+        set.u16(len(ligs))
+        ligOffset := 2 + 2*len(ligs)
+        var ligData ttWriter
+        for _, lig := range ligs {
+            set.u16(ligOffset + len(ligData))
+            ligData.u16(lig.Glyph)
+            ligData.u16(len(lig.Components))
+            for _, component := range lig.Components[1:] {
+                ligData.u16(component)
+            }
+        }
+        body = append(body, set...)
+        body = append(body, ligData...)
+    }
+    subst = append(subst, body...)
+    if len(subst) > 0xFFFF {
+        return nil, fmt.Errorf("demasiadas ligaduras para una tabla GSUB")
+    }
+    
+    // ScriptList con DFLT y latn compartiendo el mismo Script
+    var scriptList ttWriter
+    scriptList.u16(2)
+    scriptList.tag("DFLT")
+    scriptList.u16(14)
+    scriptList.tag("latn")
+    scriptList.u16(14)
+    scriptList.u16(4) // defaultLangSys
+    scriptList.u16(0)
+    scriptList.u16(0)
+    scriptList.u16(0xFFFF)
+    scriptList.u16(1)
+    scriptList.u16(0)
+    
+    var featureList ttWriter
+    featureList.u16(1)
+    featureList.tag("liga")
+    featureList.u16(8)
+    featureList.u16(0)
+    featureList.u16(1)
+    featureList.u16(0)
+    
+    var lookupList ttWriter
+    lookupList.u16(1)
+    lookupList.u16(4)
+    lookupList.u16(4) // LigatureSubst
+    lookupList.u16(0)
+    lookupList.u16(1)
+    lookupList.u16(8)
+    lookupList = append(lookupList, subst...)
+    
+    var gsub ttWriter
+    gsub.u16(1)
+    gsub.u16(0)
+    gsub.u16(10)
+    gsub.u16(10 + len(scriptList))
+    gsub.u16(10 + len(scriptList) + len(featureList))
+    gsub = append(gsub, scriptList...)
+    gsub = append(gsub, featureList...)
+    gsub = append(gsub, lookupList...)
+    return gsub, nil
+}
+
+// glyphContours convierte un trayecto (ya en unidades de la fuente) en contornos
+// TrueType, aproximando las cúbicas con cuadráticas dentro de la tolerancia
+func glyphContours(segments []pathSegment, tolerance float64) [][]ttPoint {
+    var contours [][]ttPoint
+    var current []ttPoint
+    var cx, cy float64
+    
+    add := func(x, y float64, on bool) {
+        p := ttPoint{int(math.Round(x)), int(math.Round(y)), on}
+        if n := len(current); n > 0 && on && current[n-1] == p {
+            return
+        }
+        current = append(current, p)
+    }
+    closeContour := func() {
+        // El cierre implícito hace innecesario repetir el punto inicial
+        if n := len(current); n > 1 && current[n-1] == current[0] {
+            current = current[:n-1]
+        }
+        if len(current) >= 3 {
+            contours = append(contours, current)
+        }
+        current = nil
+    }
+    
+    for _, seg := range pathWithoutArcs(segments) {
+        a := seg.Args
+        switch seg.Cmd {
+        case 'M':
+            closeContour()
+            cx, cy = a[0], a[1]
+            add(cx, cy, true)
+        case 'L':
+            cx, cy = a[0], a[1]
+            add(cx, cy, true)
+        case 'Q':
+            add(a[0], a[1], false)
+            cx, cy = a[2], a[3]
+            add(cx, cy, true)
+        case 'C':
+            for _, q := range cubicToQuads([8]float64{cx, cy, a[0], a[1], a[2], a[3], a[4], a[5]}, tolerance, 0) {
+                add(q[0], q[1], false)
+                add(q[2], q[3], true)
+            }
+            cx, cy = a[4], a[5]
+        case 'Z':
+            closeContour()
+        }
+    }
+    closeContour()
+    return contours
+}
+
+// cubicToQuads aproxima una cúbica (p0, c1, c2, p3) con cuadráticas; cada
+// resultado es {control, final}
+func cubicToQuads(c [8]float64, tolerance float64, depth int) [][4]float64 {
+    // Error de la aproximación por una sola cuadrática con el control medio
+    ex := c[6] - 3*c[4] + 3*c[2] - c[0]
+    ey := c[7] - 3*c[5] + 3*c[3] - c[1]
+    if math.Sqrt(3)/36*math.Hypot(ex, ey) <= tolerance || depth >= 8 {
+        qx := (3*(c[2]+c[4]) - c[0] - c[6]) / 4
+        qy := (3*(c[3]+c[5]) - c[1] - c[7]) / 4
+        return [][4]float64{{qx, qy, c[6], c[7]}}
+    }
+    
+    // Dividir por la mitad (de Casteljau)
+    mid := func(a, b float64) float64 { return (a + b) / 2 }
+    x01, y01 := mid(c[0], c[2]), mid(c[1], c[3])
+    x12, y12 := mid(c[2], c[4]), mid(c[3], c[5])
+    x23, y23 := mid(c[4], c[6]), mid(c[5], c[7])
+    x012, y012 := mid(x01, x12), mid(y01, y12)
+    x123, y123 := mid(x12, x23), mid(y12, y23)
+    x, y := mid(x012, x123), mid(y012, y123)
+    
+    left := cubicToQuads([8]float64{c[0], c[1], x01, y01, x012, y012, x, y}, tolerance, depth+1)
+    right := cubicToQuads([8]float64{x, y, x123, y123, x23, y23, c[6], c[7]}, tolerance, depth+1)
+    return append(left, right...)
+}
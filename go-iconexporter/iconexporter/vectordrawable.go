@@ -0,0 +1,260 @@
+// iconexporter/vectordrawable.go
+package iconexporter
+
+import (
+    "fmt"
+    "image/color"
+    "io"
+    "strings"
+)
+
+// vectorDrawableFormat convierte el SVG preparado en un VectorDrawable de Android
+type vectorDrawableFormat struct{}
+
+func init() {
+    mustRegisterFormat(vectorDrawableFormat{})
+}
+
+func (vectorDrawableFormat) Name() string      { return "vectordrawable" }
+func (vectorDrawableFormat) Extension() string { return "xml" }
+func (vectorDrawableFormat) Vector() bool      { return true }
+
+// vdConverter recorre el árbol SVG y escribe los elementos equivalentes
+type vdConverter struct {
+    b         strings.Builder
+    input     EncodeInput
+    precision int
+    depth     int
+    warned    map[string]bool
+}
+
+func (vectorDrawableFormat) Encode(w io.Writer, input EncodeInput, options map[string]interface{}) error {
+    root, err := parseSvgDocument(input.SVG)
+    if err != nil {
+        return err
+    }
+    box, err := documentViewBox(root)
+    if err != nil {
+        return err
+    }
+    
+    c := &vdConverter{
+        input:     input,
+        precision: optionInt(options, "precision", 3),
+        depth:     1,
+        warned:    map[string]bool{},
+    }
+    fmt.Fprintf(&c.b, `<?xml version="1.0" encoding="utf-8"?>
+<vector xmlns:android="http://schemas.android.com/apk/res/android"
+    android:width="%sdp"
+    android:height="%sdp"
+    android:viewportWidth="%s"
+    android:viewportHeight="%s">
+`, formatNumber(float64(input.Width), 2), formatNumber(float64(input.Height), 2),
+        formatNumber(box[2], c.precision), formatNumber(box[3], c.precision))
+    
+    // El viewport de Android siempre empieza en 0,0
+    rootStyle := svgStyle{}.inherit(root)
+    if box[0] != 0 || box[1] != 0 {
+        c.openGroup(affine{1, 0, 0, 1, -box[0], -box[1]})
+        c.children(root, rootStyle, identityAffine, elementOpacity(root))
+        c.closeGroup()
+    } else {
+        c.children(root, rootStyle, identityAffine, elementOpacity(root))
+    }
+    c.b.WriteString("</vector>\n")
+    
+    _, err = io.WriteString(w, c.b.String())
+    return err
+}
+
+// warn registra un aviso una sola vez por icono
+func (c *vdConverter) warn(format string, args ...interface{}) {
+    message := fmt.Sprintf(format, args...)
+    if c.warned[message] {
+        return
+    }
+    c.warned[message] = true
+    c.input.warn(message)
+}
+
+func (c *vdConverter) indent() string {
+    return strings.Repeat("    ", c.depth)
+}
+
+// openGroup abre un <group> con la transformación descompuesta
+func (c *vdConverter) openGroup(m affine) {
+    c.b.WriteString(c.indent() + "<group")
+    if tx, ty, rotation, sx, sy, ok := m.decompose(); ok {
+        attrs := []struct {
+            name         string
+            value, unset float64
+        }{
+            {"translateX", tx, 0},
+            {"translateY", ty, 0},
+            {"rotation", rotation, 0},
+            {"scaleX", sx, 1},
+            {"scaleY", sy, 1},
+        }
+        for _, a := range attrs {
+            if formatNumber(a.value, c.precision) != formatNumber(a.unset, c.precision) {
+                fmt.Fprintf(&c.b, "\n%s    android:%s=\"%s\"", c.indent(), a.name, formatNumber(a.value, c.precision))
+            }
+        }
+    }
+    c.b.WriteString(">\n")
+    c.depth++
+}
+
+func (c *vdConverter) closeGroup() {
+    c.depth--
+    c.b.WriteString(c.indent() + "</group>\n")
+}
+
+// children convierte los hijos de un contenedor. bake es la transformación que
+// no se puede expresar con atributos de <group> y se aplica a las coordenadas.
+func (c *vdConverter) children(parent *svgNode, style svgStyle, bake affine, opacity float64) {
+    for _, child := range parent.elements() {
+        c.element(child, style, bake, opacity)
+    }
+}
+
+func (c *vdConverter) element(n *svgNode, parentStyle svgStyle, bake affine, opacity float64) {
+    switch n.Name {
+    case "title", "desc", "metadata", "defs":
+        return
+    case "g", "a", "svg", "path", "rect", "circle", "ellipse", "line", "polyline", "polygon":
+    case "style":
+        c.warn("hoja de estilos <style> no soportada en VectorDrawable, se ignoran sus reglas")
+        return
+    default:
+        c.warn("elemento <%s> no soportado en VectorDrawable, se omite", n.Name)
+        return
+    }
+    
+    style := parentStyle.inherit(n)
+    if isHidden(n, style) {
+        return
+    }
+    for _, attr := range []string{"clip-path", "mask", "filter"} {
+        if value, ok := n.property(attr); ok && value != "none" {
+            c.warn("atributo %s no soportado en VectorDrawable, se ignora", attr)
+        }
+    }
+    if _, ok := n.attr("class"); ok {
+        c.warn("atributo class no soportado en VectorDrawable, se ignora")
+    }
+    
+    transform, err := elementTransform(n)
+    if err != nil {
+        c.warn("%v", err)
+        transform = identityAffine
+    }
+    opacity *= elementOpacity(n)
+    
+    // Con una transformación sin sesgo y nada pendiente de aplicar se usa <group>
+    grouped := false
+    if !transform.isIdentity() {
+        if _, _, _, _, _, ok := transform.decompose(); ok && bake.isIdentity() {
+            c.openGroup(transform)
+            grouped = true
+        } else {
+            bake = bake.multiply(transform)
+        }
+    }
+    
+    if isShapeElement(n.Name) {
+        c.path(n, style, bake, opacity)
+    } else {
+        if n.Name == "svg" {
+            c.warn("elementos <svg> anidados se convierten como grupos, sin viewport propio")
+        }
+        if !grouped && n.Name != "svg" {
+            c.openGroup(identityAffine)
+            grouped = true
+        }
+        c.children(n, style, bake, opacity)
+    }
+    
+    if grouped {
+        c.closeGroup()
+    }
+}
+
+// path escribe un <path> con relleno y trazo resueltos
+func (c *vdConverter) path(n *svgNode, style svgStyle, bake affine, opacity float64) {
+    segments, err := shapeToPath(n)
+    if err != nil {
+        c.warn("geometría no válida en <%s>: %v", n.Name, err)
+        return
+    }
+    if len(segments) == 0 {
+        return
+    }
+    segments = transformPath(segments, bake)
+    
+    if value := style.value("stroke-dasharray"); value != "" && value != "none" {
+        c.warn("stroke-dasharray no soportado en VectorDrawable, el trazo será continuo")
+    }
+    
+    fill := c.resolvePaint(style, "fill")
+    stroke := c.resolvePaint(style, "stroke")
+    if fill.None && stroke.None {
+        return
+    }
+    
+    ind := c.indent() + "    "
+    c.b.WriteString(c.indent() + "<path")
+    fmt.Fprintf(&c.b, "\n%sandroid:pathData=\"%s\"", ind, formatPathData(segments, c.precision))
+    if !fill.None {
+        fmt.Fprintf(&c.b, "\n%sandroid:fillColor=\"%s\"", ind, androidColorValue(fill.Color))
+        if alpha := style.number("fill-opacity") * opacity; alpha < 1 {
+            fmt.Fprintf(&c.b, "\n%sandroid:fillAlpha=\"%s\"", ind, formatNumber(alpha, 3))
+        }
+        if style.value("fill-rule") == "evenodd" {
+            fmt.Fprintf(&c.b, "\n%sandroid:fillType=\"evenOdd\"", ind)
+        }
+    }
+    if !stroke.None {
+        width := style.number("stroke-width") * bake.scaleFactor()
+        fmt.Fprintf(&c.b, "\n%sandroid:strokeColor=\"%s\"", ind, androidColorValue(stroke.Color))
+        fmt.Fprintf(&c.b, "\n%sandroid:strokeWidth=\"%s\"", ind, formatNumber(width, c.precision))
+        if alpha := style.number("stroke-opacity") * opacity; alpha < 1 {
+            fmt.Fprintf(&c.b, "\n%sandroid:strokeAlpha=\"%s\"", ind, formatNumber(alpha, 3))
+        }
+        lineCap, lineJoin := style.lineStyle(c.warn)
+        if lineCap != "butt" {
+            fmt.Fprintf(&c.b, "\n%sandroid:strokeLineCap=\"%s\"", ind, lineCap)
+        }
+        if lineJoin != "miter" {
+            fmt.Fprintf(&c.b, "\n%sandroid:strokeLineJoin=\"%s\"", ind, lineJoin)
+        }
+        if limit := style.number("stroke-miterlimit"); limit != 4 {
+            fmt.Fprintf(&c.b, "\n%sandroid:strokeMiterLimit=\"%s\"", ind, formatNumber(limit, 3))
+        }
+    }
+    c.b.WriteString("/>\n")
+}
+
+// resolvePaint resuelve fill o stroke; los degradados y colores no válidos se
+// omiten con un aviso
+func (c *vdConverter) resolvePaint(style svgStyle, name string) svgPaint {
+    paint, err := style.paint(name, c.input.Color)
+    if err != nil {
+        c.warn("%s: %v, se omite", name, err)
+        return svgPaint{None: true}
+    }
+    if paint.URL != "" {
+        c.warn("%s con referencia %s (degradado o patrón) no soportado en VectorDrawable, se omite", name, paint.URL)
+        return svgPaint{None: true}
+    }
+    return paint
+}
+
+// androidColorValue escribe el color como #RRGGBB o #AARRGGBB
+func androidColorValue(c color.NRGBA) string {
+    if c.A == 0xff {
+        return fmt.Sprintf("#%02X%02X%02X", c.R, c.G, c.B)
+    }
+    return fmt.Sprintf("#%02X%02X%02X%02X", c.A, c.R, c.G, c.B)
+}
@@ -0,0 +1,119 @@
+// iconexporter/vectordrawable_test.go
+package iconexporter
+
+import (
+    "bytes"
+    "strings"
+    "testing"
+)
+
+// encodeVectorDrawable convierte un SVG y devuelve el XML y los avisos
+func encodeVectorDrawable(t *testing.T, svg []byte) (string, []string) {
+    t.Helper()
+    var warnings []string
+    var buf bytes.Buffer
+    input := EncodeInput{
+        Icon:   "prueba",
+        Color:  "#123456",
+        Width:  24,
+        Height: 24,
+        SVG:    svg,
+        Warn:   func(message string) { warnings = append(warnings, message) },
+    }
+    if err := (vectorDrawableFormat{}).Encode(&buf, input, nil); err != nil {
+        t.Fatal(err)
+    }
+    return buf.String(), warnings
+}
+
+func TestVectorDrawable(t *testing.T) {
+    tests := []struct {
+        name     string
+        body     string
+        contains []string
+        excludes []string
+        warnings []string
+    }{
+        {
+            name:     "relleno con currentColor",
+            body:     `<path d="M2 2h4v4z" fill="currentColor"/>`,
+            contains: []string{`android:pathData="M2 2L6 2L6 6Z"`, `android:fillColor="#123456"`},
+            excludes: []string{"<group", "strokeColor"},
+        },
+        {
+            name:     "formas básicas a path",
+            body:     `<rect x="1" y="1" width="4" height="2"/><circle cx="12" cy="12" r="4"/>`,
+            contains: []string{`android:pathData="M1 1L5 1L5 3L1 3Z"`, `android:pathData="M8 12A4 4 0 1 0 16 12A4 4 0 1 0 8 12Z"`},
+        },
+        {
+            name: "trazo con remates",
+            body: `<path d="M1 1L5 5" fill="none" stroke="#f00" stroke-width="2" stroke-linecap="round" stroke-linejoin="bevel" stroke-opacity=".5"/>`,
+            contains: []string{`android:strokeColor="#FF0000"`, `android:strokeWidth="2"`, `android:strokeAlpha="0.5"`,
+                `android:strokeLineCap="round"`, `android:strokeLineJoin="bevel"`},
+            excludes: []string{"fillColor"},
+        },
+        {
+            name:     "evenodd y opacidad heredada",
+            body:     `<g opacity="0.5"><path d="M0 0h4v4z" fill-rule="evenodd" fill-opacity="0.5"/></g>`,
+            contains: []string{`android:fillAlpha="0.25"`, `android:fillType="evenOdd"`, "<group>"},
+        },
+        {
+            name:     "transformación como grupo",
+            body:     `<g transform="translate(1,2) rotate(90)"><path d="M0 0h1v1z"/></g>`,
+            contains: []string{`android:translateX="1"`, `android:translateY="2"`, `android:rotation="90"`},
+        },
+        {
+            name:     "sesgo aplicado a las coordenadas",
+            body:     `<g transform="skewX(45)"><path d="M0 2h1"/></g>`,
+            contains: []string{`android:pathData="M2 2L3 2"`},
+            excludes: []string{"android:translateX", "android:rotation"},
+        },
+        {
+            name:     "elementos no soportados",
+            body:     `<style>.a{fill:red}</style><text>hola</text><path class="a" d="M0 0h1v1z" fill="url(#g)" stroke="blue" stroke-dasharray="2"/>`,
+            contains: []string{`android:strokeColor="#0000FF"`},
+            excludes: []string{"fillColor"},
+            warnings: []string{"<style>", "<text>", "atributo class", "referencia #g", "stroke-dasharray"},
+        },
+        {
+            name:     "ocultos y descriptivos",
+            body:     `<title>x</title><path d="M0 0h1v1z" display="none"/><path d="M0 0h1v1z" fill="none"/>`,
+            excludes: []string{"<path"},
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            xml, warnings := encodeVectorDrawable(t, testSvg(tt.body))
+            for _, want := range tt.contains {
+                if !strings.Contains(xml, want) {
+                    t.Errorf("falta %s en:\n%s", want, xml)
+                }
+            }
+            for _, unwanted := range tt.excludes {
+                if strings.Contains(xml, unwanted) {
+                    t.Errorf("sobra %s en:\n%s", unwanted, xml)
+                }
+            }
+            joined := strings.Join(warnings, "\n")
+            for _, want := range tt.warnings {
+                if !strings.Contains(joined, want) {
+                    t.Errorf("falta el aviso %q en:\n%s", want, joined)
+                }
+            }
+            if len(tt.warnings) == 0 && len(warnings) > 0 {
+                t.Errorf("avisos inesperados: %v", warnings)
+            }
+        })
+    }
+}
+
+func TestVectorDrawableViewport(t *testing.T) {
+    svg := []byte(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="2 3 20 10"><path d="M2 3h1v1z"/></svg>`)
+    xml, _ := encodeVectorDrawable(t, svg)
+    for _, want := range []string{`android:width="24dp"`, `android:viewportWidth="20"`, `android:viewportHeight="10"`,
+        `android:translateX="-2"`, `android:translateY="-3"`} {
+        if !strings.Contains(xml, want) {
+            t.Errorf("falta %s en:\n%s", want, xml)
+        }
+    }
+}
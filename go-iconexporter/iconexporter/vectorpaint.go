@@ -0,0 +1,202 @@
+// iconexporter/vectorpaint.go
+package iconexporter
+
+import (
+    "fmt"
+    "image/color"
+    "strings"
+)
+
+// vectorPainter traduce las formas aplanadas a operadores de dibujo de PDF o
+// de PostScript; ambos comparten el modelo de trayectos, relleno y trazo
+type vectorPainter struct {
+    b          strings.Builder
+    postscript bool
+    // alphaState devuelve el nombre de un ExtGState con las opacidades (solo PDF)
+    alphaState func(fill, stroke float64) string
+    warn       func(message string)
+}
+
+// Códigos de extremo y unión de línea, iguales en PDF y PostScript
+var (
+    vectorLineCaps  = map[string]int{"butt": 0, "round": 1, "square": 2}
+    vectorLineJoins = map[string]int{"miter": 0, "round": 1, "bevel": 2}
+)
+
+func (v *vectorPainter) num(x float64) string {
+    return formatNumber(x, 3)
+}
+
+// op escribe un operador con sus operandos; PDF y PostScript usan nombres distintos
+func (v *vectorPainter) op(pdf, ps string, operands ...float64) {
+    for _, operand := range operands {
+        v.b.WriteString(v.num(operand) + " ")
+    }
+    if v.postscript {
+        v.b.WriteString(ps + "\n")
+    } else {
+        v.b.WriteString(pdf + "\n")
+    }
+}
+
+// color fija el color de relleno (stroke=false) o de trazo
+func (v *vectorPainter) color(c color.NRGBA, stroke bool) {
+    r, g, b := float64(c.R)/255, float64(c.G)/255, float64(c.B)/255
+    switch {
+    case v.postscript:
+        v.op("", "setrgbcolor", r, g, b)
+    case stroke:
+        v.op("RG", "", r, g, b)
+    default:
+        v.op("rg", "", r, g, b)
+    }
+}
+
+// path escribe el trayecto; las cuadráticas se elevan a cúbicas
+func (v *vectorPainter) path(segments []pathSegment) {
+    if v.postscript {
+        v.b.WriteString("newpath\n")
+    }
+    var cx, cy, sx, sy float64
+    for _, seg := range pathWithoutArcs(segments) {
+        a := seg.Args
+        switch seg.Cmd {
+        case 'M':
+            v.op("m", "moveto", a[0], a[1])
+            cx, cy, sx, sy = a[0], a[1], a[0], a[1]
+        case 'L':
+            v.op("l", "lineto", a[0], a[1])
+            cx, cy = a[0], a[1]
+        case 'C':
+            v.op("c", "curveto", a...)
+            cx, cy = a[4], a[5]
+        case 'Q':
+            c1x, c1y := cx+2.0/3*(a[0]-cx), cy+2.0/3*(a[1]-cy)
+            c2x, c2y := a[2]+2.0/3*(a[0]-a[2]), a[3]+2.0/3*(a[1]-a[3])
+            v.op("c", "curveto", c1x, c1y, c2x, c2y, a[2], a[3])
+            cx, cy = a[2], a[3]
+        case 'Z':
+            v.op("h", "closepath")
+            cx, cy = sx, sy
+        }
+    }
+}
+
+// strokeStyle fija grosor, extremos, uniones y discontinuidad del trazo
+func (v *vectorPainter) strokeStyle(shape flatShape) {
+    v.op("w", "setlinewidth", shape.StrokeWidth)
+    lineCap, lineJoin := vectorLineCaps[shape.LineCap], vectorLineJoins[shape.LineJoin]
+    if v.postscript {
+        fmt.Fprintf(&v.b, "%d setlinecap %d setlinejoin\n", lineCap, lineJoin)
+    } else {
+        fmt.Fprintf(&v.b, "%d J %d j\n", lineCap, lineJoin)
+    }
+    v.op("M", "setmiterlimit", shape.Style.number("stroke-miterlimit"))
+    
+    dash := shape.Style.value("stroke-dasharray")
+    if dash == "" || dash == "none" {
+        return
+    }
+    var lengths []string
+    for _, field := range strings.FieldsFunc(dash, func(r rune) bool { return r == ',' || r == ' ' }) {
+        length, ok := parseLength(field)
+        if !ok || length < 0 {
+            v.warn(fmt.Sprintf("stroke-dasharray no válido: %s, se dibuja continuo", dash))
+            return
+        }
+        lengths = append(lengths, v.num(length))
+    }
+    if len(lengths)%2 == 1 {
+        lengths = append(lengths, lengths...)
+    }
+    offset, _ := parseLength(shape.Style.value("stroke-dashoffset"))
+    operator := "d"
+    if v.postscript {
+        operator = "setdash"
+    }
+    fmt.Fprintf(&v.b, "[%s] %s %s\n", strings.Join(lengths, " "), v.num(offset), operator)
+}
+
+// drawIcon dibuja las formas ajustando el viewBox (centrado, sin deformar) al
+// rectángulo (x, y, w, h) en puntos, con el origen abajo a la izquierda
+func (v *vectorPainter) drawIcon(shapes []flatShape, box [4]float64, x, y, w, h float64) {
+    if box[2] <= 0 || box[3] <= 0 {
+        return
+    }
+    scale := min(w/box[2], h/box[3])
+    tx := x + (w-box[2]*scale)/2 - box[0]*scale
+    ty := y + h - (h-box[3]*scale)/2 + box[1]*scale
+    
+    if v.postscript {
+        fmt.Fprintf(&v.b, "gsave\n[%s 0 0 %s %s %s] concat\n", v.num(scale), v.num(-scale), v.num(tx), v.num(ty))
+    } else {
+        fmt.Fprintf(&v.b, "q\n%s 0 0 %s %s %s cm\n", v.num(scale), v.num(-scale), v.num(tx), v.num(ty))
+    }
+    
+    for _, shape := range shapes {
+        fill, stroke := !shape.Fill.None, !shape.Stroke.None
+        fillAlpha := float64(shape.Fill.Color.A) / 255 * shape.FillOpacity
+        strokeAlpha := float64(shape.Stroke.Color.A) / 255 * shape.StrokeOpacity
+        if !fill {
+            fillAlpha = 1
+        }
+        if !stroke {
+            strokeAlpha = 1
+        }
+        
+        v.op("q", "gsave")
+        if fillAlpha < 1 || strokeAlpha < 1 {
+            if v.postscript {
+                v.warn("la transparencia no está soportada en PostScript, se dibuja opaco")
+            } else {
+                fmt.Fprintf(&v.b, "/%s gs\n", v.alphaState(fillAlpha, strokeAlpha))
+            }
+        }
+        if stroke {
+            v.strokeStyle(shape)
+        }
+        
+        // Operador de relleno según la regla: f/f* en PDF, fill/eofill en PostScript
+        fillOperator := "f"
+        switch {
+        case v.postscript && shape.EvenOdd:
+            fillOperator = "eofill"
+        case v.postscript:
+            fillOperator = "fill"
+        case shape.EvenOdd:
+            fillOperator = "f*"
+        }
+        
+        v.path(shape.Segments)
+        switch {
+        case v.postscript && fill && stroke:
+            // PostScript consume el trayecto al rellenar; se conserva con gsave
+            v.b.WriteString("gsave\n")
+            v.color(shape.Fill.Color, false)
+            v.b.WriteString(fillOperator + "\ngrestore\n")
+            v.color(shape.Stroke.Color, true)
+            v.b.WriteString("stroke\n")
+        case v.postscript && fill:
+            v.color(shape.Fill.Color, false)
+            v.b.WriteString(fillOperator + "\n")
+        case v.postscript:
+            v.color(shape.Stroke.Color, true)
+            v.b.WriteString("stroke\n")
+        default:
+            operator := "S"
+            if fill {
+                v.color(shape.Fill.Color, false)
+                operator = fillOperator
+            }
+            if stroke {
+                v.color(shape.Stroke.Color, true)
+                if fill {
+                    operator = strings.Replace(strings.ToUpper(fillOperator), "F", "B", 1)
+                }
+            }
+            v.b.WriteString(operator + "\n")
+        }
+        v.op("Q", "grestore")
+    }
+    v.op("Q", "grestore")
+}
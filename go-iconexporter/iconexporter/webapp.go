@@ -0,0 +1,345 @@
+// iconexporter/webapp.go
+package iconexporter
+
+import (
+    "encoding/json"
+    "fmt"
+    "html"
+    "image"
+    "io"
+    "path/filepath"
+    "sort"
+    "strings"
+    "sync"
+    "time"
+
+    "github.com/disintegration/imaging"
+)
+
+// WebAppConfig configura el modo "web app icons" (favicons y PWA)
+type WebAppConfig struct {
+    Name            string  `json:"name"`
+    ShortName       string  `json:"shortName"`
+    ThemeColor      string  `json:"themeColor"`
+    BackgroundColor string  `json:"backgroundColor"`
+    Padding         float64 `json:"padding"`
+    MaskablePadding float64 `json:"maskablePadding"`
+    BasePath        string  `json:"basePath"`
+    FaviconSizes    []int   `json:"faviconSizes"`
+}
+
+// webAppManifestIcon es una entrada de "icons" en manifest.webmanifest
+type webAppManifestIcon struct {
+    Src     string `json:"src"`
+    Sizes   string `json:"sizes"`
+    Type    string `json:"type"`
+    Purpose string `json:"purpose"`
+}
+
+// webAppManifest es el contenido de manifest.webmanifest
+type webAppManifest struct {
+    Name            string               `json:"name"`
+    ShortName       string               `json:"short_name"`
+    Icons           []webAppManifestIcon `json:"icons"`
+    ThemeColor      string               `json:"theme_color"`
+    BackgroundColor string               `json:"background_color"`
+    Display         string               `json:"display"`
+}
+
+// webAppConfig completa la configuración con valores por defecto
+func (e *IconExporter) webAppConfig(iconName, col string) WebAppConfig {
+    cfg := e.config.WebApp
+    if cfg.Name == "" {
+        cfg.Name = iconName
+    }
+    if cfg.ShortName == "" {
+        cfg.ShortName = cfg.Name
+    }
+    if cfg.ThemeColor == "" {
+        cfg.ThemeColor = col
+    }
+    if cfg.BackgroundColor == "" {
+        cfg.BackgroundColor = "#ffffff"
+    }
+    if cfg.Padding <= 0 {
+        cfg.Padding = 0.1
+    }
+    if cfg.MaskablePadding <= 0 {
+        // El área segura de un icono maskable es un círculo del 80%
+        cfg.MaskablePadding = 0.2
+    }
+    if cfg.BasePath == "" {
+        cfg.BasePath = "/"
+    }
+    if !strings.HasSuffix(cfg.BasePath, "/") {
+        cfg.BasePath += "/"
+    }
+    if len(cfg.FaviconSizes) == 0 {
+        cfg.FaviconSizes = []int{16, 32, 48}
+    }
+    return cfg
+}
+
+// renderPadded rasteriza el icono con márgenes sobre un fondo opcional
+func (e *IconExporter) renderPadded(icon Icon, size int, col string, padding float64, background string) (*image.NRGBA, error) {
+    margin := int(float64(size)*padding + 0.5)
+    inner := size - 2*margin
+    if inner < 1 {
+        return nil, fmt.Errorf("margen demasiado grande para %dx%d", size, size)
+    }
+    
+    img, err := e.rasterizeSvg(e.prepareSvgBuffer(icon, inner, inner, col, ""), inner, inner)
+    if err != nil {
+        return nil, err
+    }
+    
+    canvas := image.NewNRGBA(image.Rect(0, 0, size, size))
+    if background != "" {
+        if canvas, err = flattenImage(canvas, background); err != nil {
+            return nil, err
+        }
+    }
+    return imaging.Overlay(canvas, img, image.Pt(margin, margin), 1.0), nil
+}
+
+// processWebApp genera el paquete de favicons y PWA de un icono y color
+func (e *IconExporter) processWebApp(iconData IconData, collection, iconName, col string, multipleColors bool) ([]ExportResult, error) {
+    var results []ExportResult
+    
+    icon, exists := iconData.Icons[iconName]
+    if !exists {
+        return nil, fmt.Errorf("icono '%s' no encontrado en %s", iconName, collection)
+    }
+    
+    cfg := e.webAppConfig(iconName, col)
+    folderName := iconName
+    if multipleColors {
+        folderName = fmt.Sprintf("%s-%s", iconName, strings.ReplaceAll(col, "#", ""))
+    }
+    folderPath := filepath.Join(
+        e.generateFolderPath(collection, map[string]interface{}{"width": 512, "height": 512, "color": col}),
+        e.applyCase(folderName, e.config.FileNaming.Case),
+    )
+    if err := e.ensureOutputDir(folderPath); err != nil {
+        return nil, fmt.Errorf("error creando directorio: %w", err)
+    }
+    
+    // Las imágenes rasterizadas pasan por el análisis previo; el resto de
+    // archivos se escribe siempre
+    preflight := e.rasterPreflight(e.prepareSvgBuffer(icon, icon.Width, icon.Height, col, ""))
+    
+    // size es 0 en los archivos que no son imágenes de un solo tamaño
+    save := func(fileName string, size int, raster bool, write func(w io.Writer) error) {
+        filePath := filepath.Join(folderPath, fileName)
+        result := ExportResult{
+            Collection: collection,
+            Icon:       iconName,
+            Format:     strings.TrimPrefix(filepath.Ext(fileName), "."),
+            Path:       filePath,
+            Width:      size,
+            Height:     size,
+            Color:      col,
+        }
+        if raster && !preflight(&result) {
+            results = append(results, result)
+            return
+        }
+        if err := writeFile(filePath, write); err != nil {
+            fmt.Printf("❌ Error al guardar %s para '%s' (%s): %v\n", fileName, iconName, col, err)
+            result.Error = err.Error()
+        } else {
+            fmt.Printf("✅ Exportado: %s\n", filePath)
+        }
+        printWarnings(result)
+        results = append(results, result)
+    }
+    savePNG := func(fileName string, size int, padding float64, background string) {
+        save(fileName, size, true, func(w io.Writer) error {
+            img, err := e.renderPadded(icon, size, col, padding, background)
+            if err != nil {
+                return err
+            }
+            return encodePNG(w, img, nil)
+        })
+    }
+    
+    // favicon.ico con varias resoluciones y favicon.svg escalable
+    save("favicon.ico", 0, true, func(w io.Writer) error {
+        inputs := make([]EncodeInput, 0, len(cfg.FaviconSizes))
+        for _, size := range cfg.FaviconSizes {
+            img, err := e.rasterizeSvg(e.prepareSvgBuffer(icon, size, size, col, ""), size, size)
+            if err != nil {
+                return err
+            }
+            inputs = append(inputs, EncodeInput{Width: size, Height: size, Image: img})
+        }
+        return icoFormat{}.EncodeBundle(w, inputs, nil)
+    })
+    save("favicon.svg", 0, false, func(w io.Writer) error {
+        _, err := w.Write(e.prepareSvgBuffer(icon, icon.Width, icon.Height, col, e.svgIDPrefix(collection, iconName)))
+        return err
+    })
+    
+    // iOS no admite transparencia: fondo y margen
+    savePNG("apple-touch-icon.png", 180, cfg.Padding, cfg.BackgroundColor)
+    savePNG("mstile-150x150.png", 150, cfg.Padding, "")
+    for _, size := range []int{192, 512} {
+        savePNG(fmt.Sprintf("icon-%d.png", size), size, 0, "")
+        savePNG(fmt.Sprintf("icon-%d-maskable.png", size), size, cfg.MaskablePadding, cfg.BackgroundColor)
+    }
+    
+    // manifest.webmanifest
+    manifest := webAppManifest{
+        Name:            cfg.Name,
+        ShortName:       cfg.ShortName,
+        ThemeColor:      cfg.ThemeColor,
+        BackgroundColor: cfg.BackgroundColor,
+        Display:         "standalone",
+    }
+    for _, size := range []int{192, 512} {
+        sizes := fmt.Sprintf("%dx%d", size, size)
+        manifest.Icons = append(manifest.Icons,
+            webAppManifestIcon{cfg.BasePath + fmt.Sprintf("icon-%d.png", size), sizes, "image/png", "any"},
+            webAppManifestIcon{cfg.BasePath + fmt.Sprintf("icon-%d-maskable.png", size), sizes, "image/png", "maskable"},
+        )
+    }
+    save("manifest.webmanifest", 0, false, func(w io.Writer) error {
+        encoder := json.NewEncoder(w)
+        encoder.SetIndent("", "  ")
+        return encoder.Encode(manifest)
+    })
+    
+    // browserconfig.xml para los mosaicos de Windows
+    save("browserconfig.xml", 0, false, func(w io.Writer) error {
+        _, err := fmt.Fprintf(w, `<?xml version="1.0" encoding="utf-8"?>
+<browserconfig>
+  <msapplication>
+    <tile>
+      <square150x150logo src="%s"/>
+      <TileColor>%s</TileColor>
+    </tile>
+  </msapplication>
+</browserconfig>
+`, html.EscapeString(cfg.BasePath+"mstile-150x150.png"), html.EscapeString(cfg.ThemeColor))
+        return err
+    })
+    
+    // Fragmento HTML listo para pegar en <head>
+    save("head.html", 0, false, func(w io.Writer) error {
+        base := html.EscapeString(cfg.BasePath)
+        _, err := fmt.Fprintf(w, `<link rel="icon" href="%[1]sfavicon.ico" sizes="any">
+<link rel="icon" href="%[1]sfavicon.svg" type="image/svg+xml">
+<link rel="apple-touch-icon" href="%[1]sapple-touch-icon.png">
+<link rel="manifest" href="%[1]smanifest.webmanifest">
+<meta name="msapplication-config" content="%[1]sbrowserconfig.xml">
+<meta name="theme-color" content="%[2]s">
+`, base, html.EscapeString(cfg.ThemeColor))
+        return err
+    })
+    
+    return results, nil
+}
+
+// ExportWebAppIcons genera favicons, iconos PWA, manifest y fragmento HTML para
+// cada icono seleccionado y cada color
+func (e *IconExporter) ExportWebAppIcons(colors []string) (ExportSummary, error) {
+    startTime := time.Now()
+    
+    if len(colors) == 0 {
+        colors = []string{e.config.DefaultColor}
+    }
+    
+    type webAppOutcome struct {
+        results []ExportResult
+        err     error
+    }
+    
+    var totalProcessed, totalErrors, totalSkipped int
+    var allResults []ExportResult
+    var wg sync.WaitGroup
+    outcomesChan := make(chan webAppOutcome)
+    
+    if err := e.ensureOutputDir(e.config.OutputDir); err != nil {
+        return ExportSummary{}, fmt.Errorf("error creando directorio de salida: %w", err)
+    }
+    
+    for _, collection := range e.config.Collections {
+        iconData, err := e.loadCollectionData(collection)
+        if err != nil {
+            fmt.Printf("❌ Error cargando colección %s: %v\n", collection, err)
+            continue
+        }
+        
+        icons := e.getIconsToProcess(iconData)
+        fmt.Printf("\n🌐 Generando iconos web app: %s (%d iconos)\n", collection, len(icons))
+        
+        for _, iconName := range icons {
+            if _, exists := iconData.Icons[iconName]; !exists {
+                fmt.Printf("⚠️ Icono '%s' no encontrado en %s\n", iconName, collection)
+                totalErrors += len(colors)
+                continue
+            }
+            
+            for _, col := range colors {
+                wg.Add(1)
+                
+                go func(coll, name, clr string) {
+                    defer wg.Done()
+                    
+                    results, err := e.processWebApp(iconData, coll, name, clr, len(colors) > 1)
+                    outcomesChan <- webAppOutcome{results, err}
+                }(collection, iconName, col)
+            }
+        }
+    }
+    
+    go func() {
+        wg.Wait()
+        close(outcomesChan)
+    }()
+    
+    for outcome := range outcomesChan {
+        if outcome.err != nil {
+            fmt.Printf("❌ %v\n", outcome.err)
+            totalErrors++
+        }
+        for _, result := range outcome.results {
+            switch {
+            case result.Skipped:
+                totalSkipped++
+            case result.Error != "":
+                totalErrors++
+            default:
+                totalProcessed++
+            }
+        }
+        allResults = append(allResults, outcome.results...)
+    }
+    sort.SliceStable(allResults, func(i, j int) bool {
+        return allResults[i].Path < allResults[j].Path
+    })
+    
+    duration := time.Since(startTime).Seconds()
+    if totalSkipped > 0 {
+        fmt.Printf("\n⏭️ Omitidos por el análisis previo: %d\n", totalSkipped)
+    }
+    e.printExportSummary(totalProcessed, totalErrors, duration)
+    
+    return ExportSummary{
+        Processed: totalProcessed,
+        Errors:    totalErrors,
+        Skipped:   totalSkipped,
+        Duration:  duration,
+        Results:   allResults,
+        Sanitized: e.sanitizeReports(),
+    }, nil
+}
+
+// ExportWebAppIcons genera el paquete de favicons y PWA con la configuración dada
+func ExportWebAppIcons(config Config, colors []string) (ExportSummary, error) {
+    exporter, err := NewIconExporter(config)
+    if err != nil {
+        return ExportSummary{}, err
+    }
+    return exporter.ExportWebAppIcons(colors)
+}
@@ -0,0 +1,119 @@
+// iconexporter/webapp_test.go
+package iconexporter
+
+import (
+    "encoding/json"
+    "fmt"
+    "image/png"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+func TestExportWebAppIcons(t *testing.T) {
+    e := newTestExporter(t, Config{
+        FileNaming:      FileNamingConfig{Case: "kebab"},
+        FolderStructure: FolderStructureConfig{Enabled: true, Pattern: "{collection}"},
+        WebApp:          WebAppConfig{BasePath: "/static"},
+    })
+    summary, err := e.ExportWebAppIcons([]string{"#FF5733", "green"})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if summary.Processed != 2*2*11 || summary.Errors != 0 || len(summary.Results) != summary.Processed {
+        t.Fatalf("resumen inesperado: %d procesados, %d errores, %d resultados",
+            summary.Processed, summary.Errors, len(summary.Results))
+    }
+    
+    folder := filepath.Join(e.config.OutputDir, "nonicons", "bell-ff5733")
+    tests := []struct {
+        file   string
+        format string
+        size   int
+    }{
+        {"favicon.ico", "ico", 0},
+        {"favicon.svg", "svg", 0},
+        {"apple-touch-icon.png", "png", 180},
+        {"mstile-150x150.png", "png", 150},
+        {"icon-192.png", "png", 192},
+        {"icon-512-maskable.png", "png", 512},
+        {"manifest.webmanifest", "webmanifest", 0},
+        {"browserconfig.xml", "xml", 0},
+        {"head.html", "html", 0},
+    }
+    for _, tt := range tests {
+        t.Run(tt.file, func(t *testing.T) {
+            path := filepath.Join(folder, tt.file)
+            var result *ExportResult
+            for i := range summary.Results {
+                if summary.Results[i].Path == path {
+                    result = &summary.Results[i]
+                }
+            }
+            if result == nil {
+                t.Fatalf("sin resultado para %s", path)
+            }
+            if result.Format != tt.format || result.Width != tt.size || result.Color != "#FF5733" {
+                t.Fatalf("resultado inesperado: %+v", *result)
+            }
+            if tt.format != "png" {
+                return
+            }
+            file, err := os.Open(path)
+            if err != nil {
+                t.Fatal(err)
+            }
+            defer file.Close()
+            config, err := png.DecodeConfig(file)
+            if err != nil || config.Width != tt.size || config.Height != tt.size {
+                t.Fatalf("PNG de %dx%d (%v), se esperaba %d", config.Width, config.Height, err, tt.size)
+            }
+        })
+    }
+    
+    var manifest webAppManifest
+    data, _ := os.ReadFile(filepath.Join(folder, "manifest.webmanifest"))
+    if err := json.Unmarshal(data, &manifest); err != nil {
+        t.Fatal(err)
+    }
+    if manifest.Name != "bell" || manifest.ThemeColor != "#FF5733" || len(manifest.Icons) != 4 ||
+        manifest.Icons[1].Src != "/static/icon-192-maskable.png" || manifest.Icons[1].Purpose != "maskable" {
+        t.Fatalf("manifest inesperado: %+v", manifest)
+    }
+}
+
+// Más de 100 fallos no deben bloquear la exportación y cada uno queda en Results
+func TestExportWebAppIconsCountsFailures(t *testing.T) {
+    colors := make([]string, 26)
+    for i := range colors {
+        colors[i] = fmt.Sprintf("#0000%02x", i)
+    }
+    // Con un margen del 50% los iconos maskable no tienen área útil
+    e := newTestExporter(t, Config{WebApp: WebAppConfig{MaskablePadding: 0.5}})
+    summary, err := e.ExportWebAppIcons(colors)
+    if err != nil {
+        t.Fatal(err)
+    }
+    
+    wantErrors := 2 * len(colors) * 2
+    if summary.Errors != wantErrors || summary.Processed != 2*len(colors)*9 {
+        t.Fatalf("%d procesados y %d errores, se esperaban %d y %d",
+            summary.Processed, summary.Errors, 2*len(colors)*9, wantErrors)
+    }
+    failed := 0
+    for _, result := range summary.Results {
+        if result.Error != "" {
+            failed++
+            if !strings.Contains(result.Path, "maskable") || !strings.Contains(result.Error, "margen") {
+                t.Fatalf("fallo inesperado: %+v", result)
+            }
+            if _, err := os.Stat(result.Path); !os.IsNotExist(err) {
+                t.Fatalf("%s no debería existir", result.Path)
+            }
+        }
+    }
+    if failed != wantErrors {
+        t.Fatalf("%d resultados con error, se esperaban %d", failed, wantErrors)
+    }
+}
@@ -0,0 +1,513 @@
+// iconexporter/webp.go
+package iconexporter
+
+import (
+    "encoding/binary"
+    "fmt"
+    "image"
+    "image/color"
+    "io"
+)
+
+// Constantes del formato VP8L (WebP sin pérdida)
+const (
+    vp8lSignature       = 0x2f
+    vp8lMaxDimension    = 1 << 14
+    vp8lNumLengthCodes  = 24
+    vp8lNumDistCodes    = 40
+    vp8lMaxCodeLength   = 15
+    vp8lMaxCLCodeLength = 7
+    vp8lMinMatch        = 3
+    vp8lMaxMatch        = 4096
+    vp8lMaxDistance     = (1 << 20) - 120
+    vp8lHashBits        = 16
+    vp8lSubtractGreen   = 2
+)
+
+// Orden en el que se transmiten las longitudes del código de longitudes
+var vp8lCodeLengthOrder = [19]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// vp8lSymbol representa un píxel literal o una referencia hacia atrás
+type vp8lSymbol struct {
+    argb   uint32
+    length int
+    dist   int
+}
+
+// vp8lPrefixCode es un código prefijo canónico listo para escribir
+type vp8lPrefixCode struct {
+    lengths []uint8  // longitudes transmitidas
+    codes   []uint16 // códigos con los bits invertidos (LSB primero)
+    bits    []uint8  // bits realmente escritos por símbolo
+}
+
+// vp8lBitWriter escribe bits en orden LSB primero
+type vp8lBitWriter struct {
+    buf   []byte
+    acc   uint64
+    nbits uint
+}
+
+func (bw *vp8lBitWriter) writeBits(value uint32, n uint) {
+    bw.acc |= uint64(value) << bw.nbits
+    bw.nbits += n
+    for bw.nbits >= 8 {
+        bw.buf = append(bw.buf, byte(bw.acc))
+        bw.acc >>= 8
+        bw.nbits -= 8
+    }
+}
+
+func (bw *vp8lBitWriter) writeSymbol(code *vp8lPrefixCode, symbol int) {
+    bw.writeBits(uint32(code.codes[symbol]), uint(code.bits[symbol]))
+}
+
+func (bw *vp8lBitWriter) flush() []byte {
+    if bw.nbits > 0 {
+        bw.buf = append(bw.buf, byte(bw.acc))
+        bw.acc, bw.nbits = 0, 0
+    }
+    return bw.buf
+}
+
+func init() {
+    registerRasterFormat("webp", encodeWebP)
+}
+
+// encodeWebP escribe la imagen como WebP sin pérdida (VP8L).
+// Opciones: "lossless" (solo true por ahora) y "quality" (0-100), que en modo
+// sin pérdida controla el esfuerzo de compresión, igual que cwebp -lossless -q.
+func encodeWebP(w io.Writer, img image.Image, options map[string]interface{}) error {
+    if !optionBool(options, "lossless", true) {
+        return fmt.Errorf("WebP con pérdida (VP8) no está soportado todavía, usa lossless=true")
+    }
+    quality := optionInt(options, "quality", 75)
+    if quality < 0 || quality > 100 {
+        return fmt.Errorf("calidad WebP fuera de rango (0-100): %d", quality)
+    }
+    
+    bounds := img.Bounds()
+    width, height := bounds.Dx(), bounds.Dy()
+    if width < 1 || height < 1 || width > vp8lMaxDimension || height > vp8lMaxDimension {
+        return fmt.Errorf("dimensiones no válidas para WebP: %dx%d", width, height)
+    }
+    
+    // Convertir a ARGB sin premultiplicar y aplicar la transformación "subtract green"
+    pixels := make([]uint32, 0, width*height)
+    alphaUsed := false
+    for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+        for x := bounds.Min.X; x < bounds.Max.X; x++ {
+            c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+            if c.A != 0xff {
+                alphaUsed = true
+            }
+            r, b := c.R-c.G, c.B-c.G
+            pixels = append(pixels, uint32(c.A)<<24|uint32(r)<<16|uint32(c.G)<<8|uint32(b))
+        }
+    }
+    
+    symbols := vp8lBackwardRefs(pixels, quality)
+    
+    // Histogramas de los cinco códigos prefijo
+    green := make([]uint32, 256+vp8lNumLengthCodes)
+    red := make([]uint32, 256)
+    blue := make([]uint32, 256)
+    alpha := make([]uint32, 256)
+    dist := make([]uint32, vp8lNumDistCodes)
+    for _, s := range symbols {
+        if s.length == 0 {
+            green[(s.argb>>8)&0xff]++
+            red[(s.argb>>16)&0xff]++
+            blue[s.argb&0xff]++
+            alpha[s.argb>>24]++
+            continue
+        }
+        lengthCode, _, _ := vp8lPrefixEncode(s.length)
+        green[256+lengthCode]++
+        distCode, _, _ := vp8lPrefixEncode(s.dist + 120)
+        dist[distCode]++
+    }
+    
+    bw := &vp8lBitWriter{}
+    bw.writeBits(vp8lSignature, 8)
+    bw.writeBits(uint32(width-1), 14)
+    bw.writeBits(uint32(height-1), 14)
+    if alphaUsed {
+        bw.writeBits(1, 1)
+    } else {
+        bw.writeBits(0, 1)
+    }
+    bw.writeBits(0, 3) // versión
+    
+    bw.writeBits(1, 1) // hay transformación
+    bw.writeBits(vp8lSubtractGreen, 2)
+    bw.writeBits(0, 1) // fin de transformaciones
+    bw.writeBits(0, 1) // sin caché de color
+    bw.writeBits(0, 1) // un único grupo de códigos prefijo
+    
+    codes := make([]*vp8lPrefixCode, 0, 5)
+    for _, hist := range [][]uint32{green, red, blue, alpha, dist} {
+        code := vp8lBuildPrefixCode(hist, vp8lMaxCodeLength)
+        bw.writePrefixCode(code)
+        codes = append(codes, code)
+    }
+    greenCode, redCode, blueCode, alphaCode, distCode := codes[0], codes[1], codes[2], codes[3], codes[4]
+    
+    for _, s := range symbols {
+        if s.length == 0 {
+            bw.writeSymbol(greenCode, int((s.argb>>8)&0xff))
+            bw.writeSymbol(redCode, int((s.argb>>16)&0xff))
+            bw.writeSymbol(blueCode, int(s.argb&0xff))
+            bw.writeSymbol(alphaCode, int(s.argb>>24))
+            continue
+        }
+        code, extraBits, extra := vp8lPrefixEncode(s.length)
+        bw.writeSymbol(greenCode, 256+code)
+        bw.writeBits(extra, extraBits)
+        code, extraBits, extra = vp8lPrefixEncode(s.dist + 120)
+        bw.writeSymbol(distCode, code)
+        bw.writeBits(extra, extraBits)
+    }
+    
+    return writeWebPContainer(w, "VP8L", bw.flush())
+}
+
+// writeWebPContainer envuelve el bitstream en el contenedor RIFF de WebP
+func writeWebPContainer(w io.Writer, fourCC string, data []byte) error {
+    padding := len(data) & 1
+    header := make([]byte, 20)
+    copy(header[0:4], "RIFF")
+    binary.LittleEndian.PutUint32(header[4:8], uint32(4+8+len(data)+padding))
+    copy(header[8:12], "WEBP")
+    copy(header[12:16], fourCC)
+    binary.LittleEndian.PutUint32(header[16:20], uint32(len(data)))
+    
+    if _, err := w.Write(header); err != nil {
+        return err
+    }
+    if _, err := w.Write(data); err != nil {
+        return err
+    }
+    if padding == 1 {
+        _, err := w.Write([]byte{0})
+        return err
+    }
+    return nil
+}
+
+// vp8lBackwardRefs busca repeticiones LZ77 usando cadenas hash; la longitud de
+// las cadenas depende de la calidad solicitada (0 = solo literales)
+func vp8lBackwardRefs(pixels []uint32, quality int) []vp8lSymbol {
+    maxChain := quality * 2 / 5
+    symbols := make([]vp8lSymbol, 0, len(pixels))
+    if maxChain == 0 {
+        for _, p := range pixels {
+            symbols = append(symbols, vp8lSymbol{argb: p})
+        }
+        return symbols
+    }
+    
+    head := make([]int32, 1<<vp8lHashBits)
+    for i := range head {
+        head[i] = -1
+    }
+    prev := make([]int32, len(pixels))
+    hash := func(i int) uint32 {
+        return ((pixels[i] * 0x1e35a7bd) ^ (pixels[i+1] * 0x9e3779b1)) >> (32 - vp8lHashBits)
+    }
+    insert := func(i int) {
+        if i+1 >= len(pixels) {
+            return
+        }
+        h := hash(i)
+        prev[i] = head[h]
+        head[h] = int32(i)
+    }
+    
+    for i := 0; i < len(pixels); {
+        bestLen, bestDist := 0, 0
+        if i+vp8lMinMatch <= len(pixels) {
+            maxLen := len(pixels) - i
+            if maxLen > vp8lMaxMatch {
+                maxLen = vp8lMaxMatch
+            }
+            candidate := head[hash(i)]
+            for chain := 0; candidate >= 0 && chain < maxChain; chain++ {
+                j := int(candidate)
+                if i-j > vp8lMaxDistance {
+                    break
+                }
+                n := 0
+                for n < maxLen && pixels[j+n] == pixels[i+n] {
+                    n++
+                }
+                if n > bestLen {
+                    bestLen, bestDist = n, i-j
+                    if n == maxLen {
+                        break
+                    }
+                }
+                candidate = prev[j]
+            }
+        }
+        
+        if bestLen >= vp8lMinMatch {
+            symbols = append(symbols, vp8lSymbol{length: bestLen, dist: bestDist})
+            for k := 0; k < bestLen; k++ {
+                insert(i + k)
+            }
+            i += bestLen
+        } else {
+            symbols = append(symbols, vp8lSymbol{argb: pixels[i]})
+            insert(i)
+            i++
+        }
+    }
+    return symbols
+}
+
+// vp8lPrefixEncode convierte una longitud o distancia (>= 1) en código prefijo y bits extra
+func vp8lPrefixEncode(value int) (code int, extraBits uint, extra uint32) {
+    d := value - 1
+    if d < 2 {
+        return d, 0, 0
+    }
+    highest := 0
+    for v := d; v > 1; v >>= 1 {
+        highest++
+    }
+    second := (d >> (highest - 1)) & 1
+    extraBits = uint(highest - 1)
+    extra = uint32(d & ((1 << extraBits) - 1))
+    return 2*highest + second, extraBits, extra
+}
+
+// vp8lBuildPrefixCode construye un código Huffman canónico de longitud limitada
+func vp8lBuildPrefixCode(hist []uint32, maxLength int) *vp8lPrefixCode {
+    code := &vp8lPrefixCode{
+        lengths: vp8lCodeLengths(hist, maxLength),
+        codes:   make([]uint16, len(hist)),
+        bits:    make([]uint8, len(hist)),
+    }
+    
+    used := 0
+    for _, l := range code.lengths {
+        if l > 0 {
+            used++
+        }
+    }
+    // Con un único símbolo el decodificador no lee ningún bit
+    if used <= 1 {
+        return code
+    }
+    
+    var count [vp8lMaxCodeLength + 1]int
+    for _, l := range code.lengths {
+        count[l]++
+    }
+    count[0] = 0
+    var next [vp8lMaxCodeLength + 2]int
+    c := 0
+    for bits := 1; bits <= vp8lMaxCodeLength; bits++ {
+        c = (c + count[bits-1]) << 1
+        next[bits] = c
+    }
+    for symbol, l := range code.lengths {
+        if l == 0 {
+            continue
+        }
+        value := next[l]
+        next[l]++
+        reversed := 0
+        for b := 0; b < int(l); b++ {
+            reversed = reversed<<1 | (value>>b)&1
+        }
+        code.codes[symbol] = uint16(reversed)
+        code.bits[symbol] = l
+    }
+    return code
+}
+
+// vp8lCodeLengths calcula longitudes Huffman; si superan maxLength aplana el
+// histograma y vuelve a intentarlo
+func vp8lCodeLengths(hist []uint32, maxLength int) []uint8 {
+    weights := make([]uint64, len(hist))
+    for i, h := range hist {
+        weights[i] = uint64(h)
+    }
+    for {
+        lengths, depth := huffmanLengths(weights)
+        if depth <= maxLength {
+            return lengths
+        }
+        for i, w := range weights {
+            if w > 0 {
+                weights[i] = w>>1 | 1
+            }
+        }
+    }
+}
+
+// huffmanLengths devuelve la profundidad de cada símbolo en un árbol de Huffman
+func huffmanLengths(weights []uint64) ([]uint8, int) {
+    type node struct {
+        weight      uint64
+        left, right int
+    }
+    lengths := make([]uint8, len(weights))
+    nodes := make([]node, 0, 2*len(weights))
+    active := make([]int, 0, len(weights))
+    for symbol, w := range weights {
+        if w > 0 {
+            nodes = append(nodes, node{weight: w, left: -1, right: symbol})
+            active = append(active, len(nodes)-1)
+        }
+    }
+    switch len(active) {
+    case 0:
+        return lengths, 0
+    case 1:
+        lengths[nodes[0].right] = 1
+        return lengths, 1
+    }
+    
+    popMin := func() int {
+        best := 0
+        for i := 1; i < len(active); i++ {
+            if nodes[active[i]].weight < nodes[active[best]].weight {
+                best = i
+            }
+        }
+        idx := active[best]
+        active = append(active[:best], active[best+1:]...)
+        return idx
+    }
+    for len(active) > 1 {
+        a, b := popMin(), popMin()
+        nodes = append(nodes, node{weight: nodes[a].weight + nodes[b].weight, left: a, right: b})
+        active = append(active, len(nodes)-1)
+    }
+    
+    maxDepth := 0
+    var walk func(idx, depth int)
+    walk = func(idx, depth int) {
+        n := nodes[idx]
+        if n.left < 0 {
+            lengths[n.right] = uint8(depth)
+            if depth > maxDepth {
+                maxDepth = depth
+            }
+            return
+        }
+        walk(n.left, depth+1)
+        walk(n.right, depth+1)
+    }
+    walk(active[0], 0)
+    return lengths, maxDepth
+}
+
+// writePrefixCode transmite un código prefijo, usando la forma simple cuando es posible
+func (bw *vp8lBitWriter) writePrefixCode(code *vp8lPrefixCode) {
+    symbols := make([]int, 0, 2)
+    for symbol, l := range code.lengths {
+        if l > 0 {
+            symbols = append(symbols, symbol)
+        }
+    }
+    if len(symbols) == 0 {
+        symbols = append(symbols, 0)
+    }
+    
+    if len(symbols) <= 2 && symbols[len(symbols)-1] < 256 {
+        bw.writeBits(1, 1)
+        bw.writeBits(uint32(len(symbols)-1), 1)
+        if symbols[0] < 2 {
+            bw.writeBits(0, 1)
+            bw.writeBits(uint32(symbols[0]), 1)
+        } else {
+            bw.writeBits(1, 1)
+            bw.writeBits(uint32(symbols[0]), 8)
+        }
+        if len(symbols) == 2 {
+            bw.writeBits(uint32(symbols[1]), 8)
+        }
+        return
+    }
+    
+    // Codificar las longitudes con RLE (16: repetir anterior, 17/18: ceros)
+    type token struct {
+        symbol    int
+        extra     uint32
+        extraBits uint
+    }
+    tokens := make([]token, 0, len(code.lengths))
+    prev := uint8(0)
+    for i := 0; i < len(code.lengths); {
+        value := code.lengths[i]
+        run := 1
+        for i+run < len(code.lengths) && code.lengths[i+run] == value {
+            run++
+        }
+        i += run
+        
+        if value == 0 {
+            for run >= 11 {
+                n := run
+                if n > 138 {
+                    n = 138
+                }
+                tokens = append(tokens, token{18, uint32(n - 11), 7})
+                run -= n
+            }
+            if run >= 3 {
+                tokens = append(tokens, token{17, uint32(run - 3), 3})
+                run = 0
+            }
+            for ; run > 0; run-- {
+                tokens = append(tokens, token{symbol: 0})
+            }
+            continue
+        }
+        
+        if value != prev {
+            tokens = append(tokens, token{symbol: int(value)})
+            prev = value
+            run--
+        }
+        for run >= 3 {
+            n := run
+            if n > 6 {
+                n = 6
+            }
+            tokens = append(tokens, token{16, uint32(n - 3), 2})
+            run -= n
+        }
+        for ; run > 0; run-- {
+            tokens = append(tokens, token{symbol: int(value)})
+        }
+    }
+    
+    clHist := make([]uint32, len(vp8lCodeLengthOrder))
+    for _, t := range tokens {
+        clHist[t.symbol]++
+    }
+    clCode := vp8lBuildPrefixCode(clHist, vp8lMaxCLCodeLength)
+    
+    numCodes := 4
+    for i, symbol := range vp8lCodeLengthOrder {
+        if clCode.lengths[symbol] > 0 && i+1 > numCodes {
+            numCodes = i + 1
+        }
+    }
+    
+    bw.writeBits(0, 1)
+    bw.writeBits(uint32(numCodes-4), 4)
+    for _, symbol := range vp8lCodeLengthOrder[:numCodes] {
+        bw.writeBits(uint32(clCode.lengths[symbol]), 3)
+    }
+    bw.writeBits(0, 1) // se transmiten todas las longitudes
+    for _, t := range tokens {
+        bw.writeSymbol(clCode, t.symbol)
+        bw.writeBits(t.extra, t.extraBits)
+    }
+}
@@ -0,0 +1,73 @@
+// iconexporter/webp_test.go
+package iconexporter
+
+import (
+    "bytes"
+    "fmt"
+    "image"
+    "testing"
+
+    "golang.org/x/image/webp"
+)
+
+func TestEncodeWebPRoundTrip(t *testing.T) {
+    sizes := [][2]int{{1, 1}, {16, 16}, {37, 13}, {256, 200}}
+    patterns := []int{patternNoise, patternFewColors, patternSolid, patternBands}
+    for _, quality := range []int{0, 50, 100} {
+        for _, size := range sizes {
+            for _, pattern := range patterns {
+                name := fmt.Sprintf("q%d/%dx%d/patrón%d", quality, size[0], size[1], pattern)
+                t.Run(name, func(t *testing.T) {
+                    img := testImage(size[0], size[1], pattern)
+                    var buf bytes.Buffer
+                    if err := encodeWebP(&buf, img, map[string]interface{}{"quality": quality}); err != nil {
+                        t.Fatal(err)
+                    }
+                    decoded, err := webp.Decode(&buf)
+                    if err != nil {
+                        t.Fatal(err)
+                    }
+                    sameNRGBA(t, img, decoded)
+                })
+            }
+        }
+    }
+}
+
+func TestEncodeWebPErrors(t *testing.T) {
+    tests := []struct {
+        name    string
+        img     image.Image
+        options map[string]interface{}
+    }{
+        {"con pérdida", testImage(4, 4, patternSolid), map[string]interface{}{"lossless": false}},
+        {"calidad negativa", testImage(4, 4, patternSolid), map[string]interface{}{"quality": -1}},
+        {"calidad mayor de 100", testImage(4, 4, patternSolid), map[string]interface{}{"quality": 101}},
+        {"demasiado ancha", image.NewNRGBA(image.Rect(0, 0, vp8lMaxDimension+1, 1)), nil},
+        {"vacía", image.NewNRGBA(image.Rect(0, 0, 0, 0)), nil},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if err := encodeWebP(&bytes.Buffer{}, tt.img, tt.options); err == nil {
+                t.Fatal("se esperaba un error")
+            }
+        })
+    }
+}
+
+func TestEncodeWebPContainer(t *testing.T) {
+    var buf bytes.Buffer
+    if err := encodeWebP(&buf, testImage(3, 3, patternNoise), nil); err != nil {
+        t.Fatal(err)
+    }
+    data := buf.Bytes()
+    if string(data[0:4]) != "RIFF" || string(data[8:16]) != "WEBPVP8L" {
+        t.Fatalf("cabecera inesperada: %q", data[:16])
+    }
+    if len(data)%2 != 0 {
+        t.Fatalf("el contenedor RIFF debe tener tamaño par: %d", len(data))
+    }
+    if riffSize := int(data[4]) | int(data[5])<<8 | int(data[6])<<16 | int(data[7])<<24; riffSize != len(data)-8 {
+        t.Fatalf("tamaño RIFF %d, se esperaba %d", riffSize, len(data)-8)
+    }
+}
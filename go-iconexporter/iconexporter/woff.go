@@ -0,0 +1,203 @@
+// iconexporter/woff.go
+package iconexporter
+
+import (
+    "bytes"
+    "compress/zlib"
+    "encoding/binary"
+    "fmt"
+)
+
+// sfntTable es una tabla leída del directorio de una fuente TrueType
+type sfntTable struct {
+    Tag      string
+    Checksum uint32
+    Data     []byte
+}
+
+// Etiquetas conocidas de WOFF2 (índice = valor en el directorio)
+var woff2KnownTags = []string{
+    "cmap", "head", "hhea", "hmtx", "maxp", "name", "OS/2", "post", "cvt ", "fpgm",
+    "glyf", "loca", "prep", "CFF ", "VORG", "EBDT", "EBLC", "gasp", "hdmx", "kern",
+    "LTSH", "PCLT", "VDMX", "vhea", "vmtx", "BASE", "GDEF", "GPOS", "GSUB", "EBSC",
+    "JSTF", "MATH", "CBDT", "CBLC", "COLR", "CPAL", "SVG ", "sbix", "acnt", "avar",
+    "bdat", "bloc", "bsln", "cvar", "fdsc", "feat", "fmtx", "fvar", "gvar", "hsty",
+    "just", "lcar", "mort", "morx", "opbd", "prop", "trak", "Zapf", "Silf", "Glat",
+    "Gloc", "Feat", "Sill",
+}
+
+// parseSfnt lee el directorio de tablas de una fuente TrueType
+func parseSfnt(font []byte) (uint32, []sfntTable, error) {
+    if len(font) < 12 {
+        return 0, nil, fmt.Errorf("fuente no válida")
+    }
+    flavor := binary.BigEndian.Uint32(font)
+    numTables := int(binary.BigEndian.Uint16(font[4:]))
+    if len(font) < 12+16*numTables {
+        return 0, nil, fmt.Errorf("directorio de tablas incompleto")
+    }
+    
+    tables := make([]sfntTable, numTables)
+    for i := range tables {
+        record := font[12+16*i:]
+        offset := binary.BigEndian.Uint32(record[8:])
+        length := binary.BigEndian.Uint32(record[12:])
+        if uint64(offset)+uint64(length) > uint64(len(font)) {
+            return 0, nil, fmt.Errorf("tabla %q fuera de rango", record[:4])
+        }
+        tables[i] = sfntTable{
+            Tag:      string(record[:4]),
+            Checksum: binary.BigEndian.Uint32(record[4:]),
+            Data:     font[offset : offset+length],
+        }
+    }
+    return flavor, tables, nil
+}
+
+// sfntSize es el tamaño de la fuente sin comprimir con las tablas alineadas
+func sfntSize(tables []sfntTable) int {
+    size := 12 + 16*len(tables)
+    for _, table := range tables {
+        size += (len(table.Data) + 3) &^ 3
+    }
+    return size
+}
+
+// encodeWOFF empaqueta la fuente en WOFF 1.0 con cada tabla comprimida con zlib
+func encodeWOFF(font []byte) ([]byte, error) {
+    flavor, tables, err := parseSfnt(font)
+    if err != nil {
+        return nil, err
+    }
+    
+    var data ttWriter
+    var directory ttWriter
+    offset := 44 + 20*len(tables)
+    for _, table := range tables {
+        var compressed bytes.Buffer
+        zw, _ := zlib.NewWriterLevel(&compressed, zlib.BestCompression)
+        if _, err := zw.Write(table.Data); err != nil {
+            return nil, err
+        }
+        if err := zw.Close(); err != nil {
+            return nil, err
+        }
+        // Si no se gana espacio la tabla se guarda sin comprimir
+        stored := compressed.Bytes()
+        if len(stored) >= len(table.Data) {
+            stored = table.Data
+        }
+        
+        directory.tag(table.Tag)
+        directory.u32(uint32(offset + len(data)))
+        directory.u32(uint32(len(stored)))
+        directory.u32(uint32(len(table.Data)))
+        directory.u32(table.Checksum)
+        data = append(data, stored...)
+        data.pad4()
+    }
+    
+    var woff ttWriter
+    woff.tag("wOFF")
+    woff.u32(flavor)
+    woff.u32(uint32(offset + len(data)))
+    woff.u16(len(tables))
+    woff.u16(0)
+    woff.u32(uint32(sfntSize(tables)))
+    woff.u16(1)
+    woff.u16(0)
+    for i := 0; i < 5; i++ {
+        woff.u32(0) // sin metadatos ni datos privados
+    }
+    woff = append(woff, directory...)
+    return append(woff, data...), nil
+}
+
+// appendUIntBase128 escribe un entero con el formato UIntBase128 de WOFF2
+func appendUIntBase128(w ttWriter, v uint32) ttWriter {
+    var groups []byte
+    for {
+        groups = append([]byte{byte(v & 0x7F)}, groups...)
+        v >>= 7
+        if v == 0 {
+            break
+        }
+    }
+    for i := 0; i < len(groups)-1; i++ {
+        groups[i] |= 0x80
+    }
+    return append(w, groups...)
+}
+
+// encodeWOFF2 empaqueta la fuente en WOFF2. Las tablas glyf y loca se guardan
+// con la transformación nula (versión 3) y todas se comprimen juntas con Brotli.
+func encodeWOFF2(font []byte) ([]byte, error) {
+    flavor, tables, err := parseSfnt(font)
+    if err != nil {
+        return nil, err
+    }
+    
+    // loca debe ir justo después de glyf en el directorio
+    ordered := make([]sfntTable, 0, len(tables))
+    var loca *sfntTable
+    for i := range tables {
+        if tables[i].Tag == "loca" {
+            loca = &tables[i]
+        }
+    }
+    for _, table := range tables {
+        if table.Tag == "loca" {
+            continue
+        }
+        ordered = append(ordered, table)
+        if table.Tag == "glyf" && loca != nil {
+            ordered = append(ordered, *loca)
+        }
+    }
+    
+    var directory, stream ttWriter
+    for _, table := range ordered {
+        flags := 63
+        for i, known := range woff2KnownTags {
+            if known == table.Tag {
+                flags = i
+                break
+            }
+        }
+        if table.Tag == "glyf" || table.Tag == "loca" {
+            flags |= 3 << 6
+        }
+        directory.u8(flags)
+        if flags&63 == 63 {
+            directory.tag(table.Tag)
+        }
+        directory = appendUIntBase128(directory, uint32(len(table.Data)))
+        stream = append(stream, table.Data...)
+    }
+    
+    compressed, err := brotliCompress(stream)
+    if err != nil {
+        return nil, err
+    }
+    
+    length := 48 + len(directory) + len(compressed)
+    padded := (length + 3) &^ 3
+    
+    var woff2 ttWriter
+    woff2.tag("wOF2")
+    woff2.u32(flavor)
+    woff2.u32(uint32(padded))
+    woff2.u16(len(ordered))
+    woff2.u16(0)
+    woff2.u32(uint32(sfntSize(tables)))
+    woff2.u32(uint32(len(compressed)))
+    woff2.u16(1)
+    woff2.u16(0)
+    for i := 0; i < 5; i++ {
+        woff2.u32(0)
+    }
+    woff2 = append(woff2, directory...)
+    woff2 = append(woff2, compressed...)
+    woff2.pad4()
+    return woff2, nil
+}
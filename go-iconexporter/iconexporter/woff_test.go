@@ -0,0 +1,158 @@
+// iconexporter/woff_test.go
+package iconexporter
+
+import (
+    "bytes"
+    "compress/zlib"
+    "encoding/binary"
+    "io"
+    "reflect"
+    "testing"
+
+    "golang.org/x/image/font/sfnt"
+)
+
+// tablesByTag indexa las tablas de una fuente por etiqueta
+func tablesByTag(t *testing.T, font []byte) map[string][]byte {
+    t.Helper()
+    _, tables, err := parseSfnt(font)
+    if err != nil {
+        t.Fatal(err)
+    }
+    byTag := map[string][]byte{}
+    for _, table := range tables {
+        byTag[table.Tag] = table.Data
+    }
+    return byTag
+}
+
+// Cada tabla del WOFF se descomprime con zlib y coincide con la del TTF
+func TestEncodeWOFF(t *testing.T) {
+    ttf, _ := testIconFont(t, true)
+    woff, err := encodeWOFF(ttf)
+    if err != nil {
+        t.Fatal(err)
+    }
+    be := binary.BigEndian
+    if string(woff[:4]) != "wOFF" || be.Uint32(woff[4:]) != 0x00010000 || int(be.Uint32(woff[8:])) != len(woff) {
+        t.Fatal("cabecera WOFF no válida")
+    }
+    
+    want := tablesByTag(t, ttf)
+    tables := map[string][]byte{}
+    total := 12 + 16*len(want)
+    for i := 0; i < int(be.Uint16(woff[12:])); i++ {
+        entry := woff[44+20*i:]
+        offset, compLength, origLength := be.Uint32(entry[4:]), be.Uint32(entry[8:]), be.Uint32(entry[12:])
+        data := woff[offset : offset+compLength]
+        if compLength < origLength {
+            zr, err := zlib.NewReader(bytes.NewReader(data))
+            if err != nil {
+                t.Fatal(err)
+            }
+            if data, err = io.ReadAll(zr); err != nil {
+                t.Fatal(err)
+            }
+        }
+        tag := string(entry[:4])
+        tables[tag] = data
+        if checksum := be.Uint32(entry[16:]); checksum != sfntChecksum(want[tag]) && tag != "head" {
+            t.Fatalf("%s: checksum %08x", tag, checksum)
+        }
+        total += (int(origLength) + 3) &^ 3
+    }
+    if !reflect.DeepEqual(tables, want) {
+        t.Fatal("las tablas del WOFF no coinciden con las del TTF")
+    }
+    if int(be.Uint32(woff[16:])) != total {
+        t.Fatalf("totalSfntSize %d, se esperaba %d", be.Uint32(woff[16:]), total)
+    }
+}
+
+// El WOFF2 se reconstruye a TTF (transformación nula) y se lee con sfnt
+func TestEncodeWOFF2(t *testing.T) {
+    ttf, _ := testIconFont(t, false)
+    woff2, err := encodeWOFF2(ttf)
+    if err != nil {
+        t.Fatal(err)
+    }
+    be := binary.BigEndian
+    if string(woff2[:4]) != "wOF2" || int(be.Uint32(woff2[8:])) != len(woff2) || len(woff2)%4 != 0 {
+        t.Fatal("cabecera WOFF2 no válida")
+    }
+    
+    // Directorio: flags, etiqueta opcional y longitud en UIntBase128
+    pos := 48
+    var tags []string
+    var lengths []int
+    for i := 0; i < int(be.Uint16(woff2[12:])); i++ {
+        flags := int(woff2[pos])
+        pos++
+        tag := ""
+        if flags&63 == 63 {
+            tag = string(woff2[pos : pos+4])
+            pos += 4
+        } else {
+            tag = woff2KnownTags[flags&63]
+        }
+        if (tag == "glyf" || tag == "loca") != (flags>>6 == 3) {
+            t.Fatalf("%s: versión de transformación %d", tag, flags>>6)
+        }
+        length := 0
+        for {
+            b := woff2[pos]
+            pos++
+            length = length<<7 | int(b&0x7F)
+            if b&0x80 == 0 {
+                break
+            }
+        }
+        tags = append(tags, tag)
+        lengths = append(lengths, length)
+    }
+    for i, tag := range tags {
+        if tag == "loca" && (i == 0 || tags[i-1] != "glyf") {
+            t.Fatal("loca debe ir justo después de glyf")
+        }
+    }
+    
+    compressed := woff2[pos : pos+int(be.Uint32(woff2[20:]))]
+    stream, err := decodeBrotli(compressed)
+    if err != nil {
+        t.Fatal(err)
+    }
+    want := tablesByTag(t, ttf)
+    for i, tag := range tags {
+        if !bytes.Equal(stream[:lengths[i]], want[tag]) {
+            t.Fatalf("la tabla %s no coincide", tag)
+        }
+        stream = stream[lengths[i]:]
+    }
+    if len(stream) != 0 {
+        t.Fatalf("%d bytes sobrantes", len(stream))
+    }
+    
+    // Reconstruir el TTF con las tablas en el orden del directorio
+    tables := map[string][]byte{}
+    for _, tag := range tags {
+        tables[tag] = want[tag]
+    }
+    if _, err := sfnt.Parse(assembleSfnt(tables)); err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestAppendUIntBase128(t *testing.T) {
+    tests := map[uint32][]byte{
+        0:          {0x00},
+        127:        {0x7F},
+        128:        {0x81, 0x00},
+        16384:      {0x81, 0x80, 0x00},
+        0xFFFFFFFF: {0x8F, 0xFF, 0xFF, 0xFF, 0x7F},
+    }
+    for value, want := range tests {
+        if got := appendUIntBase128(nil, value); !bytes.Equal(got, want) {
+            t.Errorf("%d: % x, se esperaba % x", value, got, want)
+        }
+    }
+}
@@ -0,0 +1,195 @@
+// iconexporter/xaml.go
+package iconexporter
+
+import (
+    "fmt"
+    "html"
+    "image/color"
+    "io"
+    "path/filepath"
+    "strings"
+)
+
+// XamlConfig configura el ResourceDictionary de WPF con un Geometry por icono
+// y un DrawingImage por icono y color
+type XamlConfig struct {
+    Enabled    bool   `json:"enabled"`
+    FileName   string `json:"fileName"`
+    KeyPattern string `json:"keyPattern"`
+}
+
+// xamlConfig completa la configuración con valores por defecto
+func (e *IconExporter) xamlConfig() XamlConfig {
+    cfg := e.config.Xaml
+    if cfg.FileName == "" {
+        cfg.FileName = "icons.xaml"
+    }
+    if cfg.KeyPattern == "" {
+        cfg.KeyPattern = "{collection}-{icon}"
+    }
+    return cfg
+}
+
+// xamlPathData escribe los segmentos en el minilenguaje de trayectos de XAML;
+// F0 es evenodd y F1 nonzero
+func xamlPathData(segments []pathSegment, evenOdd bool) string {
+    parts := []string{"F1"}
+    if evenOdd {
+        parts[0] = "F0"
+    }
+    for _, seg := range segments {
+        if seg.Cmd == 'Z' {
+            parts = append(parts, "Z")
+            continue
+        }
+        points := make([]string, 0, len(seg.Args)/2)
+        for i := 0; i+1 < len(seg.Args); i += 2 {
+            points = append(points, formatNumber(seg.Args[i], 3)+","+formatNumber(seg.Args[i+1], 3))
+        }
+        parts = append(parts, string(seg.Cmd)+strings.Join(points, " "))
+    }
+    return strings.Join(parts, " ")
+}
+
+// xamlColor escribe un color #AARRGGBB aplicando la opacidad
+func xamlColor(c color.NRGBA, opacity float64) string {
+    alpha := uint8(float64(c.A)*opacity + 0.5)
+    return fmt.Sprintf("#%02X%02X%02X%02X", alpha, c.R, c.G, c.B)
+}
+
+// exportXaml escribe el ResourceDictionary con todos los iconos y colores
+func (e *IconExporter) exportXaml(entries []iconEntry, colors []string) []ExportResult {
+    cfg := e.xamlConfig()
+    filePath := filepath.Join(e.config.OutputDir, cfg.FileName)
+    result := ExportResult{Format: "xaml", Path: filePath}
+    
+    warned := map[string]bool{}
+    warn := func(label string) func(message string) {
+        return func(message string) {
+            message = label + ": " + message
+            if !warned[message] {
+                warned[message] = true
+                result.Warnings = append(result.Warnings, message)
+            }
+        }
+    }
+    
+    caps := map[string]string{"butt": "Flat", "round": "Round", "square": "Square"}
+    joins := map[string]string{"miter": "Miter", "round": "Round", "bevel": "Bevel"}
+    seen := map[string]int{}
+    uniqueKey := func(key string) string {
+        if seen[key]++; seen[key] > 1 {
+            // La clave con sufijo también puede existir ya (a, a, a-2)
+            count := seen[key]
+            renamed := fmt.Sprintf("%s-%d", key, count)
+            for seen[renamed] > 0 {
+                count++
+                renamed = fmt.Sprintf("%s-%d", key, count)
+            }
+            seen[renamed]++
+            key = renamed
+        }
+        return html.EscapeString(key)
+    }
+    
+    var b strings.Builder
+    b.WriteString(`<ResourceDictionary xmlns="http://schemas.microsoft.com/winfx/2006/xaml/presentation"
+                    xmlns:x="http://schemas.microsoft.com/winfx/2006/xaml">
+`)
+    images := 0
+    for _, entry := range entries {
+        label := entry.Collection + ":" + entry.Name
+        fmt.Fprintf(&b, "\n  <!-- %s -->\n", html.EscapeString(label))
+        
+        // Silueta sin color para usar en <Path Data="{StaticResource ...}">
+        shapes, box, err := flattenSvg(e.prepareSvgBuffer(entry.Icon, entry.Icon.Width, entry.Icon.Height, "", ""), e.config.DefaultColor, warn(label))
+        if err != nil {
+            warn(label)(err.Error())
+            continue
+        }
+        // La silueta solo admite una regla de relleno y no incluye los trazos
+        var silhouette []pathSegment
+        evenOdd, nonZero, stroked := 0, 0, false
+        for _, shape := range shapes {
+            stroked = stroked || !shape.Stroke.None
+            if shape.Fill.None {
+                continue
+            }
+            silhouette = append(silhouette, viewBoxPath(shape.Segments, box)...)
+            if shape.EvenOdd {
+                evenOdd++
+            } else {
+                nonZero++
+            }
+        }
+        if len(silhouette) == 0 {
+            warn(label)("sin formas rellenas (solo trazos), se omite la Geometry")
+        } else {
+            if evenOdd > 0 && nonZero > 0 {
+                warn(label)("reglas de relleno evenodd y nonzero mezcladas, la Geometry usa nonzero y puede perder huecos")
+            }
+            if stroked {
+                warn(label)("los trazos no forman parte de la Geometry")
+            }
+            key := e.spriteID(cfg.KeyPattern+"-geometry", entry.Collection, entry.Name, "", false)
+            fmt.Fprintf(&b, "  <Geometry x:Key=\"%s\">%s</Geometry>\n", uniqueKey(key), xamlPathData(silhouette, nonZero == 0))
+        }
+        
+        for _, col := range colors {
+            svg := e.prepareSvgBuffer(entry.Icon, entry.Icon.Width, entry.Icon.Height, col, "")
+            shapes, box, err := flattenSvg(svg, col, warn(label))
+            if err != nil {
+                warn(label)(err.Error())
+                continue
+            }
+            
+            // El color va antes del sufijo para que las claves de un icono queden juntas
+            pattern := cfg.KeyPattern
+            if len(colors) > 1 && !strings.Contains(pattern, "{color}") {
+                pattern += "-{color}"
+            }
+            key := e.spriteID(pattern+"-image", entry.Collection, entry.Name, col, false)
+            fmt.Fprintf(&b, "  <DrawingImage x:Key=\"%s\">\n", uniqueKey(key))
+            b.WriteString("    <DrawingImage.Drawing>\n      <DrawingGroup>\n")
+            // Rectángulo transparente para que la imagen ocupe todo el viewBox
+            fmt.Fprintf(&b, "        <GeometryDrawing Brush=\"Transparent\" Geometry=\"M0,0 H%s V%s H0 Z\" />\n",
+                formatNumber(box[2], 3), formatNumber(box[3], 3))
+            for _, shape := range shapes {
+                data := xamlPathData(viewBoxPath(shape.Segments, box), shape.EvenOdd)
+                brush := ""
+                if !shape.Fill.None {
+                    brush = fmt.Sprintf(" Brush=\"%s\"", xamlColor(shape.Fill.Color, shape.FillOpacity))
+                }
+                if shape.Stroke.None {
+                    fmt.Fprintf(&b, "        <GeometryDrawing%s Geometry=\"%s\" />\n", brush, data)
+                    continue
+                }
+                if dash := shape.Style.value("stroke-dasharray"); dash != "" && dash != "none" {
+                    warn(label)("stroke-dasharray no está soportado, se dibuja continuo")
+                }
+                lineCap := caps[shape.LineCap]
+                fmt.Fprintf(&b, "        <GeometryDrawing%s Geometry=\"%s\">\n", brush, data)
+                fmt.Fprintf(&b, "          <GeometryDrawing.Pen>\n            <Pen Brush=\"%s\" Thickness=\"%s\" StartLineCap=\"%s\" EndLineCap=\"%s\" LineJoin=\"%s\" MiterLimit=\"%s\" />\n          </GeometryDrawing.Pen>\n",
+                    xamlColor(shape.Stroke.Color, shape.StrokeOpacity), formatNumber(shape.StrokeWidth, 3),
+                    lineCap, lineCap, joins[shape.LineJoin], formatNumber(shape.Style.number("stroke-miterlimit"), 3))
+                b.WriteString("        </GeometryDrawing>\n")
+            }
+            b.WriteString("      </DrawingGroup>\n    </DrawingImage.Drawing>\n  </DrawingImage>\n")
+            images++
+        }
+    }
+    b.WriteString("</ResourceDictionary>\n")
+    
+    err := writeFile(filePath, func(w io.Writer) error {
+        _, err := io.WriteString(w, b.String())
+        return err
+    })
+    if err != nil {
+        fmt.Printf("❌ Error al guardar %s: %v\n", filePath, err)
+        result.Error = err.Error()
+    } else {
+        fmt.Printf("✅ Exportado: %s (%d iconos, %d imágenes)\n", filePath, len(entries), images)
+        printWarnings(result)
+    }
+    return []ExportResult{result}
+}
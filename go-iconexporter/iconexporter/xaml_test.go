@@ -0,0 +1,149 @@
+// iconexporter/xaml_test.go
+package iconexporter
+
+import (
+    "image/color"
+    "os"
+    "reflect"
+    "strings"
+    "testing"
+)
+
+func TestXamlPathData(t *testing.T) {
+    tests := []struct {
+        d       string
+        evenOdd bool
+        want    string
+    }{
+        {"M2 2h4v4z", false, "F1 M2,2 L6,2 L6,6 Z"},
+        {"M0 0h10v10H0z M2 2h4v4H2z", true, "F0 M0,0 L10,0 L10,10 L0,10 Z M2,2 L6,2 L6,6 L2,6 Z"},
+        {"M0 0C1 2 3 4 5.5 6", false, "F1 M0,0 C1,2 3,4 5.5,6"},
+        {"M0 0Q1 1 2 0", false, "F1 M0,0 Q1,1 2,0"},
+    }
+    for _, tt := range tests {
+        segments, err := parsePathData(tt.d)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if got := xamlPathData(segments, tt.evenOdd); got != tt.want {
+            t.Errorf("xamlPathData(%q) = %q, se esperaba %q", tt.d, got, tt.want)
+        }
+    }
+}
+
+func TestXamlColor(t *testing.T) {
+    tests := []struct {
+        c       color.NRGBA
+        opacity float64
+        want    string
+    }{
+        {color.NRGBA{255, 0, 0, 255}, 1, "#FFFF0000"},
+        {color.NRGBA{0x12, 0x34, 0x56, 255}, 0.5, "#80123456"},
+        {color.NRGBA{0, 0, 0, 128}, 0.5, "#40000000"},
+    }
+    for _, tt := range tests {
+        if got := xamlColor(tt.c, tt.opacity); got != tt.want {
+            t.Errorf("xamlColor(%v, %v) = %q, se esperaba %q", tt.c, tt.opacity, got, tt.want)
+        }
+    }
+}
+
+func TestExportXaml(t *testing.T) {
+    tests := []struct {
+        name     string
+        body     string
+        contains []string
+        excludes []string
+        warnings []string
+    }{
+        {
+            name: "relleno",
+            body: `<path d="M2 2h4v4z"/>`,
+            contains: []string{
+                `<Geometry x:Key="x-icono-geometry">F1 M2,2 L6,2 L6,6 Z</Geometry>`,
+                `<DrawingImage x:Key="x-icono-image">`,
+                `<GeometryDrawing Brush="#FFFF0000" Geometry="F1 M2,2 L6,2 L6,6 Z" />`,
+            },
+        },
+        {
+            name: "evenodd conserva los huecos",
+            body: `<path fill-rule="evenodd" d="M0 0h10v10H0z M2 2h4v4H2z"/>`,
+            contains: []string{`<Geometry x:Key="x-icono-geometry">F0 M0,0`},
+        },
+        {
+            name:     "solo trazos sin Geometry",
+            body:     `<path fill="none" stroke="currentColor" stroke-linecap="round" d="M2 2L6 6"/>`,
+            contains: []string{`StartLineCap="Round" EndLineCap="Round" LineJoin="Miter"`},
+            excludes: []string{"<Geometry "},
+            warnings: []string{"x:icono: sin formas rellenas (solo trazos), se omite la Geometry"},
+        },
+        {
+            name:     "reglas de relleno mezcladas",
+            body:     `<path fill-rule="evenodd" d="M0 0h10v10H0z M2 2h4v4H2z"/><path d="M12 12h2v2z"/>`,
+            contains: []string{`<Geometry x:Key="x-icono-geometry">F1 M0,0`, `Geometry="F0 M0,0`, `Geometry="F1 M12,12`},
+            warnings: []string{"x:icono: reglas de relleno evenodd y nonzero mezcladas, la Geometry usa nonzero y puede perder huecos"},
+        },
+        {
+            name:     "relleno con trazo",
+            body:     `<path stroke="blue" d="M2 2h4v4z"/>`,
+            contains: []string{`<Geometry x:Key="x-icono-geometry">F1 M2,2`, `<Pen Brush="#FF0000FF"`},
+            warnings: []string{"x:icono: los trazos no forman parte de la Geometry"},
+        },
+        {
+            name:     "extremo no válido",
+            body:     `<path fill="none" stroke="red" stroke-linecap="flat" d="M2 2L6 6"/><path d="M2 2h4v4z"/>`,
+            contains: []string{`StartLineCap="Flat" EndLineCap="Flat"`},
+            warnings: []string{
+                "x:icono: stroke-linecap no válido: flat, se usa butt",
+                "x:icono: los trazos no forman parte de la Geometry",
+            },
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            e := newTestExporter(t, Config{Xaml: XamlConfig{Enabled: true}})
+            icon := Icon{Body: tt.body, Width: 24, Height: 24, ViewBox: "0 0 24 24"}
+            results := e.exportXaml([]iconEntry{{"x", "icono", icon}}, []string{"red"})
+            if len(results) != 1 || results[0].Error != "" {
+                t.Fatalf("resultados inesperados: %+v", results)
+            }
+            data, err := os.ReadFile(results[0].Path)
+            if err != nil {
+                t.Fatal(err)
+            }
+            for _, want := range tt.contains {
+                if !strings.Contains(string(data), want) {
+                    t.Errorf("falta %q en:\n%s", want, data)
+                }
+            }
+            for _, unwanted := range tt.excludes {
+                if strings.Contains(string(data), unwanted) {
+                    t.Errorf("sobra %q en:\n%s", unwanted, data)
+                }
+            }
+            if !reflect.DeepEqual(results[0].Warnings, tt.warnings) {
+                t.Errorf("avisos %q, se esperaban %q", results[0].Warnings, tt.warnings)
+            }
+        })
+    }
+}
+
+// Con varios colores la clave de cada imagen lleva el color
+func TestExportXamlColors(t *testing.T) {
+    e := newTestExporter(t, Config{Xaml: XamlConfig{Enabled: true}})
+    icon := Icon{Body: `<path d="M2 2h4v4z"/>`, Width: 24, Height: 24, ViewBox: "0 0 24 24"}
+    results := e.exportXaml([]iconEntry{{"x", "icono", icon}}, []string{"red", "#00ff00"})
+    data, err := os.ReadFile(results[0].Path)
+    if err != nil {
+        t.Fatal(err)
+    }
+    for _, want := range []string{
+        `<DrawingImage x:Key="x-icono-red-image">`,
+        `<DrawingImage x:Key="x-icono-00ff00-image">`,
+        `Brush="#FF00FF00"`,
+    } {
+        if !strings.Contains(string(data), want) {
+            t.Errorf("falta %q en:\n%s", want, data)
+        }
+    }
+}
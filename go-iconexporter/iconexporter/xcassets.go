@@ -0,0 +1,350 @@
+// iconexporter/xcassets.go
+package iconexporter
+
+import (
+    "encoding/json"
+    "fmt"
+    "io"
+    "path/filepath"
+    "sort"
+    "strings"
+    "sync"
+    "time"
+)
+
+// XcassetsConfig configura la generación de catálogos de Xcode (.xcassets)
+type XcassetsConfig struct {
+    CatalogName     string `json:"catalogName"`
+    Format          string `json:"format"`
+    Scales          []int  `json:"scales"`
+    RenderingIntent string `json:"renderingIntent"`
+    Idiom           string `json:"idiom"`
+}
+
+// xcassetsInfo identifica al autor del Contents.json, igual que lo escribe Xcode
+type xcassetsInfo struct {
+    Author  string `json:"author"`
+    Version int    `json:"version"`
+}
+
+// xcassetsImage es una entrada de "images" en el Contents.json de un imageset
+type xcassetsImage struct {
+    Filename string `json:"filename"`
+    Idiom    string `json:"idiom"`
+    Scale    string `json:"scale,omitempty"`
+}
+
+// xcassetsProperties son las propiedades del imageset
+type xcassetsProperties struct {
+    PreservesVectorRepresentation bool   `json:"preserves-vector-representation,omitempty"`
+    TemplateRenderingIntent       string `json:"template-rendering-intent,omitempty"`
+}
+
+// xcassetsContents es el contenido de un Contents.json
+type xcassetsContents struct {
+    Images     []xcassetsImage     `json:"images,omitempty"`
+    Info       xcassetsInfo        `json:"info"`
+    Properties *xcassetsProperties `json:"properties,omitempty"`
+}
+
+// xcassetsConfig completa la configuración con valores por defecto y la valida
+func (e *IconExporter) xcassetsConfig() (XcassetsConfig, error) {
+    cfg := e.config.Xcassets
+    if cfg.CatalogName == "" {
+        cfg.CatalogName = "Icons"
+    }
+    cfg.CatalogName = strings.TrimSuffix(cfg.CatalogName, ".xcassets")
+    if cfg.Format == "" {
+        cfg.Format = "png"
+    }
+    if len(cfg.Scales) == 0 {
+        cfg.Scales = []int{1, 2, 3}
+    }
+    if cfg.Idiom == "" {
+        cfg.Idiom = "universal"
+    }
+    
+    switch cfg.Format {
+    case "png":
+        for _, scale := range cfg.Scales {
+            if scale < 1 || scale > 3 {
+                return cfg, fmt.Errorf("escala de Xcode no válida: %d (admitidas: 1, 2, 3)", scale)
+            }
+        }
+    case "svg", "pdf":
+        if _, ok := LookupFormat(cfg.Format); !ok {
+            return cfg, fmt.Errorf("formato %s no disponible para catálogos de Xcode", cfg.Format)
+        }
+    default:
+        return cfg, fmt.Errorf("formato de catálogo Xcode no válido: %s (admitidos: png, svg, pdf)", cfg.Format)
+    }
+    
+    switch cfg.RenderingIntent {
+    case "", "template", "original":
+    default:
+        return cfg, fmt.Errorf("renderingIntent no válido: %s (admitidos: template, original)", cfg.RenderingIntent)
+    }
+    return cfg, nil
+}
+
+// writeContentsJSON escribe un Contents.json con el formato de Xcode
+func writeContentsJSON(folderPath string, contents xcassetsContents) error {
+    return writeFile(filepath.Join(folderPath, "Contents.json"), func(w io.Writer) error {
+        encoder := json.NewEncoder(w)
+        encoder.SetIndent("", "  ")
+        return encoder.Encode(contents)
+    })
+}
+
+// processImageset genera el .imageset de un icono, tamaño y color dentro del catálogo
+func (e *IconExporter) processImageset(iconData IconData, collection, iconName string, size [2]int, col, imagesetName, catalogPath string, cfg XcassetsConfig) ([]ExportResult, error) {
+    var results []ExportResult
+    
+    icon, exists := iconData.Icons[iconName]
+    if !exists {
+        return nil, fmt.Errorf("icono '%s' no encontrado en %s", iconName, collection)
+    }
+    
+    folderPath := filepath.Join(catalogPath, imagesetName+".imageset")
+    if err := e.ensureOutputDir(folderPath); err != nil {
+        return nil, fmt.Errorf("error creando directorio: %w", err)
+    }
+    
+    contents := xcassetsContents{Info: xcassetsInfo{"xcode", 1}}
+    properties := xcassetsProperties{TemplateRenderingIntent: cfg.RenderingIntent}
+    
+    // Solo el PNG pasa por el rasterizador; PDF y SVG conservan el vector
+    preflight := e.rasterPreflight(e.prepareSvgBuffer(icon, icon.Width, icon.Height, col, ""))
+    save := func(fileName string, scale int) bool {
+        width, height := size[0]*scale, size[1]*scale
+        filePath := filepath.Join(folderPath, fileName)
+        result := ExportResult{
+            Collection: collection,
+            Icon:       iconName,
+            Format:     cfg.Format,
+            Path:       filePath,
+            Width:      width,
+            Height:     height,
+            Color:      col,
+        }
+        input := EncodeInput{
+            Collection: collection,
+            Icon:       iconName,
+            Color:      col,
+            Width:      width,
+            Height:     height,
+            SVG:        e.prepareSvgBuffer(icon, width, height, col, e.svgIDPrefix(collection, iconName)),
+            FileName:   fileName,
+            Warn: func(message string) {
+                result.Warnings = append(result.Warnings, message)
+            },
+        }
+        
+        if cfg.Format == "png" && !preflight(&result) {
+            results = append(results, result)
+            return false
+        }
+        err := e.saveImage(input, filePath, cfg.Format)
+        if err != nil {
+            fmt.Printf("❌ Error al guardar %s para '%s' (%dx%d, %s): %v\n",
+                fileName, iconName, width, height, col, err)
+            result.Error = err.Error()
+        } else {
+            fmt.Printf("✅ Exportado: %s\n", filePath)
+        }
+        printWarnings(result)
+        results = append(results, result)
+        return err == nil
+    }
+    
+    if cfg.Format == "png" {
+        for _, scale := range cfg.Scales {
+            fileName := imagesetName + ".png"
+            if scale > 1 {
+                fileName = fmt.Sprintf("%s@%dx.png", imagesetName, scale)
+            }
+            if save(fileName, scale) {
+                contents.Images = append(contents.Images, xcassetsImage{fileName, cfg.Idiom, fmt.Sprintf("%dx", scale)})
+            }
+        }
+    } else {
+        // Una sola escala: Xcode genera los bitmaps a partir del vector
+        fileName := imagesetName + "." + cfg.Format
+        if save(fileName, 1) {
+            contents.Images = append(contents.Images, xcassetsImage{Filename: fileName, Idiom: cfg.Idiom})
+        }
+        properties.PreservesVectorRepresentation = true
+    }
+    
+    if properties != (xcassetsProperties{}) {
+        contents.Properties = &properties
+    }
+    if err := writeContentsJSON(folderPath, contents); err != nil {
+        return results, fmt.Errorf("error escribiendo Contents.json de %s: %w", imagesetName, err)
+    }
+    
+    return results, nil
+}
+
+// ExportXcassets genera un catálogo .xcassets con un imageset por icono, tamaño
+// (en puntos) y color
+func (e *IconExporter) ExportXcassets(sizes [][2]int, colors []string) (ExportSummary, error) {
+    startTime := time.Now()
+    
+    cfg, err := e.xcassetsConfig()
+    if err != nil {
+        return ExportSummary{}, err
+    }
+    if len(sizes) == 0 {
+        sizes = [][2]int{e.config.DefaultSize}
+    }
+    if len(colors) == 0 {
+        colors = []string{e.config.DefaultColor}
+    }
+    
+    type imagesetOutcome struct {
+        results []ExportResult
+        err     error
+    }
+    
+    var totalProcessed, totalErrors, totalSkipped int
+    var allResults []ExportResult
+    var wg sync.WaitGroup
+    outcomesChan := make(chan imagesetOutcome)
+    
+    // Los catálogos se crean antes de lanzar las goroutines: si alguno falla
+    // se devuelve el error sin dejar trabajos pendientes
+    type imagesetJob struct {
+        iconData   IconData
+        collection string
+        iconName   string
+        size       [2]int
+        col        string
+        imageset   string
+        catalog    string
+    }
+    var jobs []imagesetJob
+    catalogs := map[string]bool{}
+    imagesets := map[string]bool{}
+    for _, collection := range e.config.Collections {
+        iconData, err := e.loadCollectionData(collection)
+        if err != nil {
+            fmt.Printf("❌ Error cargando colección %s: %v\n", collection, err)
+            continue
+        }
+        
+        icons := e.getIconsToProcess(iconData)
+        fmt.Printf("\n🍎 Generando catálogo Xcode: %s (%d iconos)\n", collection, len(icons))
+        
+        for _, iconName := range icons {
+            if _, exists := iconData.Icons[iconName]; !exists {
+                fmt.Printf("⚠️ Icono '%s' no encontrado en %s\n", iconName, collection)
+                totalErrors += len(sizes) * len(colors)
+                continue
+            }
+            
+            for _, size := range sizes {
+                for _, col := range colors {
+                    // El catálogo va dentro de la carpeta configurada; su raíz
+                    // necesita su propio Contents.json
+                    catalogPath := filepath.Join(
+                        e.generateFolderPath(collection, map[string]interface{}{"width": size[0], "height": size[1], "color": col}),
+                        cfg.CatalogName+".xcassets",
+                    )
+                    if !catalogs[catalogPath] {
+                        err := e.ensureOutputDir(catalogPath)
+                        if err == nil {
+                            err = writeContentsJSON(catalogPath, xcassetsContents{Info: xcassetsInfo{"xcode", 1}})
+                        }
+                        if err != nil {
+                            return ExportSummary{}, fmt.Errorf("error creando catálogo %s: %w", catalogPath, err)
+                        }
+                        catalogs[catalogPath] = true
+                    }
+                    
+                    name := iconName
+                    if len(sizes) > 1 {
+                        name = fmt.Sprintf("%s-%dx%d", name, size[0], size[1])
+                    }
+                    if len(colors) > 1 {
+                        name = fmt.Sprintf("%s-%s", name, strings.ReplaceAll(col, "#", ""))
+                    }
+                    imagesetName := e.applyCase(name, e.config.FileNaming.Case)
+                    if imagesets[filepath.Join(catalogPath, imagesetName)] {
+                        // Otra colección ya usa el nombre en este catálogo; el
+                        // nombre con la colección también puede estar ocupado
+                        base := e.applyCase(collection+"-"+name, e.config.FileNaming.Case)
+                        renamed := base
+                        for count := 2; imagesets[filepath.Join(catalogPath, renamed)]; count++ {
+                            renamed = fmt.Sprintf("%s-%d", base, count)
+                        }
+                        fmt.Printf("⚠️ Imageset %s repetido en %s, se usa %s\n", imagesetName, catalogPath, renamed)
+                        imagesetName = renamed
+                    }
+                    imagesets[filepath.Join(catalogPath, imagesetName)] = true
+                    jobs = append(jobs, imagesetJob{iconData, collection, iconName, size, col, imagesetName, catalogPath})
+                }
+            }
+        }
+    }
+    
+    for _, job := range jobs {
+        wg.Add(1)
+        go func(job imagesetJob) {
+            defer wg.Done()
+            
+            results, err := e.processImageset(job.iconData, job.collection, job.iconName, job.size, job.col, job.imageset, job.catalog, cfg)
+            outcomesChan <- imagesetOutcome{results, err}
+        }(job)
+    }
+    
+    go func() {
+        wg.Wait()
+        close(outcomesChan)
+    }()
+    
+    for outcome := range outcomesChan {
+        if outcome.err != nil {
+            fmt.Printf("❌ %v\n", outcome.err)
+            totalErrors++
+        }
+        for _, result := range outcome.results {
+            switch {
+            case result.Skipped:
+                totalSkipped++
+            case result.Error != "":
+                totalErrors++
+            default:
+                totalProcessed++
+            }
+        }
+        allResults = append(allResults, outcome.results...)
+    }
+    sort.SliceStable(allResults, func(i, j int) bool {
+        return allResults[i].Path < allResults[j].Path
+    })
+    
+    duration := time.Since(startTime).Seconds()
+    if totalSkipped > 0 {
+        fmt.Printf("\n⏭️ Omitidos por el análisis previo: %d\n", totalSkipped)
+    }
+    e.printExportSummary(totalProcessed, totalErrors, duration)
+    
+    return ExportSummary{
+        Processed: totalProcessed,
+        Errors:    totalErrors,
+        Skipped:   totalSkipped,
+        Duration:  duration,
+        Results:   allResults,
+        Sanitized: e.sanitizeReports(),
+    }, nil
+}
+
+// ExportXcassets genera el catálogo de Xcode con la configuración dada
+func ExportXcassets(config Config, sizes [][2]int, colors []string) (ExportSummary, error) {
+    exporter, err := NewIconExporter(config)
+    if err != nil {
+        return ExportSummary{}, err
+    }
+    return exporter.ExportXcassets(sizes, colors)
+}
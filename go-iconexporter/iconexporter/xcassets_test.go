@@ -0,0 +1,156 @@
+// iconexporter/xcassets_test.go
+package iconexporter
+
+import (
+    "encoding/json"
+    "os"
+    "path/filepath"
+    "reflect"
+    "runtime"
+    "testing"
+    "time"
+)
+
+func readContentsJSON(t *testing.T, path string) xcassetsContents {
+    t.Helper()
+    var contents xcassetsContents
+    data, err := os.ReadFile(path)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if err := json.Unmarshal(data, &contents); err != nil {
+        t.Fatal(err)
+    }
+    return contents
+}
+
+func TestExportXcassets(t *testing.T) {
+    tests := []struct {
+        name       string
+        cfg        XcassetsConfig
+        sizes      [][2]int
+        processed  int
+        imageset   string
+        images     []xcassetsImage
+        properties *xcassetsProperties
+    }{
+        {
+            name:      "png en tres escalas",
+            cfg:       XcassetsConfig{RenderingIntent: "template"},
+            sizes:     [][2]int{{24, 24}, {32, 32}},
+            processed: 6,
+            imageset:  "Icons.xcassets/bell-24x24.imageset",
+            images: []xcassetsImage{
+                {"bell-24x24.png", "universal", "1x"},
+                {"bell-24x24@2x.png", "universal", "2x"},
+                {"bell-24x24@3x.png", "universal", "3x"},
+            },
+            properties: &xcassetsProperties{TemplateRenderingIntent: "template"},
+        },
+        {
+            name:       "svg vectorial",
+            cfg:        XcassetsConfig{Format: "svg", CatalogName: "Vector.xcassets", Idiom: "iphone"},
+            processed:  1,
+            imageset:   "Vector.xcassets/bell.imageset",
+            images:     []xcassetsImage{{Filename: "bell.svg", Idiom: "iphone"}},
+            properties: &xcassetsProperties{PreservesVectorRepresentation: true},
+        },
+        {
+            name:      "solo 2x",
+            cfg:       XcassetsConfig{Scales: []int{2}},
+            processed: 1,
+            imageset:  "Icons.xcassets/bell.imageset",
+            images:    []xcassetsImage{{"bell@2x.png", "universal", "2x"}},
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            e := newTestExporter(t, Config{Collections: []string{"nonicons"}, Xcassets: tt.cfg})
+            summary, err := e.ExportXcassets(tt.sizes, nil)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if summary.Processed != tt.processed || summary.Errors != 0 || len(summary.Results) != tt.processed {
+                t.Fatalf("resumen inesperado: %+v", summary)
+            }
+            
+            folder := filepath.Join(e.config.OutputDir, tt.imageset)
+            contents := readContentsJSON(t, filepath.Join(folder, "Contents.json"))
+            if !reflect.DeepEqual(contents.Images, tt.images) || !reflect.DeepEqual(contents.Properties, tt.properties) {
+                t.Fatalf("Contents.json inesperado: %+v", contents)
+            }
+            for _, image := range tt.images {
+                if _, err := os.Stat(filepath.Join(folder, image.Filename)); err != nil {
+                    t.Fatal(err)
+                }
+            }
+            catalog := readContentsJSON(t, filepath.Join(filepath.Dir(folder), "Contents.json"))
+            if catalog.Info != (xcassetsInfo{"xcode", 1}) || len(catalog.Images) != 0 {
+                t.Fatalf("Contents.json del catálogo inesperado: %+v", catalog)
+            }
+        })
+    }
+}
+
+// Dos colecciones con el mismo icono no comparten imageset: la segunda lleva
+// el nombre de la colección. Aquí se repite la colección para forzarlo
+func TestExportXcassetsNameCollision(t *testing.T) {
+    e := newTestExporter(t, Config{Collections: []string{"nonicons", "nonicons"}, Xcassets: XcassetsConfig{Format: "svg"}})
+    summary, err := e.ExportXcassets(nil, nil)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if summary.Processed != 2 || summary.Errors != 0 {
+        t.Fatalf("resumen inesperado: %+v", summary)
+    }
+    want := []string{
+        filepath.Join(e.config.OutputDir, "Icons.xcassets", "bell.imageset", "bell.svg"),
+        filepath.Join(e.config.OutputDir, "Icons.xcassets", "nonicons-bell.imageset", "nonicons-bell.svg"),
+    }
+    for i, result := range summary.Results {
+        if result.Path != want[i] {
+            t.Fatalf("resultado %d: %s, se esperaba %s", i, result.Path, want[i])
+        }
+        if _, err := os.Stat(result.Path); err != nil {
+            t.Fatal(err)
+        }
+    }
+}
+
+func TestXcassetsConfigErrors(t *testing.T) {
+    for name, cfg := range map[string]XcassetsConfig{
+        "escala 4":           {Scales: []int{4}},
+        "formato webp":       {Format: "webp"},
+        "intención inválida": {RenderingIntent: "automatic"},
+    } {
+        t.Run(name, func(t *testing.T) {
+            if _, err := newTestExporter(t, Config{Xcassets: cfg}).ExportXcassets(nil, nil); err == nil {
+                t.Fatal("se esperaba un error")
+            }
+        })
+    }
+}
+
+// Si un catálogo no se puede crear no debe quedar ninguna goroutine bloqueada
+func TestExportXcassetsCatalogErrorDoesNotLeak(t *testing.T) {
+    e := newTestExporter(t, Config{
+        Collections:     []string{"nonicons"},
+        FolderStructure: FolderStructureConfig{Enabled: true, Pattern: "{size}"},
+    })
+    // Un archivo ocupa el lugar de la carpeta del segundo tamaño
+    if err := os.WriteFile(filepath.Join(e.config.OutputDir, "32x32"), nil, 0644); err != nil {
+        t.Fatal(err)
+    }
+    
+    before := runtime.NumGoroutine()
+    if _, err := e.ExportXcassets([][2]int{{24, 24}, {32, 32}}, nil); err == nil {
+        t.Fatal("se esperaba un error al crear el catálogo")
+    }
+    time.Sleep(50 * time.Millisecond)
+    if after := runtime.NumGoroutine(); after > before {
+        t.Fatalf("%d goroutines antes y %d después", before, after)
+    }
+    if _, err := os.Stat(filepath.Join(e.config.OutputDir, "24x24", "Icons.xcassets", "bell-24x24.imageset")); !os.IsNotExist(err) {
+        t.Fatal("no se debe exportar nada si falla un catálogo")
+    }
+}